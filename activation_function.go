@@ -57,6 +57,57 @@ func activate(function string, input float64) (output float64) {
 	return
 }
 
+// isActivationFunction returns true if the function is one of the known activation functions.
+func isActivationFunction(function string) bool {
+	switch function {
+	case ACTIVATION_SIGMOID,
+		ACTIVATION_BIPOLAR_SIGMOID,
+		ACTIVATION_GAUSSIAN,
+		ACTIVATION_INVERSE,
+		ACTIVATION_SINE,
+		ACTIVATION_COSINE,
+		ACTIVATION_TANGENT,
+		ACTIVATION_HYPERBOLIC_TANGENT,
+		ACTIVATION_RAMP,
+		ACTIVATION_STEP,
+		ACTIVATION_SPIKE:
+		return true
+	}
+	return false
+}
+
+// softmax squashes a group of values so each is between 0.0 and 1.0 and together they sum to 1.0. Larger values get
+// exponentially larger shares of the total.
+// Reference: https://en.wikipedia.org/wiki/Softmax_function
+func softmax(inputs []float64) (outputs []float64) {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	// The math.Exp() chokes on large inputs. Shifting every input down by the largest input gives the same
+	// result but keeps every exponent at 0.0 or below.
+	var max float64 = inputs[0]
+	for _, input := range inputs {
+		if input > max {
+			max = input
+		}
+	}
+
+	// Exponentiate each value and total them.
+	var total float64
+	for _, input := range inputs {
+		var exp float64 = math.Exp(input - max)
+		outputs = append(outputs, exp)
+		total += exp
+	}
+
+	// Each value becomes its share of the total.
+	for i := range outputs {
+		outputs[i] /= total
+	}
+	return outputs
+}
+
 // activationSigmoid is the sigmoid activation function. It graduall curves from 0.0 to 1.0 in an "S" shape.
 // Reference: http://en.wikipedia.org/wiki/Sigmoid_function
 // Reference: http://www.computing.dcu.ie/~humphrys/Notes/Neural/sigmoid.html
@@ -118,3 +118,29 @@ func (s *ActivationFunctionSuite) Test_Activate(c *C) {
 	// Invalid parameters.
 	c.Assert(func() { activate("BOOGA", 0.0) }, Panics, `Unknown activation function: 'BOOGA'`)
 }
+
+func (s *ActivationFunctionSuite) Test_IsActivationFunction(c *C) {
+	c.Check(isActivationFunction(ACTIVATION_SIGMOID), Equals, true)
+	c.Check(isActivationFunction(ACTIVATION_SPIKE), Equals, true)
+	c.Check(isActivationFunction(""), Equals, false)
+	c.Check(isActivationFunction("BOOGA"), Equals, false)
+}
+
+func (s *ActivationFunctionSuite) Test_Softmax(c *C) {
+
+	// Nothing to squash.
+	c.Check(softmax(nil), IsNil)
+
+	// Equal values share equally.
+	c.Check(softmax([]float64{3.0, 3.0}), DeepEquals, []float64{0.5, 0.5})
+
+	// Larger values get a larger share, and the shares sum to 1.0.
+	var outputs []float64 = softmax([]float64{1.0, 2.0, 3.0})
+	c.Check(int64(outputs[0]*10000.0), Equals, int64(900))  // e^1 / (e^1 + e^2 + e^3)
+	c.Check(int64(outputs[1]*10000.0), Equals, int64(2447)) // e^2 / (e^1 + e^2 + e^3)
+	c.Check(int64(outputs[2]*10000.0), Equals, int64(6652)) // e^3 / (e^1 + e^2 + e^3)
+	c.Check(math.Abs(outputs[0]+outputs[1]+outputs[2]-1.0) < 0.0000001, Equals, true)
+
+	// Extreme values do not choke.
+	c.Check(softmax([]float64{100000.0, 0.0}), DeepEquals, []float64{1.0, 0.0})
+}
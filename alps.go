@@ -0,0 +1,287 @@
+package genetic
+
+import (
+	"fmt"
+	"log"
+)
+
+// alpsPopulation is the population of an ALPS experiment, a generationPopulation for each age layer.
+type alpsPopulation struct {
+	config ConfigAlps
+	inOut  NeuralNetInOut         // For creating new random neural nets.
+	layers []generationPopulation // The age layers, youngest first.
+	sorts  []alpsLayerSort        // How each layer's last sort went, for recording.
+}
+
+// alpsLayerSort is how the last sort of a single layer went.
+type alpsLayerSort struct {
+	best        string            // The details of the best member of the layer.
+	constraints constraintSummary // How well the layer kept to the scorer's constraints (before selection).
+	sorterBytes []byte            // The sorter's generation details, if it has any.
+}
+
+// newAlpsPopulation creates a well-formed ALPS population with empty layers.
+func newAlpsPopulation(config Config) alpsPopulation {
+	config.Alps.validOrPanic()
+
+	var alps alpsPopulation = alpsPopulation{
+		config: config.Alps,
+		inOut:  config.NeuralNetInOut,
+	}
+	for _, layer := range config.Alps.Layers {
+		// Each layer is a population of its own size.
+		var layerConfig ConfigPopulation = config.Population
+		layerConfig.PopulationSize = layer.Size
+		alps.layers = append(alps.layers, newPopulation(layerConfig))
+	}
+	return alps
+}
+
+// inject restarts the youngest layer with new random neural nets. The specimens already there move up a layer, if they
+// are fit enough.
+func (a *alpsPopulation) inject(sorter Sorter) {
+	a.moveUp(0, a.layers[0].DumpSpecimens(), sorter)
+	a.layers[0].PruneEmptySpecies()
+	for i := 0; i < a.config.Layers[0].Size; i++ {
+		a.layers[0].AddNeuralNet(newNeatNeuralNet(a.inOut), 0.0, 0.0, nil) // The specimen has no scores.
+	}
+}
+
+// breed fills each layer out to its size by mutation and mating. The parents come from the layer itself and the layer
+// below it, so good genetic material from younger layers can flow upward.
+func (a *alpsPopulation) breed() {
+	// Oldest first, so the parents in each layer are only the survivors of the last generation.
+	for i := len(a.layers) - 1; i >= 0; i-- {
+		var layer *generationPopulation = &a.layers[i]
+
+		// How many more specimens do we need?
+		var specimensNeeded int = layer.config.PopulationSize - len(layer.allSpecimens())
+
+		// Who are the parents?
+		var parentSpecimens []Specimen = layer.allSpecimens()
+		if i > 0 {
+			parentSpecimens = append(parentSpecimens, a.layers[i-1].allSpecimens()...)
+		}
+		if specimensNeeded <= 0 || len(parentSpecimens) == 0 {
+			continue
+		}
+
+		// Breed from the parents as a population of their own, so mating stays within species.
+		var parents generationPopulation = newPopulation(layer.config)
+		parents.AddAllSpecimens(parentSpecimens)
+		for _, child := range parents.breed(specimensNeeded) {
+			layer.AddSpecimen(child)
+		}
+	}
+}
+
+// score scores every specimen of every layer, keeping their ages.
+func (a *alpsPopulation) score(scorer Scorer, config ConfigScoring, noveltySearch *NoveltySearch) {
+
+	// Dump the specimens from the layers for scoring.
+	var layerSizes []int
+	var specimens []Specimen
+	for i := range a.layers {
+		var layerSpecimens []Specimen = a.layers[i].DumpSpecimens()
+		specimens = append(specimens, layerSpecimens...)
+		layerSizes = append(layerSizes, len(layerSpecimens))
+	}
+
+	// Score them all together, so novelty is measured across all the layers, and re-add them to their layers.
+	var scored []Specimen = scoreSpecimens(scorer, specimens, config, noveltySearch)
+	for i, size := range layerSizes {
+		for _, specimen := range scored[:size] {
+			a.layers[i].AddSpecimen(specimen)
+		}
+		scored = scored[size:]
+	}
+}
+
+// selectLayers sorts and selects within each layer. The best is the best of all the layers. Everyone is returned too, each
+// layer sorted, before selection.
+func (a *alpsPopulation) selectLayers(sorter Sorter, selector Selector) (bestScore float64, best string, constraints constraintSummary, sorted []Specimen) {
+	bestScore, best = noFeasibleBestScore(), _NO_FEASIBLE_BEST
+	a.sorts = make([]alpsLayerSort, len(a.layers))
+	for i := range a.layers {
+		var layer *generationPopulation = &a.layers[i]
+		a.sorts[i].best = "empty"
+
+		// Modify the scores of the specimens by the size of their species.
+		layer.WeightSpecies()
+
+		// Dump the specimens from the layer, ready for selection.
+		var specimens []Specimen = layer.DumpSpecimens()
+		if len(specimens) == 0 {
+			layer.PruneEmptySpecies()
+			continue
+		}
+		for _, specimen := range specimens {
+			constraints.add(specimen.ConstraintViolation)
+			a.sorts[i].constraints.add(specimen.ConstraintViolation)
+		}
+
+		// Sort and select the fittest, and put them back in the layer.
+		var layerBestScore float64
+		var layerBest string
+		var layerSorted []Specimen
+		layerBestScore, layerBest, layerSorted = sorter.Sort(specimens)
+		a.sorts[i].best = layerBest
+		a.sorts[i].sorterBytes = sorterGenerationDetails(sorter)
+		layer.AddAllSpecimens(selector.Select(layerSorted))
+		sorted = append(sorted, layerSorted...)
+
+		// Is this the best layer?
+		if isBetterScore(sorter.IsMaximize(), layerBestScore, bestScore) {
+			bestScore = layerBestScore
+			best = fmt.Sprintf("layer: %d, %s", i+1, layerBest)
+		}
+	}
+	return bestScore, best, constraints, sorted
+}
+
+// age makes every specimen a generation older. Specimens too old for their layer move up a layer (if they are fit enough).
+func (a *alpsPopulation) age(sorter Sorter) {
+	// Oldest first, so no specimen moves up twice.
+	for i := len(a.layers) - 1; i >= 0; i-- {
+		var maxAge int = a.config.Layers[i].MaxAge
+
+		var stay []Specimen
+		var tooOld []Specimen
+		for _, specimen := range a.layers[i].DumpSpecimens() {
+			specimen.Age++
+			if maxAge > 0 && specimen.Age > maxAge {
+				tooOld = append(tooOld, specimen)
+			} else {
+				stay = append(stay, specimen)
+			}
+		}
+		a.layers[i].AddAllSpecimens(stay)
+		a.layers[i].PruneEmptySpecies()
+		a.moveUp(i, tooOld, sorter)
+	}
+}
+
+// moveUp offers specimens to the layer above the layer they are in. If the layer above is then too big, only the fittest
+// stay. Specimens with no layer above are gone.
+func (a *alpsPopulation) moveUp(layerIndex int, specimens []Specimen, sorter Sorter) {
+	if layerIndex+1 >= len(a.layers) || len(specimens) == 0 {
+		return
+	}
+
+	var above *generationPopulation = &a.layers[layerIndex+1]
+	var candidates []Specimen = append(above.DumpSpecimens(), specimens...)
+	if len(candidates) > above.config.PopulationSize {
+		_, _, candidates = sorter.Sort(candidates)
+		candidates = candidates[:above.config.PopulationSize]
+	}
+	above.AddAllSpecimens(candidates)
+}
+
+// alpsLayerIsland is the island a layer is recorded as, numbered from 1. A species founder that moves up a layer leaves
+// its species behind with the same genome, so each layer is recorded apart.
+func alpsLayerIsland(layerIndex int) int { return layerIndex + 1 }
+
+// population is all the layers as a single population.
+func (a *alpsPopulation) population() (population generationPopulation) {
+	for _, layer := range a.layers {
+		population.species = append(population.species, layer.species...)
+	}
+	return population
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigAlps) validOrPanic() {
+	if len(c.Layers) < 2 {
+		log.Panicf("ALPS needs at least two layers: %d", len(c.Layers))
+	}
+	for i, layer := range c.Layers {
+		if layer.Size < 1 {
+			log.Panicf("ALPS layer %d Size must be one or more: %d", i+1, layer.Size)
+		}
+		if i == len(c.Layers)-1 {
+			if layer.MaxAge != 0 {
+				log.Panicf("ALPS last layer MaxAge must be 0 (no limit): %d", layer.MaxAge)
+			}
+		} else if layer.MaxAge < 1 || (i > 0 && layer.MaxAge <= c.Layers[i-1].MaxAge) {
+			log.Panicf("ALPS layer %d MaxAge must be one or more and more than the layer below: %d", i+1, layer.MaxAge)
+		}
+	}
+	if c.InjectEvery < 1 {
+		log.Panicf("ALPS InjectEvery must be one or more: %d", c.InjectEvery)
+	}
+}
+
+// RunAlps runs an age-layered population structure (ALPS) experiment until stopped manually or an end condition is met.
+// Old lineages with high fitness can crowd out new structures before they've had time to improve. Here specimens only
+// compete with specimens of about the same genetic age (how many generations their oldest genetic material has been
+// evolving), and new random neural nets are injected into the youngest layer every so often (see ConfigAlps).
+//
+// Each layer is a population with its own species, bred from itself and the layer below it, and sorted and selected on
+// its own (so a selector that keeps a fraction suits layers of different sizes). A specimen too old for its layer moves up
+// to the next layer if it is fitter than what is already there. Each layer's generations are recorded separately, as islands
+// numbered from 1 (the youngest).
+//
+// Reference: Hornby, G. S. (2006) ALPS: The Age-Layered Population Structure for Reducing the Problem of Premature
+// Convergence.
+func RunAlps(experimentName string, config Config, sorter Sorter, selector Selector, scorer Scorer) {
+
+	// Create the experiment.
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, selector, scorer)
+
+	// Ensure the interface defined in the config is valid.
+	experiment.config.NeuralNetInOut.validate()
+
+	// The selector must agree with the sorter on which way selection scores run.
+	validSelectionOrPanic(experiment.sorter, experiment.selector)
+
+	// Is novelty rewarded?
+	if experiment.config.NoveltySearch.K > 0 {
+		experiment.noveltySearch = NewNoveltySearch(experiment.config.NoveltySearch)
+	}
+
+	// Is each specimen scored in several trials?
+	experiment.config.Scoring.validOrPanic()
+	validAggregationOrPanic(experiment.sorter, experiment.config.Scoring)
+
+	// Champions can be remembered, but putting them back would bring old genetic material into the young layers.
+	if experiment.config.HallOfFame.ReinjectAfter > 0 {
+		log.Panicf("ALPS keeps old genetic material out of the young layers, HallOfFame ReinjectAfter cannot be used: %d", experiment.config.HallOfFame.ReinjectAfter)
+	}
+
+	// The empty layers. The first injection fills the youngest.
+	var alps alpsPopulation = newAlpsPopulation(experiment.config)
+
+	// Run a generation of the experiment.
+	experiment.run(func(generationNum uint64) (result generationResult) {
+
+		// Give the scorer the champions so far, if it wants them.
+		experiment.shareHallOfFame(experiment.scorer)
+
+		// Tell the scorer that a new generation has started.
+		experiment.scorer.GenerationStart(generationNum)
+
+		// Restart the youngest layer every so often (including the very first generation).
+		if (generationNum-1)%uint64(experiment.config.Alps.InjectEvery) == 0 {
+			alps.inject(experiment.sorter)
+		}
+
+		// Fill out, score, and select each layer, then move the old specimens up.
+		alps.breed()
+		alps.score(experiment.scorer, experiment.config.Scoring, experiment.noveltySearch)
+		result.bestScore, result.best, result.constraints, result.sorted = alps.selectLayers(experiment.sorter, experiment.selector)
+		alps.age(experiment.sorter)
+
+		result.population = alps.population()
+		return result
+	}, func(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, result generationResult) {
+		experiment.recordAlpsGenerations(alps, generationNum, bestExperimentScore, stagnantGenerationCount)
+	})
+}
+
+// recordAlpsGenerations records a single generation of each layer, each as an island of its own.
+func (e *geneticExperiment) recordAlpsGenerations(alps alpsPopulation, generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64) {
+	for i, layer := range alps.layers {
+		var best string = fmt.Sprintf("layer: %d, %s", i+1, alps.sorts[i].best)
+		e.recordIslandGeneration(alpsLayerIsland(i), e.scorer.GenerationDetails(), alps.sorts[i].sorterBytes, generationNum, bestExperimentScore, stagnantGenerationCount, best, alps.sorts[i].constraints, layer)
+	}
+}
@@ -0,0 +1,233 @@
+package genetic
+
+import (
+	"fmt"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type AlpsSuite struct{}
+
+var _ = Suite(&AlpsSuite{})
+
+// TearDownTest puts the gene ids back, the neural nets created here use up gene ids.
+func (s *AlpsSuite) TearDownTest(c *C) { setMaxGeneId(0) }
+
+// alpsTestConfig has three layers, and everyone in a single species.
+func alpsTestConfig() Config {
+	return Config{
+		NeuralNetInOut: populationTestInOut(),
+		Population:     populationTestConfig(0), // Each layer has its own size.
+		Alps: ConfigAlps{
+			Layers: []ConfigAlpsLayer{
+				ConfigAlpsLayer{Size: 4, MaxAge: 2},
+				ConfigAlpsLayer{Size: 3, MaxAge: 5},
+				ConfigAlpsLayer{Size: 2},
+			},
+			InjectEvery: 10,
+		},
+	}
+}
+
+// alpsLayerScores are the scores of each layer's specimens, in order.
+func alpsLayerScores(alps alpsPopulation) (scores [][]float64) {
+	for _, layer := range alps.layers {
+		var layerScores []float64 = []float64{}
+		for _, specimen := range layer.allSpecimens() {
+			layerScores = append(layerScores, specimen.Score)
+		}
+		scores = append(scores, layerScores)
+	}
+	return scores
+}
+
+// alpsAllSpecimens are the specimens of every layer.
+func alpsAllSpecimens(alps alpsPopulation) []Specimen {
+	var population generationPopulation = alps.population()
+	return population.allSpecimens()
+}
+
+// Add the tests.
+
+func (s *AlpsSuite) Test_InjectBreed(c *C) {
+	rand.Seed(1)
+
+	var alps alpsPopulation = newAlpsPopulation(alpsTestConfig())
+	c.Check(len(alps.layers), Equals, 3)
+	c.Check(alps.layers[1].config.PopulationSize, Equals, 3)
+	c.Check(alpsAllSpecimens(alps), IsNil)
+
+	// The youngest layer is filled with new neural nets.
+	alps.inject(NewSorterSimpleMaximize())
+	c.Check(len(alps.layers[0].allSpecimens()), Equals, 4)
+	c.Check(len(alps.layers[1].allSpecimens()), Equals, 0)
+
+	// Each layer breeds from itself and the layer below it. The oldest layer has nothing to breed from yet.
+	alps.breed()
+	c.Check(len(alps.layers[0].allSpecimens()), Equals, 4)
+	c.Check(len(alps.layers[1].allSpecimens()), Equals, 3)
+	c.Check(len(alps.layers[2].allSpecimens()), Equals, 0)
+	alps.breed()
+	c.Check(len(alps.layers[2].allSpecimens()), Equals, 2)
+	c.Check(len(alpsAllSpecimens(alps)), Equals, 9)
+	for _, specimen := range alpsAllSpecimens(alps) {
+		specimen.NeuralNet.Compute(map[string]float64{"in1": 1.0, "in2": 0.5}) // Well formed.
+	}
+}
+
+func (s *AlpsSuite) Test_ScoreSelect(c *C) {
+	rand.Seed(1)
+
+	var alps alpsPopulation = newAlpsPopulation(alpsTestConfig())
+	alps.inject(NewSorterSimpleMaximize())
+	alps.breed()
+	alps.layers[1].species[0].Specimens[0].Age = 3
+
+	// Everyone is scored together, keeping their age. The behavior test scorer scores by index.
+	alps.score(&behaviorTestScorer{}, ConfigScoring{}, nil)
+	c.Check(alpsLayerScores(alps), DeepEquals, [][]float64{
+		[]float64{0.0, 1.0, 2.0, 3.0},
+		[]float64{4.0, 5.0, 6.0},
+		[]float64{},
+	})
+	c.Check(alps.layers[1].allSpecimens()[0].Age, Equals, 3)
+
+	// The best is from the best layer.
+	var bestScore float64
+	var best string
+	var constraints constraintSummary
+	var sorted []Specimen
+	bestScore, best, constraints, sorted = alps.selectLayers(NewSorterSimpleMaximize(), &SelectorElitism{KeepCount: 2})
+	c.Check(len(sorted), Equals, 7)
+	c.Check(bestScore, Equals, 6.0)
+	c.Check(best, Equals, "layer: 2, score: 6.000000, bonus: 0.000000, speciesmembercount: 3")
+	c.Check(constraints, Equals, constraintSummary{specimens: 7, feasibleSpecimens: 7})
+	c.Check(alpsLayerScores(alps), DeepEquals, [][]float64{
+		[]float64{3.0, 2.0},
+		[]float64{6.0, 5.0},
+		[]float64{},
+	})
+	// Each layer's sort is kept for recording the layer.
+	c.Check(alps.sorts[1].best, Equals, "score: 6.000000, bonus: 0.000000, speciesmembercount: 3")
+	c.Check(alps.sorts[1].constraints, Equals, constraintSummary{specimens: 3, feasibleSpecimens: 3})
+	c.Check(alps.sorts[2].best, Equals, "empty")
+}
+
+func (s *AlpsSuite) Test_Age(c *C) {
+	var alps alpsPopulation = newAlpsPopulation(alpsTestConfig())
+	var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1}}}}
+	var specimen = func(score float64, age int) Specimen {
+		return Specimen{NeuralNet: neuralNet, Score: score, Age: age, SpeciesMemberCount: 1}
+	}
+	alps.layers[0].AddAllSpecimens([]Specimen{specimen(1.0, 0), specimen(2.0, 2)})
+	alps.layers[1].AddAllSpecimens([]Specimen{specimen(3.0, 4), specimen(4.0, 5)})
+	alps.layers[2].AddAllSpecimens([]Specimen{specimen(5.0, 9), specimen(0.5, 10)})
+
+	// Too old for a layer is up a layer, and a full layer keeps the fittest.
+	alps.age(NewSorterSimpleMaximize())
+	c.Check(alpsLayerScores(alps), DeepEquals, [][]float64{
+		[]float64{1.0},
+		[]float64{3.0, 2.0},
+		[]float64{5.0, 4.0},
+	})
+	var ages []int
+	for _, specimen := range alpsAllSpecimens(alps) {
+		ages = append(ages, specimen.Age)
+	}
+	c.Check(ages, DeepEquals, []int{1, 5, 3, 10, 6})
+
+	// Injecting moves the youngest layer up.
+	alps.inject(NewSorterSimpleMaximize())
+	c.Check(alpsLayerScores(alps)[1], DeepEquals, []float64{3.0, 2.0, 1.0})
+	c.Check(len(alps.layers[0].allSpecimens()), Equals, 4)
+}
+
+func (s *AlpsSuite) Test_Age_FounderMovesUp(c *C) {
+	var alps alpsPopulation = newAlpsPopulation(alpsTestConfig())
+	var specimen = func(weight float64, score float64, age int) Specimen {
+		var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: weight}}}}
+		return Specimen{NeuralNet: neuralNet, Score: score, Age: age, SpeciesMemberCount: 1}
+	}
+	alps.layers[0].AddAllSpecimens([]Specimen{specimen(0.5, 2.0, 2), specimen(0.7, 1.0, 0)})
+
+	// The founder moves up, and its species stays behind with the same genome.
+	alps.age(NewSorterSimpleMaximize())
+	c.Assert(alpsLayerScores(alps), DeepEquals, [][]float64{
+		[]float64{1.0},
+		[]float64{2.0},
+		[]float64{},
+	})
+	c.Check(speciesFingerprintOf(alps.layers[0].species[0]), Equals, speciesFingerprintOf(alps.layers[1].species[0]))
+
+	// Each layer is recorded as an island of its own, so the species are recorded apart.
+	var isRecorded map[string]bool = map[string]bool{}
+	for i, layer := range alps.layers {
+		for _, species := range layer.species {
+			var key string = fmt.Sprintf("%d:%s", alpsLayerIsland(i), speciesFingerprintOf(species))
+			c.Check(isRecorded[key], Equals, false)
+			isRecorded[key] = true
+		}
+	}
+	c.Check(len(isRecorded), Equals, 2)
+}
+
+func (s *AlpsSuite) Test_MateMutate_Age(c *C) {
+	var inOut NeuralNetInOut = NeuralNetInOut{Inputs: []string{"in1"}, Outputs: []string{"out"}}
+	inOut.validate()
+	var parents []Specimen = []Specimen{
+		Specimen{NeuralNet: newNeatNeuralNet(inOut), Age: 3},
+		Specimen{NeuralNet: newNeatNeuralNet(inOut), Age: 7},
+	}
+
+	// Mating is as old as the oldest parent.
+	c.Check(parents[0].mateMutate(parents, 0, ConfigMutate{MateWeight: 1}).Age, Equals, 7)
+
+	// A mutation is as old as its parent.
+	c.Check(parents[0].mateMutate(parents, 0, ConfigMutate{AlterConnectionWeight: 1}).Age, Equals, 3)
+}
+
+func (s *AlpsSuite) Test_TrackImprovement(c *C) {
+	var bestExperimentScore float64
+	var stagnantGenerationCount uint64
+	bestExperimentScore, stagnantGenerationCount = trackImprovement(true, 2.0, 1.0, 5)
+	c.Check(bestExperimentScore, Equals, 2.0)
+	c.Check(stagnantGenerationCount, Equals, uint64(0))
+	bestExperimentScore, stagnantGenerationCount = trackImprovement(true, 1.0, 1.0, 5)
+	c.Check(bestExperimentScore, Equals, 1.0)
+	c.Check(stagnantGenerationCount, Equals, uint64(6))
+	bestExperimentScore, stagnantGenerationCount = trackImprovement(false, 0.5, 1.0, 5)
+	c.Check(bestExperimentScore, Equals, 0.5)
+	c.Check(stagnantGenerationCount, Equals, uint64(0))
+
+	// A generation with no feasible specimens has no best, and is stagnant.
+	bestExperimentScore, stagnantGenerationCount = trackImprovement(false, noFeasibleBestScore(), 1.0, 5)
+	c.Check(bestExperimentScore, Equals, 1.0)
+	c.Check(stagnantGenerationCount, Equals, uint64(6))
+}
+
+func (s *AlpsSuite) Test_ConfigAlps_ValidOrPanic(c *C) {
+	var config ConfigAlps
+
+	config = alpsTestConfig().Alps
+	config.validOrPanic() // Valid.
+
+	config.Layers = config.Layers[:1]
+	c.Check(func() { config.validOrPanic() }, Panics, "ALPS needs at least two layers: 1")
+
+	config = alpsTestConfig().Alps
+	config.Layers[1].Size = 0
+	c.Check(func() { config.validOrPanic() }, Panics, "ALPS layer 2 Size must be one or more: 0")
+
+	config = alpsTestConfig().Alps
+	config.Layers[1].MaxAge = 2
+	c.Check(func() { config.validOrPanic() }, Panics, "ALPS layer 2 MaxAge must be one or more and more than the layer below: 2")
+
+	config = alpsTestConfig().Alps
+	config.Layers[2].MaxAge = 20
+	c.Check(func() { config.validOrPanic() }, Panics, "ALPS last layer MaxAge must be 0 (no limit): 20")
+
+	config = alpsTestConfig().Alps
+	config.InjectEvery = 0
+	c.Check(func() { config.validOrPanic() }, Panics, "ALPS InjectEvery must be one or more: 0")
+}
@@ -0,0 +1,57 @@
+/*
+	genome_diff shows exactly what changed between two neural nets, such as a parent and a child that outscored it. Genes
+	are aligned by gene id the same way speciation aligns them, reporting weight changes, enabled-state flips, and function
+	changes of matching genes, as well as disjoint and excess genes.
+
+	Each neural net is a json file, such as a specimen's NeuralNet pulled from recorded experiment results.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/glemzurg/go-genetic"
+	"log"
+	"os"
+)
+
+func main() {
+	var err error
+
+	// Pass the neural net files as parameters:
+	//
+	//   bin/genome_diff -from=path/to/parent.json -to=path/to/child.json
+	//   bin/genome_diff -from=path/to/parent.json -to=path/to/child.json -json
+	//   bin/genome_diff -h
+	//
+	var fromFilename *string = flag.String("from", "", "json file of the neural net to diff from (e.g. the parent)")
+	var toFilename *string = flag.String("to", "", "json file of the neural net to diff to (e.g. the child)")
+	var isJson *bool = flag.Bool("json", false, "output the diff as json instead of text")
+	flag.Parse()
+
+	var from genetic.NeatNeuralNet
+	if from, err = genetic.LoadNeatNeuralNet(*fromFilename); err != nil {
+		log.Panic(err)
+	}
+
+	var to genetic.NeatNeuralNet
+	if to, err = genetic.LoadNeatNeuralNet(*toFilename); err != nil {
+		log.Panic(err)
+	}
+
+	var diff genetic.GenomeDiff = genetic.DiffNeuralNets(from, to)
+
+	// Machine readable or person readable.
+	if *isJson {
+		var bytes []byte
+		if bytes, err = json.MarshalIndent(diff, "", "  "); err != nil {
+			log.Panic(err)
+		}
+		fmt.Println(string(bytes))
+	} else {
+		fmt.Print(diff.String())
+	}
+
+	os.Exit(0)
+}
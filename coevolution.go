@@ -0,0 +1,329 @@
+package genetic
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+)
+
+const (
+	// Who plays who in a coevolution experiment.
+	COEVOLUTION_PAIRING_ALL_VS_ALL   = "all_vs_all"   // Every specimen plays every specimen of every other population (the default).
+	COEVOLUTION_PAIRING_RANDOM       = "random"       // Every specimen plays a few random specimens of each other population.
+	COEVOLUTION_PAIRING_HALL_OF_FAME = "hall_of_fame" // Every specimen plays a few random champions of the hall of fame of each other population.
+)
+
+// Match is the scoring part of a coevolution experiment, implemented specifically by code that understands the game being
+// played. Rather than scoring a neural net on its own, it plays two neural nets from different populations against each
+// other.
+type Match interface {
+
+	// Play a single match. The player and the opponent are from different populations, numbered from 1 in the order of
+	// the config (each with its own inputs and outputs). The scores are how well each did in the match.
+	Play(player NeatNeuralNet, playerPopulation int, opponent NeatNeuralNet, opponentPopulation int) (playerScore float64, opponentScore float64)
+
+	// The match may gather extra details we want to capture for each generation.
+	GenerationStart(generationNum uint64)
+	GenerationDetails() (json []byte)
+}
+
+// HallOfFameMatch is a Match that plays against past champions (e.g. to judge progress against the best players so far).
+// If a match implements it, it is given the champions of each population's hall of fame before each generation starts.
+type HallOfFameMatch interface {
+	Match
+
+	// HallOfFame gives the neural nets of a population's hall of fame, the best first. The populations are numbered from 1
+	// in the order of the config. Empty until there are champions.
+	HallOfFame(population int, champions []NeatNeuralNet)
+}
+
+// matchScorer stands a match in as the scorer of an experiment, so it is recorded and told about generations the same as
+// any scorer. The scores themselves come from playing matches.
+type matchScorer struct {
+	Match Match // The match being played.
+}
+
+// Score is never called, scores come from the matches.
+func (s *matchScorer) Score(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (score float64, bonus float64, outcomes []float64) {
+	log.Panic("Coevolution scores come from matches")
+	return 0.0, 0.0, nil
+}
+
+// GenerationStart tells the match that a new generation has started.
+func (s *matchScorer) GenerationStart(generationNum uint64) { s.Match.GenerationStart(generationNum) }
+
+// GenerationDetails are the match's details of the generation.
+func (s *matchScorer) GenerationDetails() (json []byte) { return s.Match.GenerationDetails() }
+
+// coevolutionTally adds up the match scores of every neural net of every population.
+type coevolutionTally struct {
+	totals [][]float64 // The total of all match scores, by population then neural net.
+	counts [][]int     // How many matches were played, by population then neural net.
+}
+
+// newCoevolutionTally creates a well-formed tally with no matches played.
+func newCoevolutionTally(neuralNets [][]NeatNeuralNet) coevolutionTally {
+	var tally coevolutionTally
+	for _, populationNeuralNets := range neuralNets {
+		tally.totals = append(tally.totals, make([]float64, len(populationNeuralNets)))
+		tally.counts = append(tally.counts, make([]int, len(populationNeuralNets)))
+	}
+	return tally
+}
+
+// add tallies a single match score.
+func (t *coevolutionTally) add(population int, neuralNetIndex int, score float64) {
+	t.totals[population][neuralNetIndex] += score
+	t.counts[population][neuralNetIndex]++
+}
+
+// results are the scores of every neural net, the average of its matches. A neural net that played no matches scores 0.0.
+func (t *coevolutionTally) results() (results [][]ScoreResult) {
+	for population := range t.totals {
+		var populationResults []ScoreResult
+		for i, total := range t.totals[population] {
+			var result ScoreResult
+			if t.counts[population][i] > 0 {
+				result.Score = total / float64(t.counts[population][i])
+			}
+			populationResults = append(populationResults, result)
+		}
+		results = append(results, populationResults)
+	}
+	return results
+}
+
+// playMatches plays every match of a generation, pairing the neural nets of the populations as configured. The champions
+// are the hall of fame of each population, for "hall_of_fame" pairing. Champions are only opponents, their scores are not
+// kept.
+func playMatches(match Match, config ConfigCoevolution, neuralNets [][]NeatNeuralNet, champions [][]NeatNeuralNet) (results [][]ScoreResult) {
+	var tally coevolutionTally = newCoevolutionTally(neuralNets)
+
+	// play plays a match between two neural nets of this generation, both keeping their scores.
+	var play = func(population int, i int, opponentPopulation int, j int) {
+		var playerScore, opponentScore float64
+		playerScore, opponentScore = match.Play(neuralNets[population][i], population+1, neuralNets[opponentPopulation][j], opponentPopulation+1)
+		tally.add(population, i, playerScore)
+		tally.add(opponentPopulation, j, opponentScore)
+	}
+
+	for population := range neuralNets {
+		for opponentPopulation := range neuralNets {
+			if opponentPopulation == population {
+				continue
+			}
+
+			switch config.Pairing {
+			case "", COEVOLUTION_PAIRING_ALL_VS_ALL:
+				// Each pair of populations only plays once.
+				if opponentPopulation < population {
+					continue
+				}
+				for i := range neuralNets[population] {
+					for j := range neuralNets[opponentPopulation] {
+						play(population, i, opponentPopulation, j)
+					}
+				}
+
+			case COEVOLUTION_PAIRING_RANDOM:
+				for i := range neuralNets[population] {
+					for k := 0; k < config.Opponents; k++ {
+						play(population, i, opponentPopulation, rand.Intn(len(neuralNets[opponentPopulation])))
+					}
+				}
+
+			case COEVOLUTION_PAIRING_HALL_OF_FAME:
+				for i := range neuralNets[population] {
+					for k := 0; k < config.Opponents; k++ {
+						// Until there are champions, play the current population.
+						if len(champions[opponentPopulation]) == 0 {
+							play(population, i, opponentPopulation, rand.Intn(len(neuralNets[opponentPopulation])))
+							continue
+						}
+						var champion NeatNeuralNet = champions[opponentPopulation][rand.Intn(len(champions[opponentPopulation]))]
+						var playerScore float64
+						playerScore, _ = match.Play(neuralNets[population][i], population+1, champion, opponentPopulation+1)
+						tally.add(population, i, playerScore)
+					}
+				}
+
+			default:
+				log.Panicf("Unknown coevolution Pairing: '%s'", config.Pairing)
+			}
+		}
+	}
+	return tally.results()
+}
+
+// newCoevolutionHallsOfFame creates a hall of fame for each population, each with no champions yet. With no HallOfFame
+// configured, the halls of fame keep no champions.
+func newCoevolutionHallsOfFame(config Config, sorter Sorter, populations []*geneticIsland) (halls []*hallOfFame) {
+	for range populations {
+		halls = append(halls, newHallOfFame(config.HallOfFame.Size, sorter))
+	}
+	return halls
+}
+
+// coevolutionChampions are the neural nets of each population's hall of fame, the best first.
+func coevolutionChampions(halls []*hallOfFame) (champions [][]NeatNeuralNet) {
+	for _, hall := range halls {
+		var neuralNets []NeatNeuralNet = []NeatNeuralNet{}
+		neuralNets = append(neuralNets, hall.neuralNets()...)
+		champions = append(champions, neuralNets)
+	}
+	return champions
+}
+
+// shareCoevolutionHallsOfFame gives a match the champions of each population so far, if it wants them.
+func shareCoevolutionHallsOfFame(match Match, halls []*hallOfFame) {
+	var hallOfFameMatch HallOfFameMatch
+	var ok bool
+	if hallOfFameMatch, ok = match.(HallOfFameMatch); ok {
+		for p, champions := range coevolutionChampions(halls) {
+			hallOfFameMatch.HallOfFame(p+1, champions)
+		}
+	}
+}
+
+// validCoevolutionHallOfFameOrPanic panics if the hall of fame cannot be used the way coevolution keeps it.
+func validCoevolutionHallOfFameOrPanic(config Config) {
+	if config.HallOfFame.ReinjectAfter > 0 {
+		log.Panicf("Coevolution keeps a hall of fame for each population as opponents, HallOfFame ReinjectAfter cannot be used: %d", config.HallOfFame.ReinjectAfter)
+	}
+	if config.Coevolution.Pairing == COEVOLUTION_PAIRING_HALL_OF_FAME && config.HallOfFame.Size < 1 {
+		log.Panicf("Coevolution Pairing 'hall_of_fame' needs a HallOfFame Size: %d", config.HallOfFame.Size)
+	}
+}
+
+// newCoevolutionPopulations creates the competing populations, each with a single specimen to grow from. Each is kept as
+// an island that never has migrants, sharing the match as its scorer.
+func newCoevolutionPopulations(config Config, scorer Scorer) (populations []*geneticIsland) {
+	config.Coevolution.validOrPanic()
+
+	for i, populationConfig := range config.Coevolution.Populations {
+
+		// The population may be managed differently.
+		var configPopulation ConfigPopulation = config.Population
+		if populationConfig.Population != nil {
+			configPopulation = *populationConfig.Population
+		}
+
+		var population *geneticIsland = &geneticIsland{
+			number:     i + 1,
+			population: newPopulation(configPopulation),
+			scorer:     scorer,
+		}
+		population.population.AddNeuralNet(newNeatNeuralNet(populationConfig.NeuralNetInOut), 0.0, 0.0, nil) // The specimen has no scores.
+		populations = append(populations, population)
+	}
+	return populations
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigCoevolution) validOrPanic() {
+	if len(c.Populations) < 2 {
+		log.Panicf("Coevolution needs at least two populations: %d", len(c.Populations))
+	}
+	for i := range c.Populations {
+		c.Populations[i].NeuralNetInOut.validate()
+	}
+	switch c.Pairing {
+	case "", COEVOLUTION_PAIRING_ALL_VS_ALL:
+	case COEVOLUTION_PAIRING_RANDOM, COEVOLUTION_PAIRING_HALL_OF_FAME:
+		if c.Opponents < 1 {
+			log.Panicf("Coevolution Opponents must be one or more: %d", c.Opponents)
+		}
+	default:
+		log.Panicf("Unknown coevolution Pairing: '%s'", c.Pairing)
+	}
+}
+
+// RunCoevolution runs a competitive coevolution experiment until stopped manually or an end condition is met. Rather than
+// scoring specimens on their own, specimens of competing populations (e.g. players and opponents) play matches against each
+// other, and both populations evolve (see ConfigCoevolution). A specimen's score is the average of its matches.
+//
+// Each population has its own inputs and outputs and its own species, and is sorted and selected on its own with the
+// shared sorter and selector. Each population's generations are recorded separately under the same experiment, as if an
+// island. Scores are relative to the other populations, so whether the experiment is improving is judged by the first
+// population alone. If a HallOfFame is configured, each population keeps its own, recorded under its island.
+func RunCoevolution(experimentName string, config Config, sorter Sorter, selector Selector, match Match) {
+
+	// Create the experiment. The match is the scorer.
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, selector, &matchScorer{Match: match})
+
+	// The selector must agree with the sorter on which way selection scores run.
+	validSelectionOrPanic(experiment.sorter, experiment.selector)
+
+	// Scores come from the matches, which are already many games against many opponents.
+	if experiment.config.Scoring != (ConfigScoring{}) {
+		log.Panic("Coevolution scores by playing matches, Scoring cannot be used")
+	}
+
+	// Each population keeps its own champions, judged against the other populations (see ConfigHallOfFame).
+	validCoevolutionHallOfFameOrPanic(experiment.config)
+	experiment.isHallOfFameByPopulation = true
+
+	// The populations. Each validates its own inputs and outputs. All the populations share the gene ids.
+	var populations []*geneticIsland = newCoevolutionPopulations(experiment.config, experiment.scorer)
+
+	// The best distinct champions of each population, the opponents of "hall_of_fame" pairing.
+	var halls []*hallOfFame = newCoevolutionHallsOfFame(experiment.config, experiment.sorter, populations)
+
+	// Run a generation of the experiment.
+	experiment.run(func(generationNum uint64) (result generationResult) {
+
+		// Give the match the champions so far, if it wants them.
+		shareCoevolutionHallsOfFame(match, halls)
+
+		// Tell the match that a new generation has started.
+		experiment.scorer.GenerationStart(generationNum)
+
+		// Fill out each population to the correct size, and dump them ready for matches.
+		var neuralNets [][]NeatNeuralNet
+		for _, population := range populations {
+			population.population.FillOut()
+			neuralNets = append(neuralNets, population.population.DumpSpecimensAsNeuralNets())
+		}
+
+		// Play the matches, and re-add everyone with their scores.
+		var results [][]ScoreResult = playMatches(match, experiment.config.Coevolution, neuralNets, coevolutionChampions(halls))
+		for p, population := range populations {
+			for i, neuralNet := range neuralNets[p] {
+				population.population.AddSpecimen(newScoredSpecimen(neuralNet, results[p][i]))
+			}
+			population.population.WeightSpecies()
+		}
+
+		// Select the fittest of each population, remembering the champions before selection loses any.
+		for p, population := range populations {
+			population.selectFittest(experiment.sorter, experiment.selector)
+			halls[p].offer(population.sorted, generationNum)
+		}
+
+		// Scores are relative to the other populations, so the first population alone decides if the experiment improved.
+		result.bestScore = populations[0].bestScore
+
+		// Every population's species make up the whole population.
+		for _, population := range populations {
+			result.population.species = append(result.population.species, population.population.species...)
+		}
+		return result
+	}, func(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, result generationResult) {
+		experiment.recordCoevolutionGenerations(populations, generationNum, bestExperimentScore, stagnantGenerationCount)
+	})
+
+	// Record the champions of each population, as if an island.
+	if experiment.config.HallOfFame.Size > 0 {
+		for p, population := range populations {
+			experiment.recordHallOfFame(population.number, halls[p])
+		}
+	}
+}
+
+// recordCoevolutionGenerations records a single generation of each population.
+func (e *geneticExperiment) recordCoevolutionGenerations(populations []*geneticIsland, generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64) {
+	for _, population := range populations {
+		var best string = fmt.Sprintf("population: %d, %s", population.number, population.best)
+		e.recordIslandGeneration(population.number, population.scorer.GenerationDetails(), population.sorterBytes, generationNum, bestExperimentScore, stagnantGenerationCount, best, population.constraints, population.population)
+	}
+}
@@ -0,0 +1,199 @@
+package genetic
+
+import (
+	"fmt"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type CoevolutionSuite struct{}
+
+var _ = Suite(&CoevolutionSuite{})
+
+// TearDownTest puts the gene ids back, the neural nets created here use up gene ids.
+func (s *CoevolutionSuite) TearDownTest(c *C) { setMaxGeneId(0) }
+
+// weightTestMatch is won by the neural net with the higher first weight, by the difference. It remembers who played.
+type weightTestMatch struct {
+	plays []string
+}
+
+func (m *weightTestMatch) Play(player NeatNeuralNet, playerPopulation int, opponent NeatNeuralNet, opponentPopulation int) (playerScore float64, opponentScore float64) {
+	var playerWeight float64 = player.Genome.Genes[0].Weight
+	var opponentWeight float64 = opponent.Genome.Genes[0].Weight
+	m.plays = append(m.plays, fmt.Sprintf("%d:%.0f v %d:%.0f", playerPopulation, playerWeight, opponentPopulation, opponentWeight))
+	return playerWeight - opponentWeight, opponentWeight - playerWeight
+}
+func (m *weightTestMatch) GenerationStart(generationNum uint64) {}
+func (m *weightTestMatch) GenerationDetails() (json []byte)     { return []byte(`{"matches": 1}`) }
+
+// hallOfFameTestMatch remembers the champions it was given, by population.
+type hallOfFameTestMatch struct {
+	weightTestMatch
+	champions map[int][]NeatNeuralNet
+}
+
+func (m *hallOfFameTestMatch) HallOfFame(population int, champions []NeatNeuralNet) {
+	m.champions[population] = champions
+}
+
+// coevolutionTestNeuralNets are neural nets with the given first weights.
+func coevolutionTestNeuralNets(weights ...float64) (neuralNets []NeatNeuralNet) {
+	for _, weight := range weights {
+		neuralNets = append(neuralNets, NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: weight}}}})
+	}
+	return neuralNets
+}
+
+// coevolutionScores are the scores of each result.
+func coevolutionScores(results [][]ScoreResult) (scores [][]float64) {
+	for _, populationResults := range results {
+		var populationScores []float64
+		for _, result := range populationResults {
+			populationScores = append(populationScores, result.Score)
+		}
+		scores = append(scores, populationScores)
+	}
+	return scores
+}
+
+// Add the tests.
+
+func (s *CoevolutionSuite) Test_PlayMatches_AllVsAll(c *C) {
+	var match *weightTestMatch = &weightTestMatch{}
+	var neuralNets [][]NeatNeuralNet = [][]NeatNeuralNet{coevolutionTestNeuralNets(1, 2), coevolutionTestNeuralNets(0, 3, 6)}
+
+	// Every pair plays once, each score the average of its matches.
+	var results [][]ScoreResult = playMatches(match, ConfigCoevolution{}, neuralNets, nil)
+	c.Check(match.plays, DeepEquals, []string{
+		"1:1 v 2:0", "1:1 v 2:3", "1:1 v 2:6",
+		"1:2 v 2:0", "1:2 v 2:3", "1:2 v 2:6",
+	})
+	c.Check(coevolutionScores(results), DeepEquals, [][]float64{
+		[]float64{-2.0, -1.0},
+		[]float64{-1.5, 1.5, 4.5},
+	})
+}
+
+func (s *CoevolutionSuite) Test_PlayMatches_Random(c *C) {
+	rand.Seed(1)
+
+	var match *weightTestMatch = &weightTestMatch{}
+	var neuralNets [][]NeatNeuralNet = [][]NeatNeuralNet{coevolutionTestNeuralNets(1, 2), coevolutionTestNeuralNets(0, 3, 6)}
+
+	// Every specimen of every population picks a few opponents.
+	var results [][]ScoreResult = playMatches(match, ConfigCoevolution{Pairing: COEVOLUTION_PAIRING_RANDOM, Opponents: 2}, neuralNets, nil)
+	c.Check(len(match.plays), Equals, 2*2+3*2)
+	c.Check(len(results[0]), Equals, 2)
+	c.Check(len(results[1]), Equals, 3)
+}
+
+func (s *CoevolutionSuite) Test_PlayMatches_HallOfFame(c *C) {
+	rand.Seed(1)
+
+	var match *weightTestMatch = &weightTestMatch{}
+	var neuralNets [][]NeatNeuralNet = [][]NeatNeuralNet{coevolutionTestNeuralNets(1, 2), coevolutionTestNeuralNets(0, 3, 6)}
+	var config ConfigCoevolution = ConfigCoevolution{Pairing: COEVOLUTION_PAIRING_HALL_OF_FAME, Opponents: 1}
+
+	// Champions are only opponents, keeping no scores. Without champions, the current population plays.
+	var champions [][]NeatNeuralNet = [][]NeatNeuralNet{nil, coevolutionTestNeuralNets(10)}
+	var results [][]ScoreResult = playMatches(match, config, neuralNets, champions)
+	c.Check(len(match.plays), Equals, 5)
+	c.Check(match.plays[:2], DeepEquals, []string{"1:1 v 2:10", "1:2 v 2:10"})
+	c.Check(match.plays[2][:4], Equals, "2:0 ")
+	c.Check(match.plays[3][:4], Equals, "2:3 ")
+	c.Check(match.plays[4][:4], Equals, "2:6 ")
+	c.Check(len(results[1]), Equals, 3)
+	c.Check(results[0][0].Score <= -4.0, Equals, true) // Lost badly to the champion, whatever else it played.
+}
+
+func (s *CoevolutionSuite) Test_CoevolutionHallsOfFame(c *C) {
+	var sorter Sorter = NewSorterSimpleMaximize()
+	var populations []*geneticIsland = []*geneticIsland{&geneticIsland{number: 1}, &geneticIsland{number: 2}}
+	var halls []*hallOfFame = newCoevolutionHallsOfFame(Config{HallOfFame: ConfigHallOfFame{Size: 2}}, sorter, populations)
+	c.Assert(len(halls), Equals, 2)
+
+	// Without champions, each population has no opponents from its hall of fame.
+	c.Check(coevolutionChampions(halls), DeepEquals, [][]NeatNeuralNet{[]NeatNeuralNet{}, []NeatNeuralNet{}})
+
+	// The best distinct champions are kept, not the latest, each genome once.
+	halls[0].offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0), hallOfFameTestSpecimen(0.2, 9.0)}, 1)
+	halls[0].offer([]Specimen{hallOfFameTestSpecimen(0.2, 9.0), hallOfFameTestSpecimen(0.3, 1.0)}, 2)
+	halls[1].offer([]Specimen{hallOfFameTestSpecimen(0.4, 3.0)}, 2)
+	c.Check(hallOfFameScores(halls[0]), DeepEquals, []float64{9.0, 5.0})
+	c.Check(coevolutionChampions(halls), DeepEquals, [][]NeatNeuralNet{
+		[]NeatNeuralNet{hallOfFameTestSpecimen(0.2, 9.0).NeuralNet, hallOfFameTestSpecimen(0.1, 5.0).NeuralNet},
+		[]NeatNeuralNet{hallOfFameTestSpecimen(0.4, 3.0).NeuralNet},
+	})
+
+	// A match that wants the champions is given each population's.
+	var match *hallOfFameTestMatch = &hallOfFameTestMatch{champions: map[int][]NeatNeuralNet{}}
+	shareCoevolutionHallsOfFame(match, halls)
+	c.Check(match.champions[2], DeepEquals, []NeatNeuralNet{hallOfFameTestSpecimen(0.4, 3.0).NeuralNet})
+	c.Check(len(match.champions[1]), Equals, 2)
+
+	// Other matches are left alone.
+	shareCoevolutionHallsOfFame(&weightTestMatch{}, halls)
+}
+
+func (s *CoevolutionSuite) Test_ValidCoevolutionHallOfFameOrPanic(c *C) {
+	validCoevolutionHallOfFameOrPanic(Config{})                                                                                                                 // Valid.
+	validCoevolutionHallOfFameOrPanic(Config{HallOfFame: ConfigHallOfFame{Size: 3}, Coevolution: ConfigCoevolution{Pairing: COEVOLUTION_PAIRING_HALL_OF_FAME}}) // Valid.
+
+	c.Check(func() {
+		validCoevolutionHallOfFameOrPanic(Config{Coevolution: ConfigCoevolution{Pairing: COEVOLUTION_PAIRING_HALL_OF_FAME}})
+	}, Panics, "Coevolution Pairing 'hall_of_fame' needs a HallOfFame Size: 0")
+	c.Check(func() {
+		validCoevolutionHallOfFameOrPanic(Config{HallOfFame: ConfigHallOfFame{Size: 3, ReinjectAfter: 5}})
+	}, Panics, "Coevolution keeps a hall of fame for each population as opponents, HallOfFame ReinjectAfter cannot be used: 5")
+}
+
+func (s *CoevolutionSuite) Test_NewCoevolutionPopulations(c *C) {
+	var config Config = Config{
+		Population: ConfigPopulation{PopulationSize: 4},
+		Coevolution: ConfigCoevolution{
+			Populations: []ConfigCoevolutionPopulation{
+				ConfigCoevolutionPopulation{NeuralNetInOut: NeuralNetInOut{Inputs: []string{"in2", "in1"}, Outputs: []string{"move"}}},
+				ConfigCoevolutionPopulation{NeuralNetInOut: NeuralNetInOut{Inputs: []string{"c"}, Outputs: []string{"x", "y"}}, Population: &ConfigPopulation{PopulationSize: 6}},
+			},
+		},
+	}
+	var scorer Scorer = &matchScorer{Match: &weightTestMatch{}}
+	var populations []*geneticIsland = newCoevolutionPopulations(config, scorer)
+
+	// Each population has its own inputs and outputs, and may be managed differently.
+	c.Check(len(populations), Equals, 2)
+	c.Check(populations[0].population.allSpecimens()[0].NeuralNet.InOut.Inputs, DeepEquals, []string{"in1", "in2"})
+	c.Check(populations[1].population.allSpecimens()[0].NeuralNet.InOut.Outputs, DeepEquals, []string{"x", "y"})
+	c.Check(populations[0].population.config.PopulationSize, Equals, 4)
+	c.Check(populations[1].population.config.PopulationSize, Equals, 6)
+	c.Check(populations[1].number, Equals, 2)
+	c.Check(string(populations[1].scorer.GenerationDetails()), Equals, `{"matches": 1}`)
+}
+
+func (s *CoevolutionSuite) Test_ConfigCoevolution_ValidOrPanic(c *C) {
+	var inOut NeuralNetInOut = NeuralNetInOut{Inputs: []string{"in"}, Outputs: []string{"out"}}
+	var populations []ConfigCoevolutionPopulation = []ConfigCoevolutionPopulation{
+		ConfigCoevolutionPopulation{NeuralNetInOut: inOut},
+		ConfigCoevolutionPopulation{NeuralNetInOut: inOut},
+	}
+	var config ConfigCoevolution
+
+	config = ConfigCoevolution{Populations: populations}
+	config.validOrPanic() // Valid.
+	config = ConfigCoevolution{Populations: populations, Pairing: COEVOLUTION_PAIRING_HALL_OF_FAME, Opponents: 2}
+	config.validOrPanic() // Valid.
+
+	config = ConfigCoevolution{Populations: populations[:1]}
+	c.Check(func() { config.validOrPanic() }, Panics, "Coevolution needs at least two populations: 1")
+
+	config = ConfigCoevolution{Populations: populations, Pairing: COEVOLUTION_PAIRING_RANDOM}
+	c.Check(func() { config.validOrPanic() }, Panics, "Coevolution Opponents must be one or more: 0")
+
+	config = ConfigCoevolution{Populations: populations, Pairing: COEVOLUTION_PAIRING_HALL_OF_FAME}
+	c.Check(func() { config.validOrPanic() }, Panics, "Coevolution Opponents must be one or more: 0")
+
+	config = ConfigCoevolution{Populations: populations, Pairing: "swiss"}
+	c.Check(func() { config.validOrPanic() }, Panics, "Unknown coevolution Pairing: 'swiss'")
+}
@@ -16,7 +16,7 @@ type topologicalNode struct {
 	nodeId     string             // What node is this?
 	inputCount uint               // How many inputs is this node waiting on before it acts?
 	sinks      map[string]float64 // What nodes does this node send its output to, with what weight?
-	function   string             // If a hidden node, what is the function to run?
+	function   string             // If a hidden node (or an output with an output function), what is the function to run?
 }
 
 // addSink adds a connection.
@@ -48,14 +48,15 @@ func makeComputeTopology(inOut NeuralNetInOut, genes []neatGene) (compute comput
 	// The bias.
 	nodeMap[NODE_BIAS] = &topologicalNode{nodeId: NODE_BIAS}
 
-	// The inputs.
-	for _, in := range inOut.Inputs {
+	// The inputs, as the neural net sees them after normalization.
+	var networkInputs []string = inOut.networkInputs()
+	for _, in := range networkInputs {
 		nodeMap[in] = &topologicalNode{nodeId: in}
 	}
 
-	// The outputs.
+	// The outputs. An output may squash its value with an activation function.
 	for _, out := range inOut.Outputs {
-		nodeMap[out] = &topologicalNode{nodeId: out}
+		nodeMap[out] = &topologicalNode{nodeId: out, function: inOut.OutputFunctions[out]}
 	}
 
 	// The hidden nodes.
@@ -102,8 +103,8 @@ func makeComputeTopology(inOut NeuralNetInOut, genes []neatGene) (compute comput
 	var sunkNodes map[string]uint = map[string]uint{}
 
 	// The starting nodes are the inputs and the bias.
-	var orderedNodeIds []string = []string{NODE_BIAS}        // Start with the bias node itself.
-	orderedNodeIds = append(orderedNodeIds, inOut.Inputs...) // Add the inputs.
+	var orderedNodeIds []string = []string{NODE_BIAS}         // Start with the bias node itself.
+	orderedNodeIds = append(orderedNodeIds, networkInputs...) // Add the inputs.
 
 	// Keep looping until we have examined all the nodes.
 	// The length of the nodes will keep getting larger until we are done.
@@ -141,3 +142,36 @@ func makeComputeTopology(inOut NeuralNetInOut, genes []neatGene) (compute comput
 	// Return teh well-formed computeTopology
 	return computeTopology{orderedNodes: orderedNodeIds, nodes: nodes}, true
 }
+
+// makeLiveComputeOrder orders only the nodes that can affect an output, each after every node that feeds it, along with the
+// enabled connections feeding each node in gene order. Unlike the compute topology, the order is always the same for the same
+// neural net, so anything generated from it is too. The genes must already be known to have no circular dependencies.
+func makeLiveComputeOrder(inOut NeuralNetInOut, genes []neatGene) (orderedNodes []string, sources map[string][]neatGene) {
+
+	// What nodes feed each node?
+	sources = map[string][]neatGene{}
+	for _, gene := range genes {
+		if gene.IsEnabled == true && gene.Type == _GENE_TYPE_CONNECTION {
+			sources[gene.To] = append(sources[gene.To], gene)
+		}
+	}
+
+	// Walk backwards from each output, adding each node after everything that feeds it.
+	var isVisited map[string]bool = map[string]bool{}
+	var visit func(nodeId string)
+	visit = func(nodeId string) {
+		if isVisited[nodeId] {
+			return
+		}
+		isVisited[nodeId] = true
+		for _, gene := range sources[nodeId] {
+			visit(gene.From)
+		}
+		orderedNodes = append(orderedNodes, nodeId)
+	}
+	for _, out := range inOut.Outputs {
+		visit(out)
+	}
+
+	return orderedNodes, sources
+}
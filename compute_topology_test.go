@@ -307,3 +307,31 @@ func (s *ComputeTopologySuite) Test_MakeComputeTopology_CircularDependencyC(c *C
 	c.Assert(ok, Equals, false) // Circular dependency
 	c.Assert(compute, DeepEquals, computeTopology{})
 }
+
+func (s *ComputeTopologySuite) Test_MakeLiveComputeOrder(c *C) {
+	var inOut NeuralNetInOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2"},
+		Outputs: []string{"o1", "o2"},
+	}
+	var genes []neatGene = []neatGene{
+		neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+		neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "1", Weight: 0.1},
+		neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "1", Weight: 0.2},
+		neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: "o1", Weight: 0.3},
+		neatGene{GeneId: 5, IsEnabled: false, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o2", Weight: 0.4},
+		neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "b", To: "o2", Weight: 0.5},
+		neatGene{GeneId: 7, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_COSINE}, // Dead.
+		neatGene{GeneId: 8, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "7", Weight: 0.6},
+	}
+
+	var orderedNodes []string
+	var sources map[string][]neatGene
+	orderedNodes, sources = makeLiveComputeOrder(inOut, genes)
+	c.Check(orderedNodes, DeepEquals, []string{"i2", "i1", "1", "o1", "b", "o2"})
+	c.Check(sources, DeepEquals, map[string][]neatGene{
+		"1":  []neatGene{genes[1], genes[2]},
+		"o1": []neatGene{genes[3]},
+		"o2": []neatGene{genes[5]},
+		"7":  []neatGene{genes[7]},
+	})
+}
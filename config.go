@@ -8,10 +8,18 @@ import (
 
 // Config is the genetic-specific experiment configuration details (loaded from a json file).
 type Config struct {
-	Population     ConfigPopulation   // How should each generation's population be managed.
-	NeuralNetInOut NeuralNetInOut     // What is the interface to the neural nets in this experiment.
-	EndCondition   ConfigEndCondition // What determines when the experiment should end. If nothing, must manually stop.
-	Database       ConfigDatabase     // Database settings.
+	Population     ConfigPopulation    // How should each generation's population be managed.
+	NeuralNetInOut NeuralNetInOut      // What is the interface to the neural nets in this experiment.
+	EndCondition   ConfigEndCondition  // What determines when the experiment should end. If nothing, must manually stop.
+	Database       ConfigDatabase      // Database settings.
+	NoveltySearch  ConfigNoveltySearch // If set, how novelty is rewarded from the scorer's behaviors.
+	MapElites      ConfigMapElites     // For RunMapElites, how the archive of elites is laid out.
+	Alps           ConfigAlps          // For RunAlps, how the population is split into age layers.
+	Islands        ConfigIslands       // For RunIslands, the islands and how specimens migrate between them.
+	SteadyState    ConfigSteadyState   // For RunSteadyState, how often specimens are replaced.
+	Coevolution    ConfigCoevolution   // For RunCoevolution, the competing populations and who plays who.
+	HallOfFame     ConfigHallOfFame    // If set, the best distinct genomes ever seen are remembered.
+	Scoring        ConfigScoring       // If set, how each specimen is scored over several trials.
 }
 
 // ConfigEndCondition describes how a genetic experiment should end. If blank, then the experiment must be manually stopped.
@@ -28,11 +36,113 @@ type ConfigPopulation struct {
 	Mutate         ConfigMutate     // Rules for mating and mutating new members of the population.
 }
 
+// ConfigHallOfFame describes the hall of fame, the best distinct genomes ever seen in an experiment (ranked by the sorter).
+// Scorers that implement HallOfFameScorer are given the champions, and they can be put back into the population if it
+// stagnates (into every island, but never into ALPS layers). Coevolution keeps a hall of fame for each population instead,
+// the opponents of "hall_of_fame" pairing, and never puts them back. MAP-Elites keeps its elites instead.
+type ConfigHallOfFame struct {
+	Size          int    // How many champions are kept. If 0, no hall of fame.
+	ReinjectAfter uint64 // After this many generations without fitness improving (and every this many again), champions are put back into the population. If 0, never.
+	ReinjectCount int    // How many of the best champions are put back. If 0, all of them.
+}
+
+// ConfigScoring describes how a noisy scorer is handled, scoring each specimen over several trials rather than trusting a
+// single lucky (or unlucky) score. Coevolution scores by its matches instead, and MAP-Elites never averages over a lifetime.
+type ConfigScoring struct {
+	Trials          int     // How many times each specimen is scored each generation. If 0, once.
+	Aggregation     string  // How the trials become a single score: "mean", "median", "min" (not with a minimizing sorter), or "trimmed_mean". If blank, "mean".
+	TrimFraction    float64 // For "trimmed_mean", the fraction of trials dropped from each end, 0.0 up to (not including) 0.5.
+	LifetimeAverage bool    // If true, a specimen that survives is averaged over every trial of its lifetime, not just this generation's.
+}
+
 // ConfigDatabase describes how the database should be interacted with.
 type ConfigDatabase struct {
 	RecordEveryNthGeneration uint64 // If 0, only record final generation. Otherwise, record every nth generation.
 }
 
+// ConfigNoveltySearch describes how a novelty search rewards neural nets for behaving differently. Novelty is the mean
+// distance of a neural net's behavior to its K nearest neighbors among the rest of the population and an archive of past
+// behaviors. The scorer must report a behavior for every neural net (see ResultScorer).
+type ConfigNoveltySearch struct {
+	K                  int     // How many nearest neighbors novelty is measured against. If 0, there is no novelty search.
+	Distance           string  // The name of the behavior distance (e.g. "euclidean", "manhattan"). If blank, "euclidean".
+	ArchivePolicy      string  // How behaviors get into the archive: "threshold" (novelty above ArchiveThreshold) or "random" (with ArchiveProbability).
+	ArchiveThreshold   float64 // For the "threshold" policy, how novel a behavior must be to be archived.
+	ArchiveProbability float64 // For the "random" policy, the chance of each behavior being archived (0.0 to 1.0).
+	ArchiveMaxSize     int     // If not 0, the archive never grows beyond this size. Random behaviors are forgotten to make room.
+	Target             string  // Where novelty goes: "bonus" (added to the bonus) or "outcome" (appended as the last outcome).
+	Weight             float64 // Novelty is multiplied by this before use, negative for minimizing sorters. If 0.0, 1.0 is used.
+}
+
+// ConfigMapElites describes the archive of a MAP-Elites experiment. The scorer's behavior is the descriptor of where a
+// neural net falls in the archive, and each cell of the archive keeps the best neural net found for it.
+type ConfigMapElites struct {
+	Descriptors   []ConfigDescriptor // The range of each value of the behavior, in order.
+	Tessellation  string             // How the descriptors are divided into cells: "grid" (Bins per descriptor) or "cvt" (CvtCells cells). If blank, "grid".
+	CvtCells      int                // For "cvt", how many cells.
+	CvtSamples    int                // For "cvt", how many random points place the cells. If 0, 100 per cell.
+	CvtIterations int                // For "cvt", how many rounds of k-means place the cells. If 0, 20.
+	InitialSize   int                // How many neural nets are mutated from a single seed to start the archive.
+	BatchSize     int                // How many candidates are bred from random elites each generation.
+	Maximize      bool               // True if higher scores make better elites.
+}
+
+// ConfigAlps describes the age layers of an ALPS experiment. Each layer is a population of its own, and only holds
+// specimens up to its MaxAge. The layers are in order, youngest first, each layer's MaxAge above the last.
+type ConfigAlps struct {
+	Layers      []ConfigAlpsLayer // The age layers, youngest first.
+	InjectEvery int               // Every this many generations, the youngest layer is restarted with new random neural nets.
+}
+
+// ConfigAlpsLayer is a single age layer.
+type ConfigAlpsLayer struct {
+	Size   int // How many specimens are in the layer each generation (its population size).
+	MaxAge int // The oldest a specimen can be and stay in this layer. The last layer must be 0, no limit.
+}
+
+// ConfigIslands describes the islands of an island model experiment. Each island is a population of its own, evolving
+// at the same time as the others, and every so often the fittest specimens of each island migrate to other islands.
+type ConfigIslands struct {
+	Islands           []ConfigIsland // The islands, one for each population.
+	MigrationInterval int            // Every this many generations, specimens migrate.
+	MigrationCount    int            // How many of the fittest specimens of an island migrate to each island it sends to.
+	Topology          string         // Which islands send to which: "ring", "fully_connected", or "random". If blank, "ring".
+}
+
+// ConfigIsland is a single island, with anything it does differently from the rest of the experiment.
+type ConfigIsland struct {
+	Population *ConfigPopulation // If set, used instead of the experiment's population config.
+}
+
+// ConfigSteadyState describes how a steady-state experiment replaces specimens. Rather than a whole new generation at
+// once, the worst specimen old enough to have been judged fairly is replaced by a single offspring every so often.
+type ConfigSteadyState struct {
+	ReplaceInterval    int // Every this many evaluations (scorings of the whole population), a specimen is replaced.
+	MinimumEvaluations int // How many times a specimen must be scored before it can be replaced. If 0, any specimen.
+}
+
+// ConfigCoevolution describes the populations of a competitive coevolution experiment, and how their specimens are paired
+// up to play matches against each other.
+type ConfigCoevolution struct {
+	Populations []ConfigCoevolutionPopulation // The competing populations.
+	Pairing     string                        // Who plays who: "all_vs_all", "random", or "hall_of_fame". If blank, "all_vs_all".
+	Opponents   int                           // For "random" and "hall_of_fame", how many opponents each specimen plays from each other population.
+}
+
+// ConfigCoevolutionPopulation is a single competing population, with its own inputs and outputs.
+type ConfigCoevolutionPopulation struct {
+	NeuralNetInOut NeuralNetInOut    // The inputs and outputs of the population's neural nets, used instead of the experiment's.
+	Population     *ConfigPopulation // If set, used instead of the experiment's population config.
+}
+
+// ConfigDescriptor is the range of a single behavior descriptor.
+type ConfigDescriptor struct {
+	Name string  // The name of the descriptor, for reports.
+	Min  float64 // The lowest value expected. Lower values are treated as this.
+	Max  float64 // The highest value expected. Higher values are treated as this.
+	Bins int     // For "grid" tessellation, how many equal bins divide Min to Max.
+}
+
 // ConfigSpeciation describes how species are discovered to group specimens together by similarity.
 type ConfigSpeciation struct {
 	Threshold float64 // Two genomes with a speciation distance below this number will be members of the same species.
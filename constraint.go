@@ -0,0 +1,160 @@
+package genetic
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// The best of a sort where no specimen is feasible.
+	_NO_FEASIBLE_BEST = "no feasible specimens"
+)
+
+// Scorers with hard constraints (e.g. an illegal hand, going over a budget) report how badly a neural net broke them as a
+// constraint violation, 0.0 if it broke none. Rather than folding an arbitrary penalty into the score, the sorters apply
+// constraint-dominance:
+//
+//   - A feasible specimen (no violation) is always fitter than an infeasible one.
+//   - Two feasible specimens are compared however the sorter normally compares them.
+//   - Two infeasible specimens are compared by their violations alone, smaller being fitter.
+//
+// Reference: Deb, K. (2000) An Efficient Constraint Handling Method for Genetic Algorithms.
+
+// isFeasible is true if the specimen broke no constraints.
+func (s *Specimen) isFeasible() bool { return s.ConstraintViolation <= 0.0 }
+
+// noFeasibleBestScore is the best score of a sort where no specimen is feasible. An infeasible specimen is never the best
+// of an experiment, so it is NaN, which is never a better score (see isBetterScore).
+func noFeasibleBestScore() float64 { return math.NaN() }
+
+// isBetterScore is true if a best score beats another. A best score from a sort with no feasible specimens never beats
+// anything, and anything beats it.
+func isBetterScore(isMaximize bool, score float64, other float64) bool {
+	switch {
+	case math.IsNaN(score):
+		return false
+	case math.IsNaN(other):
+		return true
+	case isMaximize:
+		return score > other
+	}
+	return score < other
+}
+
+// compareFeasibility compares two specimens by their constraints alone. It is negative if a is fitter, positive if b is
+// fitter, and 0 if the constraints don't decide (both are feasible, or both are equally infeasible).
+func compareFeasibility(a Specimen, b Specimen) int {
+	switch {
+	case a.isFeasible() && b.isFeasible():
+		return 0
+	case a.isFeasible():
+		return -1
+	case b.isFeasible():
+		return 1
+	case a.ConstraintViolation < b.ConstraintViolation:
+		return -1
+	case a.ConstraintViolation > b.ConstraintViolation:
+		return 1
+	}
+	return 0
+}
+
+// sortConstrained applies constraint-dominance around a sorter's own sort. When any specimen is feasible, only the feasible
+// specimens are given to sortFeasible, so infeasible specimens can't skew a sort that depends on the whole population
+// (e.g. a hypervolume or a normalization), and the best comes from them. When none are, everyone is given to sortFeasible
+// and then reordered by violation, but there is no best (see noFeasibleBestScore). higherIsFitter is true if the sorter gives fitter specimens higher
+// selection scores.
+func sortConstrained(specimens []Specimen, higherIsFitter bool, sortFeasible func([]Specimen) (float64, string, []Specimen)) (bestScore float64, best string, sorted []Specimen) {
+	var feasible []Specimen
+	var infeasible []Specimen
+	for _, specimen := range specimens {
+		if specimen.isFeasible() {
+			feasible = append(feasible, specimen)
+		} else {
+			infeasible = append(infeasible, specimen)
+		}
+	}
+
+	// Without constraints, it's just the sorter.
+	if len(infeasible) == 0 {
+		return sortFeasible(specimens)
+	}
+
+	if len(feasible) == 0 {
+		_, _, sorted = sortFeasible(specimens)
+		bestScore, best = noFeasibleBestScore(), _NO_FEASIBLE_BEST
+	} else {
+		bestScore, best, sorted = sortFeasible(feasible)
+		sorted = append(sorted, infeasible...)
+	}
+	return bestScore, best, sortFeasibleFirst(sorted, higherIsFitter)
+}
+
+// sortFeasibleFirst reorders specimens a sorter has already sorted so the feasible specimens come first, in the order the
+// sorter gave them, then the infeasible specimens by their violation. The infeasible specimens' selection scores are moved
+// past the worst feasible selection score (further for bigger violations) so selectors that compare selection scores agree
+// with the order. higherIsFitter is true if the sorter gives fitter specimens higher selection scores.
+func sortFeasibleFirst(specimens []Specimen, higherIsFitter bool) []Specimen {
+	sort.Stable(byFeasibility(specimens))
+
+	// The worst selection score a feasible specimen has.
+	var worstFeasible float64
+	var feasibleCount int
+	for feasibleCount < len(specimens) && specimens[feasibleCount].isFeasible() {
+		var selectionScore float64 = specimens[feasibleCount].SelectionScore
+		if feasibleCount == 0 || (higherIsFitter && selectionScore < worstFeasible) || (!higherIsFitter && selectionScore > worstFeasible) {
+			worstFeasible = selectionScore
+		}
+		feasibleCount++
+	}
+
+	// Everyone infeasible is worse than that.
+	for i := feasibleCount; i < len(specimens); i++ {
+		var penalty float64 = 1.0 + specimens[i].ConstraintViolation
+		if higherIsFitter {
+			specimens[i].setSelectionScore(worstFeasible - penalty)
+		} else {
+			specimens[i].setSelectionScore(worstFeasible + penalty)
+		}
+	}
+
+	return specimens
+}
+
+// constraintSummary is how well a generation kept to the scorer's constraints, for recording.
+type constraintSummary struct {
+	specimens         int     // How many specimens were scored.
+	feasibleSpecimens int     // How many broke no constraints.
+	violationMin      float64 // The smallest violation in the generation (0.0 once anyone is feasible).
+	violationTotal    float64 // All the violations of the generation added together.
+}
+
+// add includes one more specimen's constraint violation in the summary.
+func (c *constraintSummary) add(violation float64) {
+	if violation <= 0.0 {
+		c.feasibleSpecimens++
+		violation = 0.0
+	}
+	if c.specimens == 0 || violation < c.violationMin {
+		c.violationMin = violation
+	}
+	c.violationTotal += violation
+	c.specimens++
+}
+
+// summarizeConstraints summarizes the constraint violations of a generation.
+func summarizeConstraints(specimens []Specimen) (summary constraintSummary) {
+	for _, specimen := range specimens {
+		summary.add(specimen.ConstraintViolation)
+	}
+	return summary
+}
+
+// byFeasibility implements sort.Interface to sort feasible specimens first, then infeasible ones ascending by violation.
+// Feasible specimens are all equal, so a stable sort keeps their order.
+// Example: sort.Stable(byFeasibility(specimens))
+type byFeasibility []Specimen
+
+func (a byFeasibility) Len() int           { return len(a) }
+func (a byFeasibility) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byFeasibility) Less(i, j int) bool { return compareFeasibility(a[i], a[j]) < 0 }
@@ -0,0 +1,142 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math"
+)
+
+// Create a suite.
+type ConstraintSuite struct{}
+
+var _ = Suite(&ConstraintSuite{})
+
+// constraintTestSpecimens are two feasible and two infeasible specimens.
+func constraintTestSpecimens() []Specimen {
+	return []Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 1},
+		Specimen{Score: 5.0, SpeciesMemberCount: 1, ConstraintViolation: 2.0},
+		Specimen{Score: 3.0, SpeciesMemberCount: 1},
+		Specimen{Score: 9.0, SpeciesMemberCount: 1, ConstraintViolation: 1.0},
+	}
+}
+
+// constraintTestOrder is the scores and selection scores of sorted specimens.
+func constraintTestOrder(sorted []Specimen) (scores []float64, selectionScores []float64) {
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+		selectionScores = append(selectionScores, specimen.SelectionScore)
+	}
+	return scores, selectionScores
+}
+
+// Add the tests.
+
+func (s *ConstraintSuite) Test_CompareFeasibility(c *C) {
+	var feasible Specimen = Specimen{}
+	var slightly Specimen = Specimen{ConstraintViolation: 0.5}
+	var badly Specimen = Specimen{ConstraintViolation: 4.0}
+
+	c.Check(feasible.isFeasible(), Equals, true)
+	c.Check(slightly.isFeasible(), Equals, false)
+
+	c.Check(compareFeasibility(feasible, feasible), Equals, 0)
+	c.Check(compareFeasibility(feasible, slightly), Equals, -1)
+	c.Check(compareFeasibility(badly, feasible), Equals, 1)
+	c.Check(compareFeasibility(slightly, badly), Equals, -1)
+	c.Check(compareFeasibility(badly, slightly), Equals, 1)
+	c.Check(compareFeasibility(badly, badly), Equals, 0)
+}
+
+func (s *ConstraintSuite) Test_SortConstrained(c *C) {
+	var bestScore float64
+	var best string
+	var sorted []Specimen
+	var scores []float64
+	var selectionScores []float64
+
+	// The best is only from the feasible specimens, even though the infeasible ones score higher.
+	bestScore, best, sorted = NewSorterSimpleMaximize().Sort(constraintTestSpecimens())
+	c.Check(bestScore, Equals, 3.0)
+	c.Check(best, Equals, "score: 3.000000, bonus: 0.000000, speciesmembercount: 1")
+	scores, selectionScores = constraintTestOrder(sorted)
+	c.Check(scores, DeepEquals, []float64{3.0, 1.0, 9.0, 5.0})
+	c.Check(selectionScores, DeepEquals, []float64{3.0, 1.0, -1.0, -2.0})
+
+	// Minimizing, the infeasible specimens have the higher selection scores.
+	bestScore, _, sorted = NewSorterSimpleMinimize().Sort(constraintTestSpecimens())
+	c.Check(bestScore, Equals, 1.0)
+	scores, selectionScores = constraintTestOrder(sorted)
+	c.Check(scores, DeepEquals, []float64{1.0, 3.0, 9.0, 5.0})
+	c.Check(selectionScores, DeepEquals, []float64{1.0, 3.0, 5.0, 6.0})
+
+	// No one is feasible. The violations decide the order, and there is no best.
+	bestScore, best, sorted = NewSorterSimpleMaximize().Sort([]Specimen{constraintTestSpecimens()[1], constraintTestSpecimens()[3]})
+	c.Check(math.IsNaN(bestScore), Equals, true)
+	c.Check(best, Equals, "no feasible specimens")
+	scores, selectionScores = constraintTestOrder(sorted)
+	c.Check(scores, DeepEquals, []float64{9.0, 5.0})
+	c.Check(selectionScores, DeepEquals, []float64{-2.0, -3.0})
+}
+
+func (s *ConstraintSuite) Test_IsBetterScore(c *C) {
+	c.Check(isBetterScore(true, 2.0, 1.0), Equals, true)
+	c.Check(isBetterScore(true, 1.0, 1.0), Equals, false)
+	c.Check(isBetterScore(false, 0.5, 1.0), Equals, true)
+	c.Check(isBetterScore(false, 2.0, 1.0), Equals, false)
+
+	// No best never beats anything, and anything beats it.
+	c.Check(isBetterScore(true, noFeasibleBestScore(), -100.0), Equals, false)
+	c.Check(isBetterScore(false, noFeasibleBestScore(), 100.0), Equals, false)
+	c.Check(isBetterScore(true, -100.0, noFeasibleBestScore()), Equals, true)
+	c.Check(isBetterScore(false, 100.0, noFeasibleBestScore()), Equals, true)
+}
+
+func (s *ConstraintSuite) Test_SortConstrained_AllSorters(c *C) {
+	var specimens []Specimen = []Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 1, Outcomes: []float64{1.0, 1.0}},
+		Specimen{Score: 2.0, SpeciesMemberCount: 1, Outcomes: []float64{9.0, 9.0}, ConstraintViolation: 1.0},
+		Specimen{Score: 3.0, SpeciesMemberCount: 1, Outcomes: []float64{2.0, 2.0}},
+	}
+
+	var sorters []Sorter = []Sorter{
+		&SorterHypervolumeIndicator{ReferencePoint: []float64{0.0, 0.0}, Maximize: []bool{true, true}, Weights: []float64{1.0, 1.0}, IndicatorPower: 1.0},
+		&SorterNSGA2{Maximize: []bool{true, true}},
+		&SorterLexicographic{Objectives: []LexicographicObjective{LexicographicObjective{Outcome: 0, Maximize: true}}},
+		&SorterWeightedSum{Weights: []float64{1.0, 1.0}, Maximize: []bool{true, true}, Normalize: true},
+	}
+	for _, sorter := range sorters {
+		var sorted []Specimen
+		_, _, sorted = sorter.Sort(append([]Specimen{}, specimens...))
+		var scores []float64
+		scores, _ = constraintTestOrder(sorted)
+		c.Check(scores, DeepEquals, []float64{3.0, 1.0, 2.0}, Commentf("%s", typeNameOf(sorter)))
+		c.Check(sorted[2].SelectionScore < sorted[1].SelectionScore, Equals, true, Commentf("%s", typeNameOf(sorter)))
+	}
+
+	// With no one feasible, none of them have a best.
+	for _, sorter := range sorters {
+		var bestScore float64
+		bestScore, _, _ = sorter.Sort([]Specimen{specimens[1]})
+		c.Check(math.IsNaN(bestScore), Equals, true, Commentf("%s", typeNameOf(sorter)))
+	}
+}
+
+func (s *ConstraintSuite) Test_SummarizeConstraints(c *C) {
+	c.Check(summarizeConstraints(nil), Equals, constraintSummary{})
+	c.Check(summarizeConstraints(constraintTestSpecimens()), Equals, constraintSummary{
+		specimens:         4,
+		feasibleSpecimens: 2,
+		violationMin:      0.0,
+		violationTotal:    3.0,
+	})
+	c.Check(summarizeConstraints(constraintTestSpecimens()[1:2]), Equals, constraintSummary{
+		specimens:      1,
+		violationMin:   2.0,
+		violationTotal: 2.0,
+	})
+}
+
+func (s *ConstraintSuite) Test_NewScoredSpecimen(c *C) {
+	var specimen Specimen = newScoredSpecimen(NeatNeuralNet{}, ScoreResult{Score: 1.0, ConstraintViolation: 2.5})
+	c.Check(specimen.ConstraintViolation, Equals, 2.5)
+}
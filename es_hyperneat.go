@@ -0,0 +1,370 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+)
+
+// EsHyperNeat is evolvable-substrate HyperNEAT. Rather than hidden nodes placed by hand, the CPPN places them: hidden nodes
+// go wherever the CPPN's weights have enough information (variance) to be worth a connection. A quadtree over the substrate
+// is divided where the weights vary, then the divisions are pruned back to the points that stand out from their neighbors
+// (a band). Searching starts from the inputs, continues from the hidden nodes found for a number of iterations, and finishes
+// by connecting the hidden nodes to the outputs.
+//
+// The substrate is 2D, -1.0 to 1.0 in each dimension. Only the inputs and outputs are given, there are no hidden layers.
+//
+// Reference: http://eplex.cs.ucf.edu/ESHyperNEAT/
+// Reference: Risi, S. and Stanley, K. O. (2012) An Enhanced Hypercube-Based Encoding for Evolving the Placement, Density
+// and Connectivity of Neurons.
+type EsHyperNeat struct {
+	HyperNeat                 // The substrate inputs and outputs, phenotype in/out, and how weights are expressed.
+	InitialDepth      int     // The quadtree is always divided at least this deep.
+	MaxDepth          int     // The quadtree is never divided deeper than this.
+	DivisionThreshold float64 // Between the initial and max depth, quadtree squares with more variance than this are divided.
+	VarianceThreshold float64 // Quadtree squares with at least this much variance are searched further for connections. Must be more than 0.0.
+	BandThreshold     float64 // A point becomes a connection if it differs from its neighbors by more than this.
+	IterationLevel    int     // How many times to search for more hidden nodes from the hidden nodes already found.
+}
+
+// LoadEsHyperNeatConfig loads the json filename as a new configuration.
+func LoadEsHyperNeatConfig(filename string) (EsHyperNeat, error) {
+	var err error
+	var bytes []byte
+	var esHyperNeat EsHyperNeat
+
+	log.Printf("Loading ES-HyperNEAT Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return EsHyperNeat{}, err
+	}
+	if err = json.Unmarshal(bytes, &esHyperNeat); err != nil {
+		return EsHyperNeat{}, err
+	}
+	esHyperNeat.validOrPanic()
+	return esHyperNeat, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (e *EsHyperNeat) validOrPanic() {
+	e.HyperNeat.validOrPanic()
+
+	if e.Substrate.Dimensions != 2 {
+		log.Panicf("ES-HyperNEAT Substrate Dimensions must be 2: %d", e.Substrate.Dimensions)
+	}
+	if len(e.Substrate.Hidden) > 0 {
+		log.Panic("ES-HyperNEAT Substrate cannot have Hidden layers, hidden nodes are placed by the CPPN")
+	}
+	if e.InitialDepth < 1 {
+		log.Panicf("InitialDepth must be one or more: %d", e.InitialDepth)
+	}
+	if e.MaxDepth < e.InitialDepth {
+		log.Panicf("MaxDepth (%d) must be at least InitialDepth (%d)", e.MaxDepth, e.InitialDepth)
+	}
+	if e.DivisionThreshold < 0.0 || e.VarianceThreshold < 0.0 || e.BandThreshold < 0.0 {
+		log.Panicf("DivisionThreshold (%f), VarianceThreshold (%f), and BandThreshold (%f) cannot be negative", e.DivisionThreshold, e.VarianceThreshold, e.BandThreshold)
+	}
+	if e.VarianceThreshold == 0.0 {
+		log.Panic("VarianceThreshold must be more than 0.0, every square would be searched further and none become connections")
+	}
+	if e.IterationLevel < 0 {
+		log.Panicf("IterationLevel cannot be negative: %d", e.IterationLevel)
+	}
+}
+
+// quadPoint is a square of the quadtree, with the CPPN weight at its center.
+type quadPoint struct {
+	x        float64
+	y        float64
+	width    float64 // Half the width of the square.
+	level    int
+	weight   float64
+	children []*quadPoint
+}
+
+// leafWeights are the weights of all the undivided squares inside this square.
+func (q *quadPoint) leafWeights() (weights []float64) {
+	if len(q.children) == 0 {
+		return []float64{q.weight}
+	}
+	for _, child := range q.children {
+		weights = append(weights, child.leafWeights()...)
+	}
+	return weights
+}
+
+// variance is how much the weights inside this square vary.
+func (q *quadPoint) variance() float64 {
+	var weights []float64 = q.leafWeights()
+	var mean float64
+	for _, weight := range weights {
+		mean += weight
+	}
+	mean /= float64(len(weights))
+	var variance float64
+	for _, weight := range weights {
+		variance += (weight - mean) * (weight - mean)
+	}
+	return variance / float64(len(weights))
+}
+
+// esConnection is a connection found between two points of the substrate.
+type esConnection struct {
+	from   []float64
+	to     []float64
+	weight float64 // The CPPN weight, not yet expressed.
+}
+
+// cppnWeight is the CPPN weight of a connection from or to a point, depending on the direction of the search.
+func (e *EsHyperNeat) cppnWeight(cppn *NeatNeuralNet, point []float64, other []float64, isOutgoing bool) float64 {
+	if isOutgoing {
+		return e.queryCppn(cppn, point, other)[HYPERNEAT_CPPN_WEIGHT]
+	}
+	return e.queryCppn(cppn, other, point)[HYPERNEAT_CPPN_WEIGHT]
+}
+
+// divide builds the quadtree of CPPN weights for connections from (outgoing) or to (incoming) a point. Squares are divided
+// to the initial depth, then further where their weights vary, up to the max depth.
+func (e *EsHyperNeat) divide(cppn *NeatNeuralNet, point []float64, isOutgoing bool) (root *quadPoint) {
+	root = &quadPoint{x: 0.0, y: 0.0, width: 1.0, level: 1}
+	var toDivide []*quadPoint = []*quadPoint{root}
+	for len(toDivide) > 0 {
+		var square *quadPoint = toDivide[0]
+		toDivide = toDivide[1:]
+
+		// Four smaller squares.
+		var half float64 = square.width / 2.0
+		for _, offset := range [][]float64{{-1.0, -1.0}, {-1.0, 1.0}, {1.0, -1.0}, {1.0, 1.0}} {
+			var child *quadPoint = &quadPoint{x: square.x + offset[0]*half, y: square.y + offset[1]*half, width: half, level: square.level + 1}
+			child.weight = e.cppnWeight(cppn, point, []float64{child.x, child.y}, isOutgoing)
+			square.children = append(square.children, child)
+		}
+
+		// Divide further?
+		if square.level < e.InitialDepth || (square.level < e.MaxDepth && square.variance() > e.DivisionThreshold) {
+			toDivide = append(toDivide, square.children...)
+		}
+	}
+	return root
+}
+
+// prune walks the quadtree, keeping the points that stand out from their neighbors as connections. Divided squares with
+// enough variance are searched further instead. An undivided square can't be searched further, so it is always a candidate.
+func (e *EsHyperNeat) prune(cppn *NeatNeuralNet, point []float64, square *quadPoint, isOutgoing bool) (connections []esConnection) {
+	for _, child := range square.children {
+		if len(child.children) > 0 && child.variance() >= e.VarianceThreshold {
+			connections = append(connections, e.prune(cppn, point, child, isOutgoing)...)
+			continue
+		}
+
+		// How different is this point from its neighbors to the left and right, and above and below? It is in a band if it
+		// differs on both sides in either direction.
+		var left float64 = math.Abs(child.weight - e.cppnWeight(cppn, point, []float64{child.x - child.width, child.y}, isOutgoing))
+		var right float64 = math.Abs(child.weight - e.cppnWeight(cppn, point, []float64{child.x + child.width, child.y}, isOutgoing))
+		var below float64 = math.Abs(child.weight - e.cppnWeight(cppn, point, []float64{child.x, child.y - child.width}, isOutgoing))
+		var above float64 = math.Abs(child.weight - e.cppnWeight(cppn, point, []float64{child.x, child.y + child.width}, isOutgoing))
+		var band float64 = math.Max(math.Min(left, right), math.Min(below, above))
+		if band <= e.BandThreshold {
+			continue
+		}
+
+		var childPoint []float64 = []float64{child.x, child.y}
+		if isOutgoing {
+			connections = append(connections, esConnection{from: point, to: childPoint, weight: child.weight})
+		} else {
+			connections = append(connections, esConnection{from: childPoint, to: point, weight: child.weight})
+		}
+	}
+	return connections
+}
+
+// search finds the connections from (outgoing) or to (incoming) a point.
+func (e *EsHyperNeat) search(cppn *NeatNeuralNet, point []float64, isOutgoing bool) []esConnection {
+	return e.prune(cppn, point, e.divide(cppn, point, isOutgoing), isOutgoing)
+}
+
+// Decode places the hidden nodes and connections of the phenotype neural net with the CPPN.
+//
+// Only connections with a CPPN weight larger than the weight threshold are expressed, as with HyperNEAT. Hidden to hidden
+// connections that would make a loop are skipped, phenotypes are always feed-forward. Every hidden node and output also
+// gets a bias connection, as with HyperNEAT. Hidden nodes that are not on a path from the inputs to the outputs are
+// dropped, and an output left with nothing feeding it gets a zero weight bias connection so it can still be computed.
+// The phenotype's gene ids are its own.
+func (e *EsHyperNeat) Decode(genotype NeatNeuralNet) (phenotype NeatNeuralNet) {
+	var cppn NeatNeuralNet = genotype.makeClone() // Computing builds a topology on the neural net, keep it off the genotype.
+
+	// Hidden nodes are known by where they are.
+	var hiddenPoints [][]float64
+	var hiddenNodeIds map[string]string = map[string]string{}
+	var pointKey = func(point []float64) string {
+		return strconv.FormatFloat(point[0], 'g', -1, 64) + "," + strconv.FormatFloat(point[1], 'g', -1, 64)
+	}
+	var addHidden = func(point []float64) (nodeId string, isNew bool) {
+		var ok bool
+		if nodeId, ok = hiddenNodeIds[pointKey(point)]; ok {
+			return nodeId, false
+		}
+		hiddenPoints = append(hiddenPoints, point)
+		nodeId = strconv.Itoa(len(hiddenPoints)) // Node genes come first, so a node's gene id is its order.
+		hiddenNodeIds[pointKey(point)] = nodeId
+		return nodeId, true
+	}
+
+	// The connections found, only expressed ones.
+	type foundConnection struct {
+		from   string
+		to     string
+		weight float64
+	}
+	var connections []foundConnection
+	var isConnected map[string]bool = map[string]bool{}
+	var hiddenSinks map[string][]string = map[string][]string{} // For finding loops between hidden nodes.
+	var addConnection = func(from string, to string, cppnWeight float64) {
+		var weight float64
+		var isExpressed bool
+		if weight, isExpressed = e.expressedWeight(cppnWeight); !isExpressed {
+			return
+		}
+		if isConnected[from+" "+to] {
+			return
+		}
+		isConnected[from+" "+to] = true
+		connections = append(connections, foundConnection{from: from, to: to, weight: weight})
+	}
+
+	// Search from each input for hidden nodes.
+	var networkInputs []string = e.InOut.networkInputs()
+	var unexplored []string
+	for _, in := range networkInputs {
+		for _, connection := range e.search(&cppn, e.Substrate.Inputs[in], true) {
+			if _, isExpressed := e.expressedWeight(connection.weight); !isExpressed {
+				continue
+			}
+			var nodeId string
+			var isNew bool
+			if nodeId, isNew = addHidden(connection.to); isNew {
+				unexplored = append(unexplored, nodeId)
+			}
+			addConnection(in, nodeId, connection.weight)
+		}
+	}
+
+	// Search from the hidden nodes just found for more hidden nodes, again and again.
+	for iteration := 0; iteration < e.IterationLevel; iteration++ {
+		var exploring []string = unexplored
+		unexplored = nil
+		for _, from := range exploring {
+			var fromIndex int
+			fromIndex, _ = strconv.Atoi(from)
+			for _, connection := range e.search(&cppn, hiddenPoints[fromIndex-1], true) {
+				if _, isExpressed := e.expressedWeight(connection.weight); !isExpressed {
+					continue
+				}
+				var nodeId string
+				var isNew bool
+				if nodeId, isNew = addHidden(connection.to); isNew {
+					unexplored = append(unexplored, nodeId)
+				}
+
+				// Phenotypes must be feed-forward. Skip any connection back to where it came from.
+				if nodeId == from || isReachable(hiddenSinks, nodeId, from) {
+					continue
+				}
+				hiddenSinks[from] = append(hiddenSinks[from], nodeId)
+				addConnection(from, nodeId, connection.weight)
+			}
+		}
+	}
+
+	// Search into each output from the hidden nodes already found.
+	for _, out := range e.InOut.Outputs {
+		for _, connection := range e.search(&cppn, e.Substrate.Outputs[out], false) {
+			var nodeId string
+			var ok bool
+			if nodeId, ok = hiddenNodeIds[pointKey(connection.from)]; ok {
+				addConnection(nodeId, out, connection.weight)
+			}
+		}
+	}
+
+	// Every hidden node and output also has a bias, queried as if coming from the origin.
+	var origin []float64 = []float64{0.0, 0.0}
+	for i, point := range hiddenPoints {
+		addConnection(NODE_BIAS, strconv.Itoa(i+1), e.queryCppn(&cppn, origin, point)[HYPERNEAT_CPPN_BIAS])
+	}
+	for _, out := range e.InOut.Outputs {
+		addConnection(NODE_BIAS, out, e.queryCppn(&cppn, origin, e.Substrate.Outputs[out])[HYPERNEAT_CPPN_BIAS])
+	}
+
+	// What has a path from the inputs?
+	var sinks map[string][]string = map[string][]string{}
+	for _, connection := range connections {
+		sinks[connection.from] = append(sinks[connection.from], connection.to)
+	}
+	var isFed map[string]bool = map[string]bool{}
+	var toVisit []string
+	toVisit = append(toVisit, networkInputs...)
+	for len(toVisit) > 0 {
+		var nodeId string = toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if isFed[nodeId] {
+			continue
+		}
+		isFed[nodeId] = true
+		toVisit = append(toVisit, sinks[nodeId]...)
+	}
+
+	// Build the phenotype from only the hidden nodes with a path from the inputs.
+	phenotype = NeatNeuralNet{
+		InOut:  e.InOut,
+		Genome: neatGenome{},
+	}
+	for i := range hiddenPoints {
+		if isFed[strconv.Itoa(i+1)] {
+			phenotype.Genome.Genes = append(phenotype.Genome.Genes, neatGene{GeneId: uint64(i + 1), IsEnabled: true, Type: _GENE_TYPE_NODE, Function: e.HiddenFunction})
+		}
+	}
+	var isOutputFed map[string]bool = map[string]bool{}
+	var geneId uint64 = uint64(len(hiddenPoints))
+	for _, connection := range connections {
+		geneId++
+		if (connection.from == NODE_BIAS || isFed[connection.from]) && (isFed[connection.to] || inStrings(e.InOut.Outputs, connection.to)) {
+			phenotype.Genome.Genes = append(phenotype.Genome.Genes, neatGene{GeneId: geneId, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: connection.from, To: connection.to, Weight: connection.weight})
+			isOutputFed[connection.to] = true
+		}
+	}
+
+	// An output must have something feeding it to be computed.
+	for _, out := range e.InOut.Outputs {
+		if !isOutputFed[out] {
+			geneId++
+			phenotype.Genome.Genes = append(phenotype.Genome.Genes, neatGene{GeneId: geneId, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: out, Weight: 0.0})
+		}
+	}
+
+	// Drop hidden nodes that cannot reach an output.
+	phenotype, _ = phenotype.Simplify()
+	return phenotype
+}
+
+// isReachable is true if there is a path from one node to another.
+func isReachable(sinks map[string][]string, from string, to string) bool {
+	var isVisited map[string]bool = map[string]bool{}
+	var toVisit []string = []string{from}
+	for len(toVisit) > 0 {
+		var nodeId string = toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if nodeId == to {
+			return true
+		}
+		if isVisited[nodeId] {
+			continue
+		}
+		isVisited[nodeId] = true
+		toVisit = append(toVisit, sinks[nodeId]...)
+	}
+	return false
+}
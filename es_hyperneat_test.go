@@ -0,0 +1,172 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type EsHyperNeatSuite struct{}
+
+var _ = Suite(&EsHyperNeatSuite{})
+
+// esHyperNeatTestConfig is a 2D substrate with two inputs along the bottom and one output at the top.
+func esHyperNeatTestConfig() EsHyperNeat {
+	var esHyperNeat EsHyperNeat = EsHyperNeat{
+		HyperNeat: HyperNeat{
+			Substrate: Substrate{
+				Dimensions: 2,
+				Inputs:     map[string][]float64{"left": []float64{-1.0, -1.0}, "right": []float64{1.0, -1.0}},
+				Outputs:    map[string][]float64{"out": []float64{0.0, 1.0}},
+			},
+			InOut: NeuralNetInOut{
+				Inputs:  []string{"left", "right"},
+				Outputs: []string{"out"},
+			},
+			WeightThreshold: 0.1,
+			MaxWeight:       3.0,
+			HiddenFunction:  ACTIVATION_SIGMOID,
+		},
+		InitialDepth:      2,
+		MaxDepth:          4,
+		DivisionThreshold: 0.03,
+		VarianceThreshold: 0.03,
+		BandThreshold:     0.15,
+		IterationLevel:    1,
+	}
+	esHyperNeat.validOrPanic()
+	return esHyperNeat
+}
+
+// Add the tests.
+
+func (s *EsHyperNeatSuite) Test_QuadPoint_Variance(c *C) {
+	var square quadPoint = quadPoint{weight: 5.0}
+	c.Check(square.variance(), Equals, 0.0) // A single point never varies.
+
+	square.children = []*quadPoint{
+		&quadPoint{weight: 1.0},
+		&quadPoint{weight: 3.0, children: []*quadPoint{&quadPoint{weight: 2.0}, &quadPoint{weight: 4.0}}},
+		&quadPoint{weight: 1.0},
+		&quadPoint{weight: 4.0},
+	}
+	c.Check(square.leafWeights(), DeepEquals, []float64{1.0, 2.0, 4.0, 1.0, 4.0})
+	c.Check(isNearlyEqual(square.variance(), 1.84), Equals, true) // Mean 2.4.
+}
+
+func (s *EsHyperNeatSuite) Test_IsReachable(c *C) {
+	var sinks map[string][]string = map[string][]string{
+		"1": []string{"2", "3"},
+		"3": []string{"4"},
+		"4": []string{"2"},
+	}
+	c.Check(isReachable(sinks, "1", "4"), Equals, true)
+	c.Check(isReachable(sinks, "1", "1"), Equals, true)
+	c.Check(isReachable(sinks, "4", "3"), Equals, false)
+	c.Check(isReachable(sinks, "2", "1"), Equals, false)
+}
+
+func (s *EsHyperNeatSuite) Test_EsHyperNeat_Decode_Uniform(c *C) {
+	var esHyperNeat EsHyperNeat = esHyperNeatTestConfig()
+
+	// A CPPN with the same weight everywhere has no information to place hidden nodes with.
+	var cppn NeatNeuralNet = NeatNeuralNet{
+		InOut: esHyperNeat.CppnInOut(),
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_WEIGHT, Weight: 0.55},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_BIAS, Weight: 0.55},
+		}},
+	}
+
+	// Only the output's bias remains.
+	var phenotype NeatNeuralNet = esHyperNeat.Decode(cppn)
+	c.Check(phenotype.InOut, DeepEquals, esHyperNeat.InOut)
+	c.Assert(phenotype.Genome.Genes, HasLen, 1)
+	var gene neatGene = phenotype.Genome.Genes[0]
+	c.Check(gene.GeneId, Equals, uint64(1))
+	c.Check(gene.From, Equals, NODE_BIAS)
+	c.Check(gene.To, Equals, "out")
+	c.Check(isNearlyEqual(gene.Weight, 1.5), Equals, true) // Half way from the threshold to 1.0 is half the max weight.
+}
+
+func (s *EsHyperNeatSuite) Test_EsHyperNeat_Decode(c *C) {
+	var esHyperNeat EsHyperNeat = esHyperNeatTestConfig()
+
+	// A CPPN with a ridge of strong weights along the diagonal x + y = 0, at both ends of a connection.
+	var cppn NeatNeuralNet = NeatNeuralNet{
+		InOut: esHyperNeat.CppnInOut(),
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_GAUSSIAN},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_X2, To: "1", Weight: 8.0},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_Y2, To: "1", Weight: 8.0},
+			neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: HYPERNEAT_CPPN_WEIGHT, Weight: 1.0},
+			neatGene{GeneId: 5, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_GAUSSIAN},
+			neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_X1, To: "5", Weight: 8.0},
+			neatGene{GeneId: 7, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_Y1, To: "5", Weight: 8.0},
+			neatGene{GeneId: 8, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "5", To: HYPERNEAT_CPPN_WEIGHT, Weight: 1.0},
+			neatGene{GeneId: 9, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_BIAS, Weight: 0.0},
+		}},
+	}
+
+	var phenotype NeatNeuralNet = esHyperNeat.Decode(cppn)
+
+	// Hidden nodes were placed, and connect inputs to the output.
+	var hiddenCount int
+	var inputConnections int
+	var outputConnections int
+	for _, gene := range phenotype.Genome.Genes {
+		if gene.Type == _GENE_TYPE_NODE {
+			hiddenCount++
+			c.Check(gene.Function, Equals, ACTIVATION_SIGMOID)
+		}
+		if gene.Type == _GENE_TYPE_CONNECTION && (gene.From == "left" || gene.From == "right") {
+			inputConnections++
+		}
+		if gene.Type == _GENE_TYPE_CONNECTION && gene.To == "out" {
+			outputConnections++
+			c.Check(gene.From, Not(Equals), NODE_BIAS) // The CPPN bias is zero.
+		}
+	}
+	c.Check(hiddenCount > 0, Equals, true)
+	c.Check(inputConnections > 0, Equals, true)
+	c.Check(outputConnections > 0, Equals, true)
+
+	// It is feed-forward, and nothing in it is dead.
+	var ok bool
+	_, ok = makeComputeTopology(phenotype.InOut, phenotype.Genome.Genes)
+	c.Check(ok, Equals, true)
+	var report SimplifyReport
+	_, report = phenotype.Simplify()
+	c.Check(report, DeepEquals, SimplifyReport{})
+	phenotype.Compute(map[string]float64{"left": 0.5, "right": 0.25})
+
+	// The same CPPN always decodes the same way.
+	c.Check(esHyperNeat.Decode(cppn).Genome, DeepEquals, phenotype.Genome)
+}
+
+func (s *EsHyperNeatSuite) Test_EsHyperNeat_ValidOrPanic(c *C) {
+	var esHyperNeat EsHyperNeat
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.Substrate.Hidden = [][][]float64{[][]float64{[]float64{0.0, 0.0}}}
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "ES-HyperNEAT Substrate cannot have Hidden layers, hidden nodes are placed by the CPPN")
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.InitialDepth = 0
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "InitialDepth must be one or more: 0")
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.MaxDepth = 1
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "MaxDepth (1) must be at least InitialDepth (2)")
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.BandThreshold = -1.0
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "DivisionThreshold (0.030000), VarianceThreshold (0.030000), and BandThreshold (-1.000000) cannot be negative")
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.VarianceThreshold = 0.0
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "VarianceThreshold must be more than 0.0, every square would be searched further and none become connections")
+
+	esHyperNeat = esHyperNeatTestConfig()
+	esHyperNeat.IterationLevel = -1
+	c.Check(func() { esHyperNeat.validOrPanic() }, Panics, "IterationLevel cannot be negative: -1")
+}
@@ -28,12 +28,92 @@ type geneticExperiment struct {
 	sorter         Sorter   // The sorter that orders the specimens for selection.
 	selector       Selector // The selector of which specimens should continue on to the next generation.
 	db             *sql.DB  // The database connection.
+
+	noveltySearch *NoveltySearch // If configured, the novelty search rewarding new behaviors. nil otherwise.
+	hallOfFame    *hallOfFame    // If configured, the best distinct genomes ever seen. nil otherwise.
+
+	// Some experiments keep a hall of fame for each population instead (e.g. coevolution, where the populations are
+	// scored against each other).
+	isHallOfFameByPopulation bool // True if each population keeps its own hall of fame, rather than the experiment.
 }
 
 // RunExperiment runs a genetic experiment until stopped manually or an end condition is met.
 func RunExperiment(experimentName string, config Config, sorter Sorter, selector Selector, scorer Scorer) {
 
 	// Create the experiment.
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, selector, scorer)
+
+	// Ensure the interface defined in the config is valid.
+	experiment.config.NeuralNetInOut.validate()
+
+	// The selector must agree with the sorter on which way selection scores run.
+	validSelectionOrPanic(experiment.sorter, experiment.selector)
+
+	// Is novelty rewarded?
+	if experiment.config.NoveltySearch.K > 0 {
+		experiment.noveltySearch = NewNoveltySearch(experiment.config.NoveltySearch)
+	}
+
+	// Is each specimen scored in several trials?
+	experiment.config.Scoring.validOrPanic()
+	validAggregationOrPanic(experiment.sorter, experiment.config.Scoring)
+
+	// Create an initial neural net that will seed the population, creating
+	// a single specimen in a single species. In the first generation, this neural net will
+	// be mutated into a full population through the normal mechanism to fill out a generation.
+	var population generationPopulation = newPopulation(experiment.config.Population)
+	var neuralNet NeatNeuralNet = newNeatNeuralNet(experiment.config.NeuralNetInOut)
+	population.AddNeuralNet(neuralNet, 0.0, 0.0, nil) // The specimen has no scores.
+
+	// Run a generation of the experiment.
+	experiment.run(func(generationNum uint64) (result generationResult) {
+
+		// Give the scorer the champions so far, if it wants them.
+		experiment.shareHallOfFame(experiment.scorer)
+
+		// Tell the scorer that a new generation has started.
+		// It may want to prepare internal data structures.
+		experiment.scorer.GenerationStart(generationNum)
+
+		// Fill out the population to the correct size.
+		// We either have the first generation's initial specimen or we have
+		// the fittest specimens from the prior generation.
+		population.FillOut()
+
+		// Score each specimen, and re-add it into the population. The survivors keep their earlier scores, in case they
+		// are averaged over their lifetime.
+		for _, specimen := range scoreSpecimens(experiment.scorer, population.DumpSpecimens(), experiment.config.Scoring, experiment.noveltySearch) {
+			population.AddSpecimen(specimen)
+		}
+
+		// Modify the scores of the specimens by the size of their species.
+		population.WeightSpecies()
+
+		// Dump the specimens from the population, ready for selection.
+		var specimens []Specimen = population.DumpSpecimens()
+
+		// Sort the specimens. The specimens earlier in the slice are considered more fit.
+		result.bestScore, result.best, result.sorted = experiment.sorter.Sort(specimens)
+		result.sorterBytes = sorterGenerationDetails(experiment.sorter)
+
+		// How well did the whole generation keep to the scorer's constraints (before selection)?
+		result.constraints = summarizeConstraints(result.sorted)
+
+		// Select the fittest specimens.
+		var fittestSpecimens []Specimen = experiment.selector.Select(result.sorted)
+
+		// Add the the specimens back into the population.
+		population.AddAllSpecimens(fittestSpecimens)
+
+		result.population = population
+		result.reinjectInto = []*generationPopulation{&population}
+		return result
+	}, experiment.recordPopulationGeneration)
+}
+
+// newGeneticExperiment creates an experiment, ready for its population to be created. The randomness and gene ids start
+// afresh. Each way of running an experiment checks the parts of the config it uses.
+func newGeneticExperiment(experimentName string, config Config, sorter Sorter, selector Selector, scorer Scorer) geneticExperiment {
 	var experiment geneticExperiment = geneticExperiment{
 		experimentName: experimentName,
 		config:         config,
@@ -43,24 +123,51 @@ func RunExperiment(experimentName string, config Config, sorter Sorter, selector
 		db:             newDatabaseConnection(),
 	}
 
-	// Ensure the interface defined in the config is valid.
-	experiment.config.NeuralNetInOut.validate()
-
 	// Get the randomness rolling.
 	rand.Seed(time.Now().UnixNano())
 
-	// Record the start of the experiment.
-	experiment.recordStart()
-
 	// Start the current max geneid.
 	setMaxGeneId(0)
 
-	// Create an initial neural net that will seed the population, creating
-	// a single specimen in a single species. In the first generation, this neural net will
-	// be mutated into a full population through the normal mechanism to fill out a generation.
-	var population generationPopulation = newPopulation(experiment.config.Population)
-	var neuralNet NeatNeuralNet = newNeatNeuralNet(experiment.config.NeuralNetInOut)
-	population.AddNeuralNet(neuralNet, 0.0, 0.0, nil) // The specimen has no scores.
+	return experiment
+}
+
+// generationStep evolves a single generation of an experiment, leaving its population ready for the next generation,
+// and reports how the generation went.
+type generationStep func(generationNum uint64) generationResult
+
+// generationRecorder records a single generation of an experiment.
+type generationRecorder func(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, result generationResult)
+
+// generationResult is how a single generation of an experiment went.
+type generationResult struct {
+	bestScore   float64              // The best score of the generation (see noFeasibleBestScore).
+	best        string               // The details of the best member of the generation.
+	constraints constraintSummary    // How well the generation kept to the scorer's constraints.
+	population  generationPopulation // Everyone kept for the next generation.
+	sorted      []Specimen           // Everyone scored this generation, sorted, for the hall of fame.
+	sorterBytes []byte               // The sorter's details of the generation, if it has any.
+	endResults  interface{}          // What is recorded as the results if the experiment ends here. If nil, the population's species.
+
+	// Some steps judge improvement themselves, rather than by whether the best score is better (e.g. MAP-Elites, where
+	// any new elite is an improvement).
+	isImprovementJudged bool                    // True if the step judged whether the generation improved.
+	isImproved          bool                    // If judged, true if the generation improved.
+	reinjectInto        []*generationPopulation // The populations the hall of fame can put champions back into, if any.
+}
+
+// run runs the generations of an experiment until stopped manually or an end condition is met. The step evolves each
+// generation, and the recorder records the generations worth recording.
+func (e *geneticExperiment) run(step generationStep, record generationRecorder) {
+
+	// Are champions remembered?
+	e.config.HallOfFame.validOrPanic()
+	if e.config.HallOfFame.Size > 0 && !e.isHallOfFameByPopulation {
+		e.hallOfFame = newHallOfFame(e.config.HallOfFame.Size, e.sorter)
+	}
+
+	// Record the start of the experiment.
+	e.recordStart()
 
 	// Keep track if this experiment becomes stagnant (fitness never improving).
 	// In this case, we have found some maxima and cannot move beyond it.
@@ -68,13 +175,13 @@ func RunExperiment(experimentName string, config Config, sorter Sorter, selector
 	var stagnantGenerationCount uint64 = 0
 
 	// What generation is the last of the experiment?
-	var endConditionGenerationNum uint64 = experiment.config.EndCondition.GenerationNum
+	var endConditionGenerationNum uint64 = e.config.EndCondition.GenerationNum
 	if endConditionGenerationNum == 0 {
 		endConditionGenerationNum = _DEFAULT_END_GENERATION_NUM
 	}
 
 	// Every generation capture the details of the best member of that generation.
-	var best string
+	var result generationResult
 
 	// Keep track of why the experiment ends.
 	var endReason string
@@ -88,141 +195,121 @@ func RunExperiment(experimentName string, config Config, sorter Sorter, selector
 	// Run a generation of the experiment.
 	var generationNum uint64
 	for generationNum = 1; generationNum <= endConditionGenerationNum; generationNum++ {
+		result = step(generationNum)
 
-		// Tell the scorer that a new generaiton has started.
-		// It may want to prepare internal data structures.
-		experiment.scorer.GenerationStart(generationNum)
-
-		// Fill out the population to the correct size.
-		// We either have the first generation's initial specimen or we have
-		// the fittest specimens from the prior generation.
-		population.FillOut()
-
-		// Dump the neural nets from the population for examining, ready for scoring.
-		var neuralNets []NeatNeuralNet = population.DumpSpecimensAsNeuralNets()
-
-		// Score each neural net, one at a time. Bundle with its scores to make a specimen.
-		for i, neuralNet := range neuralNets {
-
-			// For scoring get these results:
-			//
-			//  score is the score for the neural net
-			//  bonus is decided by meta-decisions (e.g. novelty search), 0.0 if nothing
-			//  outcomes are for use with multi-outcome selectors (e.g. hyper-volume indicator), null otherwise
-			var score float64
-			var bonus float64
-			var outcomes []float64
-
-			// Score this neural net.
-			score, bonus, outcomes = scorer.Score(neuralNet, neuralNets, i)
-
-			// Re-add the specimen into the population.
-			population.AddNeuralNet(neuralNet, score, bonus, outcomes)
+		// Remember the champions, before selection loses any.
+		if e.hallOfFame != nil {
+			e.hallOfFame.offer(result.sorted, generationNum)
 		}
 
-		// Modify the scores of the specimens by the size of their species.
-		population.WeightSpecies()
-
-		// Dump the specimens from the population, ready for selection.
-		var specimens []Specimen = population.DumpSpecimens()
-
-		// Sort the specimens. The specimens earlier in the slice are considered more fit.
-		var bestScore float64
-		var sorted []Specimen
-		bestScore, best, sorted = experiment.sorter.Sort(specimens)
-
-		// Select the fittest specimens.
-		var fittestSpecimens []Specimen = experiment.selector.Select(sorted)
-
-		// Add the the specimens back into the population.
-		population.AddAllSpecimens(fittestSpecimens)
-
 		// Did we improve over prior generations?
-		if experiment.sorter.IsMaximize() {
-			// Maximizing score.
-			if bestScore > bestExperimentScore {
-				bestExperimentScore = bestScore
-				stagnantGenerationCount = 0
-			} else {
-				stagnantGenerationCount++
-			}
+		if result.isImprovementJudged {
+			bestExperimentScore, stagnantGenerationCount = trackJudgedImprovement(result.isImproved, result.bestScore, stagnantGenerationCount)
 		} else {
-			// Minimizing score.
-			if bestScore < bestExperimentScore {
-				bestExperimentScore = bestScore
-				stagnantGenerationCount = 0
-			} else {
-				stagnantGenerationCount++
-			}
+			bestExperimentScore, stagnantGenerationCount = trackImprovement(e.sorter.IsMaximize(), result.bestScore, bestExperimentScore, stagnantGenerationCount)
 		}
 
 		// Is this experiment over?
-
-		// Have we reached the final generation?
-		if generationNum >= endConditionGenerationNum {
-			// End the experiment.
-			endReason = fmt.Sprintf("reached generation: %d", generationNum)
+		if endReason = e.endReason(generationNum, endConditionGenerationNum, e.sorter.IsMaximize(), bestExperimentScore, stagnantGenerationCount, manualStopChannel); endReason != "" {
 			break
 		}
 
-		// Have we reached a target score?
-		if experiment.sorter.IsMaximize() {
-			// Maximizing score.
-			if experiment.config.EndCondition.TargetScore > 0.0 && bestExperimentScore >= experiment.config.EndCondition.TargetScore {
-				// End the experiment.
-				endReason = fmt.Sprintf("target score %f reached: %f", experiment.config.EndCondition.TargetScore, bestExperimentScore)
-				break
-			}
-		} else {
-			// Minimizing score.
-			// Since an uninitialized config will give a target score of 0.0, assume that is our target or anything else specified.
-			if bestExperimentScore <= experiment.config.EndCondition.TargetScore {
-				// End the experiment.
-				endReason = fmt.Sprintf("target score %f reached: %f", experiment.config.EndCondition.TargetScore, bestExperimentScore)
-				break
-			}
+		// Record the generation of the experiment, if it is one we want to record.
+		if e.isRecordGeneration(generationNum) {
+			record(generationNum, bestExperimentScore, stagnantGenerationCount, result)
 		}
 
-		// Is the experiment stuck and not improving?
-		if experiment.config.EndCondition.StagnantGenerationCount > 0 && stagnantGenerationCount >= experiment.config.EndCondition.StagnantGenerationCount {
-			// End the experiment.
-			endReason = fmt.Sprintf("stagnant generation reached: %d", stagnantGenerationCount)
-			break
+		// If the experiment has stagnated, put the champions back into the population.
+		if e.isReinjectGeneration(stagnantGenerationCount) {
+			for _, population := range result.reinjectInto {
+				e.hallOfFame.reinject(population, e.config.HallOfFame.ReinjectCount)
+			}
 		}
+	}
+
+	// If we just ended the experiment we have yet to record this last generation.
+	record(generationNum, bestExperimentScore, stagnantGenerationCount, result)
+
+	// Record the end of the experiment.
+	var endResults interface{} = result.endResults
+	if endResults == nil {
+		endResults = result.population.species
+	}
+	e.recordEnd(generationNum, endReason, endResults)
+	if e.hallOfFame != nil {
+		e.recordHallOfFame(0, e.hallOfFame)
+	}
+}
+
+// trackImprovement compares a generation's best score to the best of the experiment so far. If it is better, it is the new
+// best and the experiment is no longer stagnant. Otherwise (including a generation with no feasible specimens), the
+// experiment has been stagnant another generation.
+func trackImprovement(isMaximize bool, bestScore float64, bestExperimentScore float64, stagnantGenerationCount uint64) (float64, uint64) {
+	if isBetterScore(isMaximize, bestScore, bestExperimentScore) {
+		return bestScore, 0
+	}
+	return bestExperimentScore, stagnantGenerationCount + 1
+}
+
+// trackJudgedImprovement is trackImprovement for a generation that judged whether it improved itself. Its best score is
+// already the best of the experiment so far.
+func trackJudgedImprovement(isImproved bool, bestScore float64, stagnantGenerationCount uint64) (float64, uint64) {
+	if isImproved {
+		return bestScore, 0
+	}
+	return bestScore, stagnantGenerationCount + 1
+}
+
+// endReason is why the experiment should end after this generation, or "" if it should keep going.
+func (e *geneticExperiment) endReason(generationNum uint64, endConditionGenerationNum uint64, isMaximize bool, bestExperimentScore float64, stagnantGenerationCount uint64, manualStopChannel chan bool) string {
 
-		// Is there a manual stop from the user?
-		// Anything sent of the manual stop channel means it's time to stop.
-		var isStop bool
-		// Use select to create non-blocking channel receive.
-		select {
-		case isStop = <-manualStopChannel: // Only true will ever be sent over this channel.
-		default: // Nothing to do, but creates a non-blocking receive.
+	// Have we reached the final generation?
+	if generationNum >= endConditionGenerationNum {
+		return fmt.Sprintf("reached generation: %d", generationNum)
+	}
+
+	// Have we reached a target score?
+	if isMaximize {
+		// Maximizing score.
+		if e.config.EndCondition.TargetScore > 0.0 && bestExperimentScore >= e.config.EndCondition.TargetScore {
+			return fmt.Sprintf("target score %f reached: %f", e.config.EndCondition.TargetScore, bestExperimentScore)
 		}
-		// Manual stop?
-		if isStop {
-			// End the experiment.
-			endReason = fmt.Sprintf("manual stop triggered")
-			break
+	} else {
+		// Minimizing score.
+		// Since an uninitialized config will give a target score of 0.0, assume that is our target or anything else specified.
+		if bestExperimentScore <= e.config.EndCondition.TargetScore {
+			return fmt.Sprintf("target score %f reached: %f", e.config.EndCondition.TargetScore, bestExperimentScore)
 		}
+	}
 
-		// Is this a generation we want to record?
-		// We don't want to record every generation because of the time it takes to write.
-		var isRecordGeneration bool = false
-		if experiment.config.Database.RecordEveryNthGeneration > 0 {
-			isRecordGeneration = ((generationNum % experiment.config.Database.RecordEveryNthGeneration) == 0)
-		}
+	// Is the experiment stuck and not improving?
+	if e.config.EndCondition.StagnantGenerationCount > 0 && stagnantGenerationCount >= e.config.EndCondition.StagnantGenerationCount {
+		return fmt.Sprintf("stagnant generation reached: %d", stagnantGenerationCount)
+	}
 
-		// Record the generation of the experiment.
-		if isRecordGeneration {
-			experiment.recordGeneration(generationNum, bestExperimentScore, stagnantGenerationCount, best, population)
-		}
+	// Is there a manual stop from the user?
+	// Anything sent of the manual stop channel means it's time to stop.
+	var isStop bool
+	// Use select to create non-blocking channel receive.
+	select {
+	case isStop = <-manualStopChannel: // Only true will ever be sent over this channel.
+	default: // Nothing to do, but creates a non-blocking receive.
+	}
+	// Manual stop?
+	if isStop {
+		return fmt.Sprintf("manual stop triggered")
 	}
 
-	// If we just ended the experiment we have yet to record this last generation.
-	experiment.recordGeneration(generationNum, bestExperimentScore, stagnantGenerationCount, best, population)
+	return ""
+}
 
-	// Record the end of the experiment.
-	experiment.recordEnd(generationNum, endReason, population)
+// isRecordGeneration is true if this generation should be recorded.
+// We don't want to record every generation because of the time it takes to write.
+func (e *geneticExperiment) isRecordGeneration(generationNum uint64) bool {
+	if e.config.Database.RecordEveryNthGeneration > 0 {
+		return ((generationNum % e.config.Database.RecordEveryNthGeneration) == 0)
+	}
+	return false
 }
 
 // recordStart records details about the experiment before it runs.
@@ -250,7 +337,7 @@ func (e *geneticExperiment) recordStart() {
 	var sorterJson string = string(bytes)
 
 	// Also save the type of scorer we're using.
-	var sorterType string = reflect.ValueOf(e.sorter).Elem().Type().String()
+	var sorterType string = typeNameOf(e.sorter)
 
 	// Get the selector as json.
 	if bytes, err = json.Marshal(e.selector); err != nil {
@@ -259,7 +346,7 @@ func (e *geneticExperiment) recordStart() {
 	var selectorJson string = string(bytes)
 
 	// Also save the type of selector we're using.
-	var selectorType string = reflect.ValueOf(e.selector).Elem().Type().String()
+	var selectorType string = typeNameOf(e.selector)
 
 	// Write the experiment to the database.
 	var result sql.Result
@@ -304,30 +391,54 @@ func (e *geneticExperiment) recordStart() {
 	e.experimentId = experimentId
 }
 
-// recordGeneration records details of a single generation of the experiment.
-func (e *geneticExperiment) recordGeneration(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, best string, population generationPopulation) {
-	var err error
+// recordPopulationGeneration records a single generation of an experiment with a single population.
+// The sorter's details are those of the generation's sort, since the hall of fame sorts its champions afterwards.
+func (e *geneticExperiment) recordPopulationGeneration(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, result generationResult) {
+	e.recordIslandGeneration(0, e.scorer.GenerationDetails(), result.sorterBytes, generationNum, bestExperimentScore, stagnantGenerationCount, result.best, result.constraints, result.population)
+}
 
-	// Get generation details from the scorer.
-	var scorerBytes []byte = e.scorer.GenerationDetails()
+// sorterGenerationDetails are the generation details from the sorter, if it has any.
+func sorterGenerationDetails(sorter Sorter) (sorterBytes []byte) {
+	var sorterDetails SorterDetails
+	var ok bool
+	if sorterDetails, ok = sorter.(SorterDetails); ok {
+		sorterBytes = sorterDetails.GenerationDetails()
+	}
+	return sorterBytes
+}
+
+// recordIslandGeneration records details of a single generation of one island of the experiment. Experiments without
+// islands are all island 0.
+func (e *geneticExperiment) recordIslandGeneration(island int, scorerBytes []byte, sorterBytes []byte, generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, best string, constraints constraintSummary, population generationPopulation) {
+	var err error
 
 	// Write the core experiment record to the database.
 	var result sql.Result
 	if result, err = e.db.Exec(
 		`INSERT INTO genetic.experiment_generation
          SET experimentid=?,
+             island=?,
              generation_num=?,
              datetime=NOW(),
              best_experiment_score=?,
              stagnant_generations=?,
              best=?,
-             details=?`,
+             details=?,
+             sorter_details=?,
+             feasible_specimens=?,
+             violation_min=?,
+             violation_total=?`,
 		e.experimentId,
+		island,
 		generationNum,
 		bestExperimentScore,
 		stagnantGenerationCount,
 		best,
-		scorerBytes); err != nil {
+		scorerBytes,
+		sorterBytes,
+		constraints.feasibleSpecimens,
+		constraints.violationMin,
+		constraints.violationTotal); err != nil {
 
 		log.Panic(err)
 	}
@@ -345,15 +456,7 @@ func (e *geneticExperiment) recordGeneration(generationNum uint64, bestExperimen
 	for _, species := range population.species {
 
 		// We need to create a unique hash for the species.
-		// Make an md5 of the genome.
-
-		// Get the gnome as json.
-		var bytes []byte
-		if bytes, err = json.Marshal(species.genome); err != nil {
-			log.Panic(err)
-		}
-		var genomeJson string = string(bytes)
-		var genomeMd5 string = md5Of(genomeJson)
+		var genomeMd5 string = speciesFingerprintOf(species)
 
 		// Species overview.
 		var specimenCount int = len(species.Specimens)
@@ -366,16 +469,18 @@ func (e *geneticExperiment) recordGeneration(generationNum uint64, bestExperimen
 		if result, err = e.db.Exec(
 			`INSERT INTO genetic.experiment_generation_species
          SET experimentid=?,
+             island=?,
              generation_num=?,
              species_fingerprint=?,
              specimens=?,
              best_score=?,
              best=?`,
 			e.experimentId,
+			island,
 			generationNum,
 			genomeMd5,
 			specimenCount,
-			specimenBestScore,
+			sql.NullFloat64{Float64: specimenBestScore, Valid: !math.IsNaN(specimenBestScore)}, // NULL if no one in the species is feasible.
 			specimenBest); err != nil {
 
 			log.Panic(err)
@@ -392,16 +497,16 @@ func (e *geneticExperiment) recordGeneration(generationNum uint64, bestExperimen
 	}
 }
 
-// recordEnd records details about the experiment after it ends.
-func (e *geneticExperiment) recordEnd(generationNum uint64, endReason string, population generationPopulation) {
+// recordEnd records details about the experiment after it ends, with its results (e.g. the species of the population).
+func (e *geneticExperiment) recordEnd(generationNum uint64, endReason string, results interface{}) {
 	var err error
 
-	// Get the population as json.
+	// Get the results as json.
 	var bytes []byte
-	if bytes, err = json.Marshal(population.species); err != nil {
+	if bytes, err = json.Marshal(results); err != nil {
 		log.Panic(err)
 	}
-	var populationJson string = string(bytes)
+	var resultsJson string = string(bytes)
 
 	// Write the core experiment record to the database.
 	var result sql.Result
@@ -415,7 +520,7 @@ func (e *geneticExperiment) recordEnd(generationNum uint64, endReason string, po
 		e.experimentId,
 		endReason,
 		generationNum,
-		populationJson); err != nil {
+		resultsJson); err != nil {
 
 		log.Panic(err)
 	}
@@ -461,6 +566,27 @@ func listenForManualStop(manualStopChannel chan bool) {
 	}
 }
 
+// typeNameOf is the name of the type a pointer points to (e.g. "genetic.sorterSimple"), "" if there is none. Experiments
+// that run without a sorter or selector record them as "".
+func typeNameOf(value interface{}) string {
+	if value == nil || reflect.ValueOf(value).IsNil() {
+		return ""
+	}
+	return reflect.ValueOf(value).Elem().Type().String()
+}
+
+// speciesFingerprintOf identifies a species by an md5 of its genome.
+func speciesFingerprintOf(species genSpecies) string {
+	var err error
+
+	// Get the gnome as json.
+	var bytes []byte
+	if bytes, err = json.Marshal(species.genome); err != nil {
+		log.Panic(err)
+	}
+	return md5Of(string(bytes))
+}
+
 // md5Of creates an md5 (as string) for an input string.
 func md5Of(value string) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(value)))
@@ -0,0 +1,218 @@
+package genetic
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+)
+
+// HallOfFameScorer is a Scorer that evaluates against past champions (e.g. a game played against the best players so far).
+// If a scorer implements it, it is given the champions of the hall of fame before each generation starts.
+type HallOfFameScorer interface {
+	Scorer
+
+	// HallOfFame gives the neural nets of the hall of fame, the best first. Empty until there are champions.
+	HallOfFame(champions []NeatNeuralNet)
+}
+
+// hallOfFameChampion is a single member of the hall of fame.
+type hallOfFameChampion struct {
+	Specimen      Specimen // The specimen as it was when it entered the hall of fame.
+	GenomeKey     string   // Identifies the genome, so no genome is in the hall of fame twice.
+	GenerationNum uint64   // The generation it entered the hall of fame.
+}
+
+// hallOfFame is the best distinct genomes ever seen in an experiment, no matter what happened to them since. Champions
+// are ranked by the experiment's sorter. Infeasible specimens (that broke the scorer's constraints) are never champions.
+type hallOfFame struct {
+	size      int                  // The most champions kept.
+	sorter    Sorter               // Ranks the champions, the same as the specimens of a generation.
+	champions []hallOfFameChampion // The champions, the best first.
+}
+
+// newHallOfFame creates a well-formed hall of fame, with no champions yet.
+func newHallOfFame(size int, sorter Sorter) *hallOfFame {
+	return &hallOfFame{size: size, sorter: sorter}
+}
+
+// offer offers the specimens of a generation to the hall of fame, keeping the best. The champions and the specimens
+// offered are ranked together, each as if the only member of its species. A champion offered again is ranked by its
+// latest scores (e.g. averaged over a longer lifetime), but keeps the generation it entered. Returns how many specimens
+// entered.
+func (h *hallOfFame) offer(specimens []Specimen, generationNum uint64) (entered int) {
+	if h.size == 0 {
+		return 0
+	}
+
+	// Everyone in the running, each genome once, the champions first.
+	var contenders map[string]hallOfFameChampion = map[string]hallOfFameChampion{}
+	var contenderKeys []string
+	var wasChampion map[string]bool = map[string]bool{}
+	for _, champion := range h.champions {
+		contenders[champion.GenomeKey] = champion
+		contenderKeys = append(contenderKeys, champion.GenomeKey)
+		wasChampion[champion.GenomeKey] = true
+	}
+	var keys genomeKeyCache = genomeKeyCache{}
+	var isOffered map[string]bool = map[string]bool{}
+	for _, specimen := range specimens {
+		if !specimen.isFeasible() {
+			continue
+		}
+		var genomeKey string = keys.keyOf(specimen)
+		if isOffered[genomeKey] {
+			continue
+		}
+		isOffered[genomeKey] = true
+
+		// A champion is refreshed, anyone else is a new contender.
+		var champion hallOfFameChampion
+		var ok bool
+		if champion, ok = contenders[genomeKey]; ok {
+			champion.Specimen = specimen
+		} else {
+			champion = hallOfFameChampion{Specimen: specimen, GenomeKey: genomeKey, GenerationNum: generationNum}
+			contenderKeys = append(contenderKeys, genomeKey)
+		}
+		contenders[genomeKey] = champion
+	}
+	if len(contenderKeys) == 0 {
+		return 0
+	}
+
+	// Rank them with the sorter, each on its own merits rather than the size of its species.
+	var ranked []Specimen
+	for _, genomeKey := range contenderKeys {
+		var specimen Specimen = contenders[genomeKey].Specimen
+		specimen.SpeciesMemberCount = 1
+		ranked = append(ranked, specimen)
+	}
+	_, _, ranked = h.sorter.Sort(ranked)
+
+	// Keep the best. The sorted specimens are copies, known by the genes they share with the contenders.
+	h.champions = nil
+	for _, specimen := range ranked {
+		if len(h.champions) >= h.size {
+			break
+		}
+		var champion hallOfFameChampion = contenders[keys.keyOf(specimen)]
+		if !wasChampion[champion.GenomeKey] {
+			entered++
+		}
+		h.champions = append(h.champions, champion)
+	}
+	return entered
+}
+
+// neuralNets are the neural nets of the champions, the best first.
+func (h *hallOfFame) neuralNets() (neuralNets []NeatNeuralNet) {
+	for _, champion := range h.champions {
+		neuralNets = append(neuralNets, champion.Specimen.NeuralNet)
+	}
+	return neuralNets
+}
+
+// reinject puts copies of the best champions (up to count, all of them if 0) back into a population, if their genome isn't
+// there already. Returns how many were put back.
+func (h *hallOfFame) reinject(population *generationPopulation, count int) (reinjected int) {
+
+	// What genomes are already in the population?
+	var isInPopulation map[string]bool = map[string]bool{}
+	for _, specimen := range population.allSpecimens() {
+		isInPopulation[genomeKeyOf(specimen)] = true
+	}
+
+	for i, champion := range h.champions {
+		if count > 0 && i >= count {
+			break
+		}
+		if isInPopulation[champion.GenomeKey] {
+			continue
+		}
+		// A copy, not tethered to the champion. It is scored again with everyone else.
+		population.AddNeuralNet(champion.Specimen.NeuralNet.makeClone(), 0.0, 0.0, nil) // The specimen has no scores.
+		reinjected++
+	}
+	return reinjected
+}
+
+// shareHallOfFame gives a scorer the champions so far, if it wants them.
+func (e *geneticExperiment) shareHallOfFame(scorer Scorer) {
+	var hallOfFameScorer HallOfFameScorer
+	var ok bool
+	if hallOfFameScorer, ok = scorer.(HallOfFameScorer); ok {
+		var champions []NeatNeuralNet = []NeatNeuralNet{}
+		if e.hallOfFame != nil {
+			champions = e.hallOfFame.neuralNets()
+		}
+		hallOfFameScorer.HallOfFame(champions)
+	}
+}
+
+// isReinjectGeneration is true if the champions should be put back into the population, after every so many generations
+// without fitness improving.
+func (e *geneticExperiment) isReinjectGeneration(stagnantGenerationCount uint64) bool {
+	if e.hallOfFame == nil || e.config.HallOfFame.ReinjectAfter == 0 || stagnantGenerationCount == 0 {
+		return false
+	}
+	return (stagnantGenerationCount % e.config.HallOfFame.ReinjectAfter) == 0
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigHallOfFame) validOrPanic() {
+	if c.Size < 0 {
+		log.Panicf("HallOfFame Size cannot be negative: %d", c.Size)
+	}
+	if c.ReinjectCount < 0 {
+		log.Panicf("HallOfFame ReinjectCount cannot be negative: %d", c.ReinjectCount)
+	}
+	if c.ReinjectAfter > 0 && c.Size == 0 {
+		log.Panicf("HallOfFame ReinjectAfter needs a Size: %d", c.ReinjectAfter)
+	}
+}
+
+// recordHallOfFame records the champions of the hall of fame, the best first. The island is 0 unless the hall of fame
+// belongs to a single population of the experiment (e.g. coevolution).
+func (e *geneticExperiment) recordHallOfFame(island int, hall *hallOfFame) {
+	var err error
+
+	for i, champion := range hall.champions {
+
+		// Get the specimen as json.
+		var bytes []byte
+		if bytes, err = json.Marshal(champion.Specimen); err != nil {
+			log.Panic(err)
+		}
+
+		// Write the champion to the database.
+		var result sql.Result
+		if result, err = e.db.Exec(
+			`INSERT INTO genetic.experiment_hall_of_fame
+         SET experimentid=?,
+             island=?,
+             place=?,
+             genome_fingerprint=?,
+             generation_num=?,
+             score=?,
+             specimen=?`,
+			e.experimentId,
+			island,
+			i+1,
+			champion.GenomeKey,
+			champion.GenerationNum,
+			champion.Specimen.Score,
+			string(bytes)); err != nil {
+
+			log.Panic(err)
+		}
+
+		// Depending on data in the database zero to two rows may be effected.
+		var rowsAffected int64
+		if rowsAffected, err = result.RowsAffected(); err != nil {
+			log.Panic(err)
+		}
+		if rowsAffected != 1 {
+			log.Panicf("Inserting experiment hall of fame expected 1 row affected but was: %d", rowsAffected)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type HallOfFameSuite struct{}
+
+var _ = Suite(&HallOfFameSuite{})
+
+// hallOfFameTestSpecimen is a specimen with a genome of its own (by the weight) and a score.
+func hallOfFameTestSpecimen(weight float64, score float64) Specimen {
+	var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Type: _GENE_TYPE_CONNECTION, IsEnabled: true, Weight: weight}}}}
+	return Specimen{NeuralNet: neuralNet, Score: score}
+}
+
+// hallOfFameScores are the scores of the champions, best first.
+func hallOfFameScores(hall *hallOfFame) (scores []float64) {
+	scores = []float64{}
+	for _, champion := range hall.champions {
+		scores = append(scores, champion.Specimen.Score)
+	}
+	return scores
+}
+
+// hallOfFameTestScorer remembers the champions it was given.
+type hallOfFameTestScorer struct {
+	behaviorTestScorer
+	champions []NeatNeuralNet
+}
+
+func (s *hallOfFameTestScorer) HallOfFame(champions []NeatNeuralNet) { s.champions = champions }
+
+// Add the tests.
+
+func (s *HallOfFameSuite) Test_Offer(c *C) {
+	var hall *hallOfFame = newHallOfFame(3, NewSorterSimpleMaximize())
+
+	// The best are kept, the best first.
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0), hallOfFameTestSpecimen(0.2, 3.0)}, 1), Equals, 2)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{5.0, 3.0})
+
+	// Once full, only better specimens enter, pushing out the worst.
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.3, 4.0), hallOfFameTestSpecimen(0.4, 1.0), hallOfFameTestSpecimen(0.5, 6.0)}, 2), Equals, 2)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{6.0, 5.0, 4.0})
+	c.Check(hall.champions[0].GenerationNum, Equals, uint64(2))
+	c.Check(hall.champions[0].GenomeKey, Equals, genomeKeyOf(hallOfFameTestSpecimen(0.5, 6.0)))
+
+	// The same genome is never in twice. A champion offered again is ranked by its latest score, but keeps the
+	// generation it entered.
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.3, 5.5), hallOfFameTestSpecimen(0.3, 5.5)}, 3), Equals, 0)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{6.0, 5.5, 5.0})
+	c.Check(hall.champions[1].GenerationNum, Equals, uint64(2))
+
+	// A champion's latest score can push it out.
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.5, 1.0), hallOfFameTestSpecimen(0.2, 3.0)}, 4), Equals, 1)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{5.5, 5.0, 3.0})
+
+	// Not good enough, or infeasible, never enters.
+	var infeasible Specimen = hallOfFameTestSpecimen(0.6, 10.0)
+	infeasible.ConstraintViolation = 1.0
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.7, 2.0), infeasible}, 5), Equals, 0)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{5.5, 5.0, 3.0})
+
+	// Each is ranked on its own, not by the size of its species.
+	var crowded Specimen = hallOfFameTestSpecimen(0.8, 5.2)
+	crowded.SpeciesMemberCount = 4
+	c.Check(hall.offer([]Specimen{crowded}, 6), Equals, 1)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{5.5, 5.2, 5.0})
+
+	// Minimizing keeps the lowest.
+	hall = newHallOfFame(2, NewSorterSimpleMinimize())
+	hall.offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0), hallOfFameTestSpecimen(0.2, 3.0), hallOfFameTestSpecimen(0.3, 4.0)}, 1)
+	c.Check(hallOfFameScores(hall), DeepEquals, []float64{3.0, 4.0})
+
+	// No room at all.
+	hall = newHallOfFame(0, NewSorterSimpleMaximize())
+	c.Check(hall.offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0)}, 1), Equals, 0)
+}
+
+func (s *HallOfFameSuite) Test_Reinject(c *C) {
+	var hall *hallOfFame = newHallOfFame(3, NewSorterSimpleMaximize())
+	hall.offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0), hallOfFameTestSpecimen(0.2, 3.0), hallOfFameTestSpecimen(0.3, 4.0)}, 1)
+	c.Check(hall.neuralNets(), DeepEquals, []NeatNeuralNet{
+		hallOfFameTestSpecimen(0.1, 0.0).NeuralNet,
+		hallOfFameTestSpecimen(0.3, 0.0).NeuralNet,
+		hallOfFameTestSpecimen(0.2, 0.0).NeuralNet,
+	})
+
+	// Champions already in the population aren't put back again. They come back unscored.
+	var population generationPopulation = newPopulation(populationTestConfig(0))
+	population.AddSpecimen(hallOfFameTestSpecimen(0.1, 7.0))
+	c.Check(hall.reinject(&population, 2), Equals, 1)
+	c.Check(len(population.allSpecimens()), Equals, 2)
+	c.Check(population.allSpecimens()[1].NeuralNet.Genome.Genes[0].Weight, Equals, 0.3)
+	c.Check(population.allSpecimens()[1].Score, Equals, 0.0)
+
+	// A count of 0 is all of them.
+	c.Check(hall.reinject(&population, 0), Equals, 1)
+	c.Check(len(population.allSpecimens()), Equals, 3)
+
+	// They are copies.
+	population.allSpecimens()[1].NeuralNet.Genome.Genes[0].Weight = 0.9
+	c.Check(hall.champions[1].Specimen.NeuralNet.Genome.Genes[0].Weight, Equals, 0.3)
+}
+
+func (s *HallOfFameSuite) Test_ShareHallOfFame(c *C) {
+	var scorer *hallOfFameTestScorer = &hallOfFameTestScorer{}
+	var experiment geneticExperiment = geneticExperiment{}
+
+	// Without a hall of fame, there are no champions.
+	experiment.shareHallOfFame(scorer)
+	c.Check(scorer.champions, DeepEquals, []NeatNeuralNet{})
+
+	experiment.hallOfFame = newHallOfFame(2, NewSorterSimpleMaximize())
+	experiment.hallOfFame.offer([]Specimen{hallOfFameTestSpecimen(0.1, 5.0)}, 1)
+	experiment.shareHallOfFame(scorer)
+	c.Check(scorer.champions, DeepEquals, []NeatNeuralNet{hallOfFameTestSpecimen(0.1, 5.0).NeuralNet})
+
+	// Other scorers are left alone.
+	experiment.shareHallOfFame(&behaviorTestScorer{})
+}
+
+func (s *HallOfFameSuite) Test_IsReinjectGeneration(c *C) {
+	var experiment geneticExperiment = geneticExperiment{config: Config{HallOfFame: ConfigHallOfFame{Size: 2, ReinjectAfter: 3}}}
+	c.Check(experiment.isReinjectGeneration(3), Equals, false) // No hall of fame.
+
+	experiment.hallOfFame = newHallOfFame(2, NewSorterSimpleMaximize())
+	c.Check(experiment.isReinjectGeneration(0), Equals, false)
+	c.Check(experiment.isReinjectGeneration(2), Equals, false)
+	c.Check(experiment.isReinjectGeneration(3), Equals, true)
+	c.Check(experiment.isReinjectGeneration(4), Equals, false)
+	c.Check(experiment.isReinjectGeneration(6), Equals, true)
+
+	experiment.config.HallOfFame.ReinjectAfter = 0
+	c.Check(experiment.isReinjectGeneration(3), Equals, false)
+}
+
+func (s *HallOfFameSuite) Test_ConfigHallOfFame_ValidOrPanic(c *C) {
+	var config ConfigHallOfFame
+
+	config = ConfigHallOfFame{}
+	config.validOrPanic() // Valid, no hall of fame.
+	config = ConfigHallOfFame{Size: 5, ReinjectAfter: 10, ReinjectCount: 2}
+	config.validOrPanic() // Valid.
+
+	config = ConfigHallOfFame{Size: -1}
+	c.Check(func() { config.validOrPanic() }, Panics, "HallOfFame Size cannot be negative: -1")
+
+	config = ConfigHallOfFame{Size: 5, ReinjectCount: -1}
+	c.Check(func() { config.validOrPanic() }, Panics, "HallOfFame ReinjectCount cannot be negative: -1")
+
+	config = ConfigHallOfFame{ReinjectAfter: 10}
+	c.Check(func() { config.validOrPanic() }, Panics, "HallOfFame ReinjectAfter needs a Size: 10")
+}
@@ -0,0 +1,343 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+)
+
+const (
+	// The inputs of a HyperNEAT CPPN are the coordinates of the two substrate nodes being connected.
+	HYPERNEAT_CPPN_X1 = "x1"
+	HYPERNEAT_CPPN_Y1 = "y1"
+	HYPERNEAT_CPPN_Z1 = "z1"
+	HYPERNEAT_CPPN_X2 = "x2"
+	HYPERNEAT_CPPN_Y2 = "y2"
+	HYPERNEAT_CPPN_Z2 = "z2"
+
+	// The outputs of a HyperNEAT CPPN are the weight of the connection between the two nodes, and the weight of
+	// the bias into the second node (queried with the first node at the origin).
+	HYPERNEAT_CPPN_WEIGHT = "weight"
+	HYPERNEAT_CPPN_BIAS   = "bias"
+)
+
+// Substrate is where the nodes of a HyperNEAT phenotype sit in space. The CPPN is asked for the weight of each connection
+// by the coordinates of the nodes at each end, so connections follow the geometry of the problem (e.g. a board game board).
+type Substrate struct {
+	Dimensions int                  // 2 or 3, how many coordinates each node has.
+	Inputs     map[string][]float64 // The coordinates of each phenotype input, keyed by input name (one-hot inputs by category input name).
+	Hidden     [][][]float64        // Layers of hidden node coordinates. Each layer is fully connected to the next.
+	Outputs    map[string][]float64 // The coordinates of each phenotype output, keyed by output name.
+}
+
+// HyperNeat decodes an evolved neural net, treated as a compositional pattern producing network (CPPN), into a much
+// larger fixed-topology phenotype neural net laid out on a substrate. Inputs connect to the first hidden layer, each
+// hidden layer to the next, and the last hidden layer to the outputs (or inputs straight to outputs without hidden layers).
+// Every hidden node and output also gets a bias connection.
+//
+// The experiment's NeuralNetInOut must be the CPPN's in/out, from CppnInOut().
+//
+// Reference: http://eplex.cs.ucf.edu/hyperNEATpage/
+type HyperNeat struct {
+	Substrate       Substrate      // Where the phenotype's nodes are.
+	InOut           NeuralNetInOut // The phenotype's in/out, what the scorer sees.
+	WeightThreshold float64        // CPPN weights with a size at or below this (0.0 to less than 1.0) make no connection.
+	MaxWeight       float64        // The largest weight of any phenotype connection.
+	HiddenFunction  string         // The activation function of every hidden node.
+}
+
+// LoadHyperNeatConfig loads the json filename as a new configuration.
+func LoadHyperNeatConfig(filename string) (HyperNeat, error) {
+	var err error
+	var bytes []byte
+	var hyperNeat HyperNeat
+
+	log.Printf("Loading HyperNEAT Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return HyperNeat{}, err
+	}
+	if err = json.Unmarshal(bytes, &hyperNeat); err != nil {
+		return HyperNeat{}, err
+	}
+	hyperNeat.validOrPanic()
+	return hyperNeat, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (h *HyperNeat) validOrPanic() {
+	h.InOut.validate()
+
+	if h.Substrate.Dimensions != 2 && h.Substrate.Dimensions != 3 {
+		log.Panicf("Substrate Dimensions must be 2 or 3: %d", h.Substrate.Dimensions)
+	}
+	if h.WeightThreshold < 0.0 || h.WeightThreshold >= 1.0 {
+		log.Panicf("WeightThreshold must be from 0.0 to less than 1.0: %f", h.WeightThreshold)
+	}
+	if h.MaxWeight <= 0.0 {
+		log.Panicf("MaxWeight must be positive: %f", h.MaxWeight)
+	}
+	if !isActivationFunction(h.HiddenFunction) {
+		log.Panicf("HiddenFunction is not an activation function: '%s'", h.HiddenFunction)
+	}
+
+	// Every input and output of the phenotype sits somewhere on the substrate, and nothing else does.
+	var networkInputs []string = h.InOut.networkInputs()
+	if len(h.Substrate.Inputs) != len(networkInputs) {
+		log.Panicf("Substrate Inputs (%d) must match the in/out inputs: %v", len(h.Substrate.Inputs), networkInputs)
+	}
+	for _, in := range networkInputs {
+		var ok bool
+		if _, ok = h.Substrate.Inputs[in]; !ok {
+			log.Panicf("Substrate has no coordinates for input '%s'", in)
+		}
+	}
+	if len(h.Substrate.Outputs) != len(h.InOut.Outputs) {
+		log.Panicf("Substrate Outputs (%d) must match the in/out outputs: %v", len(h.Substrate.Outputs), h.InOut.Outputs)
+	}
+	for _, out := range h.InOut.Outputs {
+		var ok bool
+		if _, ok = h.Substrate.Outputs[out]; !ok {
+			log.Panicf("Substrate has no coordinates for output '%s'", out)
+		}
+	}
+
+	// Every node has the right number of coordinates.
+	var nodes [][]float64
+	for _, coordinates := range h.Substrate.Inputs {
+		nodes = append(nodes, coordinates)
+	}
+	for _, layer := range h.Substrate.Hidden {
+		if len(layer) == 0 {
+			log.Panic("Substrate has an empty Hidden layer")
+		}
+		nodes = append(nodes, layer...)
+	}
+	for _, coordinates := range h.Substrate.Outputs {
+		nodes = append(nodes, coordinates)
+	}
+	for _, coordinates := range nodes {
+		if len(coordinates) != h.Substrate.Dimensions {
+			log.Panicf("Substrate node %v must have %d coordinates", coordinates, h.Substrate.Dimensions)
+		}
+	}
+}
+
+// CppnInOut is the in/out of the CPPNs the experiment evolves: the coordinates of two nodes in, the connection weight and
+// bias weight out.
+func (h *HyperNeat) CppnInOut() (inOut NeuralNetInOut) {
+	inOut = NeuralNetInOut{
+		Inputs:  []string{HYPERNEAT_CPPN_X1, HYPERNEAT_CPPN_Y1, HYPERNEAT_CPPN_X2, HYPERNEAT_CPPN_Y2},
+		Outputs: []string{HYPERNEAT_CPPN_BIAS, HYPERNEAT_CPPN_WEIGHT},
+	}
+	if h.Substrate.Dimensions == 3 {
+		inOut.Inputs = append(inOut.Inputs, HYPERNEAT_CPPN_Z1, HYPERNEAT_CPPN_Z2)
+	}
+	inOut.validate()
+	return inOut
+}
+
+// substrateNode is a node of the phenotype and where it sits.
+type substrateNode struct {
+	nodeId      string
+	coordinates []float64
+}
+
+// Decode queries the CPPN for every connection of the substrate, building the phenotype neural net.
+//
+// Only connections with a CPPN weight larger than the threshold are expressed, scaled so the weakest is near 0.0 and the
+// strongest is the max weight. A hidden node left with nothing feeding it is dropped, along with everything it would have
+// fed. An output left with nothing feeding it gets a zero weight bias connection so it can still be computed. Finally,
+// hidden nodes that cannot reach an output are simplified away. The phenotype's gene ids are its own, and have nothing to
+// do with the gene ids of the experiment.
+func (h *HyperNeat) Decode(genotype NeatNeuralNet) (phenotype NeatNeuralNet) {
+	var cppn NeatNeuralNet = genotype.makeClone() // Computing builds a topology on the neural net, keep it off the genotype.
+
+	phenotype = NeatNeuralNet{
+		InOut:  h.InOut,
+		Genome: neatGenome{},
+	}
+
+	// Phenotype gene ids count up from 1.
+	var geneId uint64
+	var addGene = func(gene neatGene) {
+		geneId++
+		gene.GeneId = geneId
+		gene.IsEnabled = true
+		phenotype.Genome.Genes = append(phenotype.Genome.Genes, gene)
+	}
+
+	// The layers of nodes, in order. Inputs sorted by name so the phenotype is always the same.
+	var layers [][]substrateNode
+	var inputLayer []substrateNode
+	for _, in := range h.InOut.networkInputs() {
+		inputLayer = append(inputLayer, substrateNode{nodeId: in, coordinates: h.Substrate.Inputs[in]})
+	}
+	layers = append(layers, inputLayer)
+	for _, hiddenLayer := range h.Substrate.Hidden {
+		var layer []substrateNode
+		for _, coordinates := range hiddenLayer {
+			addGene(neatGene{Type: _GENE_TYPE_NODE, Function: h.HiddenFunction})
+			layer = append(layer, substrateNode{nodeId: strconv.FormatUint(geneId, _BASE_10), coordinates: coordinates})
+		}
+		layers = append(layers, layer)
+	}
+	var outputLayer []substrateNode
+	for _, out := range h.InOut.Outputs {
+		outputLayer = append(outputLayer, substrateNode{nodeId: out, coordinates: h.Substrate.Outputs[out]})
+	}
+	layers = append(layers, outputLayer)
+
+	// Inputs and the bias always have a value. Other nodes only do if something feeds them.
+	var isFed map[string]bool = map[string]bool{NODE_BIAS: true}
+	for _, node := range inputLayer {
+		isFed[node.nodeId] = true
+	}
+
+	// Query the CPPN for every connection, layer by layer.
+	var origin []float64 = make([]float64, h.Substrate.Dimensions)
+	for i := 1; i < len(layers); i++ {
+		for _, to := range layers[i] {
+
+			// The bias is queried as if coming from the origin.
+			var weight float64
+			var ok bool
+			if weight, ok = h.expressedWeight(h.queryCppn(&cppn, origin, to.coordinates)[HYPERNEAT_CPPN_BIAS]); ok {
+				addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: to.nodeId, Weight: weight})
+				isFed[to.nodeId] = true
+			}
+
+			for _, from := range layers[i-1] {
+				// Nodes with nothing feeding them are dropped, so they feed nothing.
+				if !isFed[from.nodeId] {
+					continue
+				}
+				if weight, ok = h.expressedWeight(h.queryCppn(&cppn, from.coordinates, to.coordinates)[HYPERNEAT_CPPN_WEIGHT]); ok {
+					addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: from.nodeId, To: to.nodeId, Weight: weight})
+					isFed[to.nodeId] = true
+				}
+			}
+
+			// An output must have something feeding it to be computed.
+			if i == len(layers)-1 && !isFed[to.nodeId] {
+				addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: to.nodeId, Weight: 0.0})
+				isFed[to.nodeId] = true
+			}
+		}
+	}
+
+	// Drop hidden nodes with nothing feeding them.
+	var fedGenes []neatGene
+	for _, gene := range phenotype.Genome.Genes {
+		if gene.Type == _GENE_TYPE_NODE && !isFed[strconv.FormatUint(gene.GeneId, _BASE_10)] {
+			continue
+		}
+		fedGenes = append(fedGenes, gene)
+	}
+	phenotype.Genome.Genes = fedGenes
+
+	// Drop hidden nodes that cannot reach an output.
+	phenotype, _ = phenotype.Simplify()
+	return phenotype
+}
+
+// queryCppn computes the CPPN for a connection between two substrate nodes.
+func (h *HyperNeat) queryCppn(cppn *NeatNeuralNet, from []float64, to []float64) (outputs map[string]float64) {
+	var inputs map[string]float64 = map[string]float64{
+		HYPERNEAT_CPPN_X1: from[0],
+		HYPERNEAT_CPPN_Y1: from[1],
+		HYPERNEAT_CPPN_X2: to[0],
+		HYPERNEAT_CPPN_Y2: to[1],
+	}
+	if h.Substrate.Dimensions == 3 {
+		inputs[HYPERNEAT_CPPN_Z1] = from[2]
+		inputs[HYPERNEAT_CPPN_Z2] = to[2]
+	}
+	return cppn.Compute(inputs)
+}
+
+// expressedWeight turns a CPPN weight into a phenotype connection weight. CPPN weights are clipped to -1.0 to 1.0. Only weights
+// larger than the threshold are expressed, scaled from the threshold up to the max weight.
+func (h *HyperNeat) expressedWeight(cppnWeight float64) (weight float64, isExpressed bool) {
+	if math.IsNaN(cppnWeight) {
+		return 0.0, false
+	}
+	var size float64 = math.Min(math.Abs(cppnWeight), 1.0)
+	if size <= h.WeightThreshold {
+		return 0.0, false
+	}
+	weight = (size - h.WeightThreshold) / (1.0 - h.WeightThreshold) * h.MaxWeight
+	if cppnWeight < 0.0 {
+		weight = -weight
+	}
+	return weight, true
+}
+
+// PhenotypeDecoder turns an evolved neural net (the genotype) into the neural net that is actually scored (the phenotype).
+type PhenotypeDecoder interface {
+	Decode(genotype NeatNeuralNet) (phenotype NeatNeuralNet)
+}
+
+// PhenotypeScorer scores the phenotypes of the neural nets being evolved. The whole population is decoded once per
+// generation, so the scorer sees phenotypes for both the neural net being scored and the population around it.
+type PhenotypeScorer struct {
+	Decoder PhenotypeDecoder // How to turn each genotype into a phenotype.
+	Scorer  Scorer           // The scorer of the phenotypes.
+
+	population []NeatNeuralNet // The genotypes the phenotypes were decoded from.
+	phenotypes []NeatNeuralNet // The decoded phenotypes.
+}
+
+// NewPhenotypeScorer creates a scorer that decodes each neural net before scoring it.
+func NewPhenotypeScorer(decoder PhenotypeDecoder, scorer Scorer) *PhenotypeScorer {
+	return &PhenotypeScorer{
+		Decoder: decoder,
+		Scorer:  scorer,
+	}
+}
+
+// Score the phenotype of the neural net.
+func (s *PhenotypeScorer) Score(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (score float64, bonus float64, outcomes []float64) {
+	s.decode(population)
+	return s.Scorer.Score(s.phenotypes[neuralNetIndex], s.phenotypes, neuralNetIndex)
+}
+
+// ScoreResult scores the phenotype of the neural net, with a behavior if the scorer reports one.
+func (s *PhenotypeScorer) ScoreResult(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (result ScoreResult) {
+	s.decode(population)
+	return scoreNeuralNet(s.Scorer, s.phenotypes[neuralNetIndex], s.phenotypes, neuralNetIndex)
+}
+
+// decode decodes the population if it is not the one already decoded.
+func (s *PhenotypeScorer) decode(population []NeatNeuralNet) {
+	if !s.isDecoded(population) {
+		s.population = population
+		s.phenotypes = nil
+		for _, genotype := range population {
+			s.phenotypes = append(s.phenotypes, s.Decoder.Decode(genotype))
+		}
+	}
+}
+
+// isDecoded is true if the population is the same population already decoded.
+func (s *PhenotypeScorer) isDecoded(population []NeatNeuralNet) bool {
+	if len(population) == 0 || len(population) != len(s.population) {
+		return false
+	}
+	return &population[0] == &s.population[0]
+}
+
+// GenerationStart forgets the last generation's phenotypes.
+func (s *PhenotypeScorer) GenerationStart(generationNum uint64) {
+	s.population = nil
+	s.phenotypes = nil
+	s.Scorer.GenerationStart(generationNum)
+}
+
+// GenerationDetails are the details of the phenotype scorer.
+func (s *PhenotypeScorer) GenerationDetails() (json []byte) {
+	return s.Scorer.GenerationDetails()
+}
@@ -0,0 +1,225 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type HyperNeatSuite struct{}
+
+var _ = Suite(&HyperNeatSuite{})
+
+// hyperNeatTestConfig is a small 2D substrate: two inputs along the bottom, one hidden node in the middle, one output at the top.
+func hyperNeatTestConfig() HyperNeat {
+	var hyperNeat HyperNeat = HyperNeat{
+		Substrate: Substrate{
+			Dimensions: 2,
+			Inputs:     map[string][]float64{"left": []float64{-1.0, -1.0}, "right": []float64{1.0, -1.0}},
+			Hidden:     [][][]float64{[][]float64{[]float64{0.0, 0.0}}},
+			Outputs:    map[string][]float64{"out": []float64{0.0, 1.0}},
+		},
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"left", "right"},
+			Outputs: []string{"out"},
+		},
+		WeightThreshold: 0.2,
+		MaxWeight:       3.0,
+		HiddenFunction:  ACTIVATION_SINE,
+	}
+	hyperNeat.validOrPanic()
+	return hyperNeat
+}
+
+// hyperNeatTestCppn is a CPPN with weight = x1 + y2 and the given bias weight from the bias node.
+func hyperNeatTestCppn(hyperNeat HyperNeat, bias float64) NeatNeuralNet {
+	return NeatNeuralNet{
+		InOut: hyperNeat.CppnInOut(),
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_X1, To: HYPERNEAT_CPPN_WEIGHT, Weight: 1.0},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: HYPERNEAT_CPPN_Y2, To: HYPERNEAT_CPPN_WEIGHT, Weight: 1.0},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_BIAS, Weight: bias},
+		}},
+	}
+}
+
+// Add the tests.
+
+func (s *HyperNeatSuite) Test_HyperNeat_CppnInOut(c *C) {
+	var hyperNeat HyperNeat = hyperNeatTestConfig()
+	c.Check(hyperNeat.CppnInOut(), DeepEquals, NeuralNetInOut{
+		Inputs:  []string{"x1", "x2", "y1", "y2"},
+		Outputs: []string{"bias", "weight"},
+	})
+
+	hyperNeat.Substrate.Dimensions = 3
+	c.Check(hyperNeat.CppnInOut(), DeepEquals, NeuralNetInOut{
+		Inputs:  []string{"x1", "x2", "y1", "y2", "z1", "z2"},
+		Outputs: []string{"bias", "weight"},
+	})
+}
+
+func (s *HyperNeatSuite) Test_HyperNeat_ExpressedWeight(c *C) {
+	var hyperNeat HyperNeat = hyperNeatTestConfig()
+
+	var weight float64
+	var isExpressed bool
+	weight, isExpressed = hyperNeat.expressedWeight(0.2)
+	c.Check(isExpressed, Equals, false)
+	weight, isExpressed = hyperNeat.expressedWeight(-0.1)
+	c.Check(isExpressed, Equals, false)
+	weight, isExpressed = hyperNeat.expressedWeight(0.6)
+	c.Check(isExpressed, Equals, true)
+	c.Check(weight > 1.4999 && weight < 1.5001, Equals, true)
+	weight, isExpressed = hyperNeat.expressedWeight(-5.0) // Clipped.
+	c.Check(isExpressed, Equals, true)
+	c.Check(weight, Equals, -3.0)
+}
+
+func (s *HyperNeatSuite) Test_HyperNeat_Decode(c *C) {
+	var hyperNeat HyperNeat = hyperNeatTestConfig()
+	var cppn NeatNeuralNet = hyperNeatTestCppn(hyperNeat, 0.0) // No bias connections.
+
+	// left -> hidden: x1 = -1, y2 = 0, weight -1
+	// right -> hidden: x1 = 1, y2 = 0, weight 1
+	// hidden -> out: x1 = 0, y2 = 1, weight 1
+	var phenotype NeatNeuralNet = hyperNeat.Decode(cppn)
+	c.Check(phenotype.InOut, DeepEquals, hyperNeat.InOut)
+	c.Check(phenotype.Genome, DeepEquals, neatGenome{Genes: []neatGene{
+		neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+		neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "left", To: "1", Weight: -3.0},
+		neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "right", To: "1", Weight: 3.0},
+		neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: "out", Weight: 3.0},
+	}})
+
+	// The genotype is untouched.
+	c.Check(cppn.topology.orderedNodes, IsNil)
+
+	// The phenotype computes.
+	var outputs map[string]float64 = phenotype.Compute(map[string]float64{"left": 0.5, "right": 0.25})
+	c.Check(outputs["out"], Equals, 3.0*activationSine(-3.0*0.5+3.0*0.25))
+
+	// With a strong bias, every node gets a bias connection.
+	phenotype = hyperNeat.Decode(hyperNeatTestCppn(hyperNeat, -1.0))
+	c.Check(phenotype.Genome, DeepEquals, neatGenome{Genes: []neatGene{
+		neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+		neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: "1", Weight: -3.0},
+		neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "left", To: "1", Weight: -3.0},
+		neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "right", To: "1", Weight: 3.0},
+		neatGene{GeneId: 5, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: "out", Weight: -3.0},
+		neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: "out", Weight: 3.0},
+	}})
+}
+
+func (s *HyperNeatSuite) Test_HyperNeat_Decode_Unconnected(c *C) {
+	var hyperNeat HyperNeat = hyperNeatTestConfig()
+
+	// A CPPN that never expresses a connection.
+	var cppn NeatNeuralNet = NeatNeuralNet{
+		InOut: hyperNeat.CppnInOut(),
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_WEIGHT, Weight: 0.1},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: HYPERNEAT_CPPN_BIAS, Weight: 0.1},
+		}},
+	}
+
+	// The hidden node is dropped and the output gets a zero weight bias so it still computes.
+	var phenotype NeatNeuralNet = hyperNeat.Decode(cppn)
+	c.Check(phenotype.Genome, DeepEquals, neatGenome{Genes: []neatGene{
+		neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: "out", Weight: 0.0},
+	}})
+	c.Check(phenotype.Compute(map[string]float64{"left": 0.5, "right": 0.25}), DeepEquals, map[string]float64{"out": 0.0})
+}
+
+func (s *HyperNeatSuite) Test_HyperNeat_ValidOrPanic(c *C) {
+	var hyperNeat HyperNeat
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.Substrate.Dimensions = 4
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "Substrate Dimensions must be 2 or 3: 4")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.WeightThreshold = 1.0
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "WeightThreshold must be from 0.0 to less than 1.0: 1.000000")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.MaxWeight = 0.0
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "MaxWeight must be positive: 0.000000")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.HiddenFunction = "unknown"
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "HiddenFunction is not an activation function: 'unknown'")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.Substrate.Inputs = map[string][]float64{"left": []float64{-1.0, -1.0}, "middle": []float64{0.0, -1.0}}
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "Substrate has no coordinates for input 'right'")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.Substrate.Outputs = map[string][]float64{}
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "Substrate Outputs (0) must match the in/out outputs: [out]")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.Substrate.Hidden = [][][]float64{[][]float64{}}
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "Substrate has an empty Hidden layer")
+
+	hyperNeat = hyperNeatTestConfig()
+	hyperNeat.Substrate.Hidden = [][][]float64{[][]float64{[]float64{0.0, 0.0, 0.0}}}
+	c.Check(func() { hyperNeat.validOrPanic() }, Panics, "Substrate node [0 0 0] must have 2 coordinates")
+}
+
+// phenotypeTestDecoder counts decodes and names each phenotype's output after the decode.
+type phenotypeTestDecoder struct {
+	decodes int
+}
+
+func (d *phenotypeTestDecoder) Decode(genotype NeatNeuralNet) (phenotype NeatNeuralNet) {
+	d.decodes++
+	phenotype = genotype.makeClone()
+	phenotype.Genome.Genes[0].Weight = float64(d.decodes)
+	return phenotype
+}
+
+// phenotypeTestScorer scores a neural net by its first weight.
+type phenotypeTestScorer struct {
+	generationNum uint64
+	populations   [][]NeatNeuralNet
+}
+
+func (s *phenotypeTestScorer) Score(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (score float64, bonus float64, outcomes []float64) {
+	s.populations = append(s.populations, population)
+	return neuralNet.Genome.Genes[0].Weight, 0.0, nil
+}
+func (s *phenotypeTestScorer) GenerationStart(generationNum uint64) { s.generationNum = generationNum }
+func (s *phenotypeTestScorer) GenerationDetails() (json []byte)     { return []byte("details") }
+
+func (s *HyperNeatSuite) Test_PhenotypeScorer(c *C) {
+	var population []NeatNeuralNet
+	for i := 0; i < 3; i++ {
+		population = append(population, NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.5}}}})
+	}
+
+	var decoder *phenotypeTestDecoder = &phenotypeTestDecoder{}
+	var innerScorer *phenotypeTestScorer = &phenotypeTestScorer{}
+	var scorer Scorer = NewPhenotypeScorer(decoder, innerScorer)
+
+	// The whole population is decoded once, on the first score of the generation.
+	scorer.GenerationStart(1)
+	c.Check(innerScorer.generationNum, Equals, uint64(1))
+	var score float64
+	for i := range population {
+		score, _, _ = scorer.Score(population[i], population, i)
+		c.Check(score, Equals, float64(i+1))
+	}
+	c.Check(decoder.decodes, Equals, 3)
+	c.Check(len(innerScorer.populations), Equals, 3)
+	c.Check(innerScorer.populations[2][0].Genome.Genes[0].Weight, Equals, 1.0) // Phenotypes, not genotypes.
+	c.Check(string(scorer.GenerationDetails()), Equals, "details")
+
+	// The genotypes are untouched.
+	c.Check(population[0].Genome.Genes[0].Weight, Equals, 0.5)
+
+	// A new generation decodes again.
+	scorer.GenerationStart(2)
+	score, _, _ = scorer.Score(population[0], population, 0)
+	c.Check(score, Equals, 4.0)
+	c.Check(decoder.decodes, Equals, 6)
+}
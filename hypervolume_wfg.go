@@ -0,0 +1,142 @@
+package genetic
+
+import (
+	"sort"
+)
+
+// calculateExactHypervolumeIndicators computes the exact hypervolume indicator of each specimen in a population with the
+// WFG algorithm.
+//
+// calculateHypervolumeIndicators shrinks a single base corner for each hypercube, which is exact in two dimensions. In
+// three or more dimensions the part of a hypercube left over after the overlaps is usually not a box, and a single corner
+// only approximates it. Here the indicator is the hypercube's volume less the volume of the union of its overlaps with
+// every other hypercube, which is exact in any number of dimensions.
+//
+// Unlike the approximation, a hypercube that contains all the others has its whole exclusive volume as its indicator.
+// Hypercubes wholely inside another (or equal to another) have no exclusive volume and are dominated.
+//
+// Reference: While, L., Bradstreet, L. and Barone, L. (2012) A Fast Way of Calculating Exact Hypervolumes.
+func calculateExactHypervolumeIndicators(hypercubes []*specimenHypercube) {
+	for i, hypercube := range hypercubes {
+
+		// The overlap of this hypercube with each of the others is itself a hypercube from the origin.
+		var overlaps [][]float64
+		for j, other := range hypercubes {
+			if j != i {
+				overlaps = append(overlaps, hypervolumeOverlap(hypercube.dimensions, other.dimensions))
+			}
+		}
+
+		// What is left is the indicator.
+		var volume float64 = hypervolumeOfBox(hypercube.dimensions)
+		hypercube.indicator = volume - wfgHypervolume(overlaps)
+		if hypercube.indicator <= 0.0 {
+			hypercube.setDominated()
+		} else {
+			hypercube.isDominated = false
+		}
+	}
+}
+
+// wfgHypervolume is the exact volume of the union of boxes that all have the origin as a corner, each box given by its
+// opposite corner.
+func wfgHypervolume(points [][]float64) (volume float64) {
+	points = nondominatedPoints(points)
+	if len(points) == 0 {
+		return 0.0
+	}
+
+	// Two dimensions is a simple sweep. With the points sorted descending by the first dimension, the second dimension is
+	// ascending, and each point adds a strip.
+	if len(points[0]) == 2 {
+		var height float64
+		for _, point := range points {
+			volume += point[0] * (point[1] - height)
+			height = point[1]
+		}
+		return volume
+	}
+
+	// Each point adds the volume not already covered by the points after it.
+	for i, point := range points {
+		var limited [][]float64
+		for _, later := range points[i+1:] {
+			limited = append(limited, hypervolumeOverlap(point, later))
+		}
+		volume += hypervolumeOfBox(point) - wfgHypervolume(limited)
+	}
+	return volume
+}
+
+// nondominatedPoints are the points whose boxes are not inside another point's box, sorted descending by the first
+// dimension (then the rest). Boxes with no volume and repeats are dropped.
+func nondominatedPoints(points [][]float64) (nondominated [][]float64) {
+	var sorted [][]float64
+	for _, point := range points {
+		if hypervolumeOfBox(point) > 0.0 {
+			sorted = append(sorted, point)
+		}
+	}
+	sort.Sort(byPointDescending(sorted))
+
+	// A point can only be inside the box of a point earlier in the sorted list.
+	for _, point := range sorted {
+		var isInside bool
+		for _, kept := range nondominated {
+			if isPointInsideBox(point, kept) {
+				isInside = true
+				break
+			}
+		}
+		if !isInside {
+			nondominated = append(nondominated, point)
+		}
+	}
+	return nondominated
+}
+
+// isPointInsideBox is true if the box of point is wholely inside (or the same as) the box of other.
+func isPointInsideBox(point []float64, other []float64) bool {
+	for i := range point {
+		if point[i] > other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hypervolumeOverlap is the corner of the box where two boxes from the origin overlap.
+func hypervolumeOverlap(a []float64, b []float64) (overlap []float64) {
+	for i := range a {
+		if a[i] < b[i] {
+			overlap = append(overlap, a[i])
+		} else {
+			overlap = append(overlap, b[i])
+		}
+	}
+	return overlap
+}
+
+// hypervolumeOfBox is the volume of the box from the origin to a point.
+func hypervolumeOfBox(point []float64) (volume float64) {
+	volume = 1.0
+	for _, length := range point {
+		volume *= length
+	}
+	return volume
+}
+
+// byPointDescending implements sort.Interface to sort points descending by the first dimension, then the next, and so on.
+// Example: sort.Sort(byPointDescending(points))
+type byPointDescending [][]float64
+
+func (a byPointDescending) Len() int      { return len(a) }
+func (a byPointDescending) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byPointDescending) Less(i, j int) bool {
+	for d := range a[i] {
+		if a[i][d] != a[j][d] {
+			return a[i][d] > a[j][d]
+		}
+	}
+	return false
+}
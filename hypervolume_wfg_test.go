@@ -0,0 +1,218 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type HypervolumeWfgSuite struct{}
+
+var _ = Suite(&HypervolumeWfgSuite{})
+
+// Add the tests.
+
+func (s *HypervolumeWfgSuite) Test_WfgHypervolume(c *C) {
+
+	// Nothing.
+	c.Check(wfgHypervolume(nil), Equals, 0.0)
+
+	// A single box.
+	c.Check(wfgHypervolume([][]float64{[]float64{2.0, 3.0, 4.0}}), Equals, 24.0)
+
+	// A staircase in two dimensions, with a dominated point, a repeat, and a point with no volume.
+	c.Check(wfgHypervolume([][]float64{
+		[]float64{1.0, 3.0},
+		[]float64{2.0, 2.0},
+		[]float64{3.0, 1.0},
+		[]float64{1.0, 1.0},
+		[]float64{2.0, 2.0},
+		[]float64{9.0, 0.0},
+	}), Equals, 6.0)
+
+	// Three boxes of 3 that overlap each other by 1, all at the same 1: 9 - 3 + 1.
+	c.Check(wfgHypervolume([][]float64{
+		[]float64{1.0, 1.0, 3.0},
+		[]float64{1.0, 3.0, 1.0},
+		[]float64{3.0, 1.0, 1.0},
+	}), Equals, 7.0)
+
+	// A linear front in three dimensions.
+	c.Check(wfgHypervolume([][]float64{
+		[]float64{1.0, 2.0, 3.0},
+		[]float64{2.0, 3.0, 1.0},
+		[]float64{3.0, 1.0, 2.0},
+	}), Equals, 13.0)
+
+	// Random fronts in three and four dimensions, against counting unit cubes.
+	rand.Seed(1)
+	for dimensions := 3; dimensions <= 4; dimensions++ {
+		for trial := 0; trial < 10; trial++ {
+			var points [][]float64 = randomIntegerPoints(8, dimensions, 5)
+			c.Check(wfgHypervolume(points), Equals, countUnitCubes(points, nil), Commentf("%v", points))
+		}
+	}
+}
+
+func (s *HypervolumeWfgSuite) Test_CalculateExactHypervolumeIndicators(c *C) {
+
+	// The two dimensional problem from calculateHypervolumeIndicators is the same, except the cube that contains all the
+	// others (there isn't one here).
+	var hypercubes []*specimenHypercube = []*specimenHypercube{
+		&specimenHypercube{dimensions: []float64{2.0, 1.0}},
+		&specimenHypercube{dimensions: []float64{1.5, 1.5}},
+		&specimenHypercube{dimensions: []float64{1.0, 2.0}},
+		&specimenHypercube{dimensions: []float64{0.5, 1.3}},
+	}
+	calculateExactHypervolumeIndicators(hypercubes)
+	c.Check(hypercubes[0].indicator, Equals, 0.5)
+	c.Check(hypercubes[1].indicator, Equals, 0.25)
+	c.Check(hypercubes[2].indicator, Equals, 0.5)
+	c.Check(hypercubes[3].indicator, Equals, 0.0)
+	c.Check(hypercubes[3].isDominated, Equals, true)
+
+	// Both approximations agree in two dimensions.
+	var approximated []*specimenHypercube
+	for _, hypercube := range hypercubes {
+		approximated = append(approximated, &specimenHypercube{dimensions: hypercube.dimensions, indicatorBase: []float64{0.0, 0.0}})
+	}
+	calculateHypervolumeIndicators(append([]*specimenHypercube{}, approximated...)) // The k-d tree reorders the slice.
+	for i := range hypercubes {
+		c.Check(approximated[i].indicator, Equals, hypercubes[i].indicator)
+		c.Check(approximated[i].isDominated, Equals, hypercubes[i].isDominated)
+	}
+
+	// Equal cubes have nothing of their own.
+	hypercubes = []*specimenHypercube{
+		&specimenHypercube{dimensions: []float64{1.0, 2.0, 3.0}},
+		&specimenHypercube{dimensions: []float64{1.0, 2.0, 3.0}},
+	}
+	calculateExactHypervolumeIndicators(hypercubes)
+	c.Check(hypercubes[0].isDominated, Equals, true)
+	c.Check(hypercubes[1].isDominated, Equals, true)
+
+	// Random fronts in three and four dimensions, against counting unit cubes.
+	rand.Seed(2)
+	for dimensions := 3; dimensions <= 4; dimensions++ {
+		for trial := 0; trial < 10; trial++ {
+			var points [][]float64 = randomIntegerPoints(6, dimensions, 5)
+			hypercubes = nil
+			for _, point := range points {
+				hypercubes = append(hypercubes, &specimenHypercube{dimensions: point})
+			}
+			calculateExactHypervolumeIndicators(hypercubes)
+			for i := range hypercubes {
+				c.Check(hypercubes[i].indicator, Equals, countUnitCubes(points[i:i+1], append(append([][]float64{}, points[:i]...), points[i+1:]...)), Commentf("%v %d", points, i))
+			}
+		}
+	}
+}
+
+func (s *HypervolumeWfgSuite) Test_CalculateExactHypervolumeIndicators_Approximation(c *C) {
+
+	// In three dimensions, what is left of a cube after its overlaps is often not a box, and the approximation only
+	// finds part of it.
+	var points [][]float64 = [][]float64{
+		[]float64{3.0, 3.0, 3.0},
+		[]float64{4.0, 1.0, 1.0},
+		[]float64{1.0, 4.0, 1.0},
+		[]float64{1.0, 1.0, 4.0},
+	}
+	var exact []*specimenHypercube
+	var approximated []*specimenHypercube
+	for _, point := range points {
+		exact = append(exact, &specimenHypercube{dimensions: point})
+		approximated = append(approximated, &specimenHypercube{dimensions: point, indicatorBase: []float64{0.0, 0.0, 0.0}})
+	}
+	calculateExactHypervolumeIndicators(exact)
+	calculateHypervolumeIndicators(append([]*specimenHypercube{}, approximated...)) // The k-d tree reorders the slice.
+
+	// The big cube keeps all but the 7 unit cubes the small ones overlap.
+	c.Check(exact[0].indicator, Equals, 20.0)
+	c.Check(countUnitCubes(points[:1], points[1:]), Equals, 20.0)
+	c.Check(approximated[0].indicator < exact[0].indicator, Equals, true)
+
+	// The small ones each have their tip.
+	for i := 1; i < len(points); i++ {
+		c.Check(exact[i].indicator, Equals, 1.0)
+	}
+}
+
+func (s *HypervolumeWfgSuite) Test_SorterHypervolumeIndicator_Wfg(c *C) {
+	var sorter SorterHypervolumeIndicator = SorterHypervolumeIndicator{
+		ReferencePoint: []float64{0.0, 0.0, 0.0},
+		Maximize:       []bool{true, true, true},
+		Weights:        []float64{1.0, 1.0, 1.0},
+		IndicatorPower: 1.0,
+		Algorithm:      HYPERVOLUME_ALGORITHM_WFG,
+	}
+	sorter.validOrPanic()
+
+	var specimens []Specimen = []Specimen{
+		Specimen{Outcomes: []float64{1.0, 1.0, 4.0}, SpeciesMemberCount: 1}, // selection score: 1.0 + 4.0
+		Specimen{Outcomes: []float64{3.0, 3.0, 3.0}, SpeciesMemberCount: 1}, // selection score: 22.0 + 27.0
+		Specimen{Outcomes: []float64{4.0, 1.0, 1.0}, SpeciesMemberCount: 1}, // selection score: 1.0 + 4.0
+	}
+	var bestScore float64
+	var sorted []Specimen
+	bestScore, _, sorted = sorter.Sort(specimens)
+	c.Check(bestScore, Equals, 27.0)
+	c.Check(sorted[0].SelectionScore, Equals, 49.0)
+	c.Check(sorted[1].SelectionScore, Equals, 5.0)
+
+	// Unknown algorithms panic.
+	sorter.Algorithm = "monte_carlo"
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Unknown hypervolume Algorithm: 'monte_carlo'")
+}
+
+// randomIntegerPoints makes random points with whole number lengths from 1 to max.
+func randomIntegerPoints(count int, dimensions int, max int) (points [][]float64) {
+	for i := 0; i < count; i++ {
+		var point []float64
+		for d := 0; d < dimensions; d++ {
+			point = append(point, float64(1+rand.Intn(max)))
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// countUnitCubes counts the unit cubes inside the boxes of points but not inside any box of excluded, for points with
+// whole number lengths.
+func countUnitCubes(points [][]float64, excluded [][]float64) (count float64) {
+	var dimensions int = len(points[0])
+	var max float64
+	for _, point := range points {
+		for _, length := range point {
+			if length > max {
+				max = length
+			}
+		}
+	}
+
+	// Check the center of each unit cube.
+	var cell []float64 = make([]float64, dimensions)
+	var visit func(d int)
+	visit = func(d int) {
+		if d == dimensions {
+			var isInside = func(boxes [][]float64) bool {
+				for _, box := range boxes {
+					if isPointInsideBox(cell, box) {
+						return true
+					}
+				}
+				return false
+			}
+			if isInside(points) && !isInside(excluded) {
+				count++
+			}
+			return
+		}
+		for i := 0; i < int(max); i++ {
+			cell[d] = float64(i) + 0.5
+			visit(d + 1)
+		}
+	}
+	visit(0)
+	return count
+}
@@ -0,0 +1,238 @@
+package genetic
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+)
+
+const (
+	// Which islands send migrants to which.
+	ISLAND_TOPOLOGY_RING            = "ring"            // Each island sends to the next, the last to the first (the default).
+	ISLAND_TOPOLOGY_FULLY_CONNECTED = "fully_connected" // Each island sends to every other island.
+	ISLAND_TOPOLOGY_RANDOM          = "random"          // Each island sends to another island picked at random each migration.
+)
+
+// ScorerFactory creates the scorer of an island, numbered from 1. The islands score at the same time, so each needs a
+// scorer of its own.
+type ScorerFactory func(island int) Scorer
+
+// geneticIsland is a single island of an island model experiment, a population evolving on its own between migrations.
+type geneticIsland struct {
+	number        int                  // The island's number, from 1.
+	population    generationPopulation // The island's population.
+	scorer        Scorer               // The island's own scorer.
+	noveltySearch *NoveltySearch       // If configured, the island's own novelty search. nil otherwise.
+	scoring       ConfigScoring        // How many trials each specimen is scored in, and how.
+
+	// The last generation.
+	sorted      []Specimen        // All the specimens, sorted, for picking migrants.
+	bestScore   float64           // The best score of the sort.
+	best        string            // The best of the sort.
+	constraints constraintSummary // How well the specimens kept to the scorer's constraints.
+	sorterBytes []byte            // The sorter's generation details, if it has any.
+}
+
+// newIslands creates the islands, each with a single specimen to grow its population from.
+func newIslands(config Config, newScorer ScorerFactory) (islands []*geneticIsland) {
+	config.Islands.validOrPanic()
+	config.Scoring.validOrPanic()
+
+	for i, islandConfig := range config.Islands.Islands {
+
+		// The island may manage its population differently.
+		var populationConfig ConfigPopulation = config.Population
+		if islandConfig.Population != nil {
+			populationConfig = *islandConfig.Population
+		}
+
+		var island *geneticIsland = &geneticIsland{
+			number:     i + 1,
+			population: newPopulation(populationConfig),
+			scorer:     newScorer(i + 1),
+			scoring:    config.Scoring,
+		}
+		if config.NoveltySearch.K > 0 {
+			island.noveltySearch = NewNoveltySearch(config.NoveltySearch)
+		}
+		island.population.AddNeuralNet(newNeatNeuralNet(config.NeuralNetInOut), 0.0, 0.0, nil) // The specimen has no scores.
+		islands = append(islands, island)
+	}
+	return islands
+}
+
+// evolve fills out the island's population and scores it. Islands evolve at the same time, so this only touches the
+// island itself.
+func (i *geneticIsland) evolve(generationNum uint64) {
+
+	// Tell the scorer that a new generation has started.
+	i.scorer.GenerationStart(generationNum)
+
+	// Fill out the population to the correct size.
+	i.population.FillOut()
+
+	// Score each specimen and re-add it into the population.
+	for _, specimen := range scoreSpecimens(i.scorer, i.population.DumpSpecimens(), i.scoring, i.noveltySearch) {
+		i.population.AddSpecimen(specimen)
+	}
+
+	// Modify the scores of the specimens by the size of their species.
+	i.population.WeightSpecies()
+}
+
+// selectFittest sorts the island's population and keeps the fittest. A sorter may keep details of its last sort, so
+// the islands are sorted one at a time.
+func (i *geneticIsland) selectFittest(sorter Sorter, selector Selector) {
+	var specimens []Specimen = i.population.DumpSpecimens()
+	i.constraints = summarizeConstraints(specimens)
+	i.bestScore, i.best, i.sorted = sorter.Sort(specimens)
+	i.sorterBytes = sorterGenerationDetails(sorter)
+	i.population.AddAllSpecimens(selector.Select(i.sorted))
+}
+
+// migrate copies the fittest specimens of each island to the islands it sends to. The migrants are picked from every
+// island before any arrive, so a migrant only moves one island each migration.
+func migrate(islands []*geneticIsland, config ConfigIslands) {
+	var arrivals [][]Specimen = make([][]Specimen, len(islands))
+	for from, island := range islands {
+		var count int = config.MigrationCount
+		if count > len(island.sorted) {
+			count = len(island.sorted)
+		}
+		for _, to := range migrationDestinations(config.Topology, from, len(islands)) {
+			for _, migrant := range island.sorted[:count] {
+				// The migrant is a copy, not tethered to the original.
+				migrant.NeuralNet = migrant.NeuralNet.makeClone()
+				arrivals[to] = append(arrivals[to], migrant)
+			}
+		}
+	}
+
+	for to, island := range islands {
+		for _, migrant := range arrivals[to] {
+			island.population.AddSpecimen(migrant)
+		}
+	}
+}
+
+// migrationDestinations are the islands (as indexes) that an island (as an index) sends migrants to.
+func migrationDestinations(topology string, from int, islandCount int) (destinations []int) {
+	switch topology {
+	case "", ISLAND_TOPOLOGY_RING:
+		destinations = append(destinations, (from+1)%islandCount)
+	case ISLAND_TOPOLOGY_FULLY_CONNECTED:
+		for to := 0; to < islandCount; to++ {
+			if to != from {
+				destinations = append(destinations, to)
+			}
+		}
+	case ISLAND_TOPOLOGY_RANDOM:
+		// Any island but this one.
+		var to int = rand.Intn(islandCount - 1)
+		if to >= from {
+			to++
+		}
+		destinations = append(destinations, to)
+	default:
+		log.Panicf("Unknown island Topology: '%s'", topology)
+	}
+	return destinations
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigIslands) validOrPanic() {
+	if len(c.Islands) < 2 {
+		log.Panicf("Islands needs at least two islands: %d", len(c.Islands))
+	}
+	if c.MigrationInterval < 1 {
+		log.Panicf("Islands MigrationInterval must be one or more: %d", c.MigrationInterval)
+	}
+	if c.MigrationCount < 0 {
+		log.Panicf("Islands MigrationCount cannot be negative: %d", c.MigrationCount)
+	}
+	switch c.Topology {
+	case "", ISLAND_TOPOLOGY_RING, ISLAND_TOPOLOGY_FULLY_CONNECTED, ISLAND_TOPOLOGY_RANDOM:
+	default:
+		log.Panicf("Unknown island Topology: '%s'", c.Topology)
+	}
+}
+
+// RunIslands runs an island model experiment until stopped manually or an end condition is met. Rather than a single
+// population, there is a population on each island (see ConfigIslands). The islands evolve at the same time, and each
+// has its own species and can manage its population differently, so they tend to find different solutions. Every few
+// generations the fittest specimens of each island migrate to other islands, spreading good genetic material around.
+//
+// The islands score at the same time, so each island gets its own scorer from newScorer. The sorter and selector are
+// shared, with the islands sorted and selected one at a time. Each island's generations are recorded separately under the
+// same experiment.
+func RunIslands(experimentName string, config Config, sorter Sorter, selector Selector, newScorer ScorerFactory) {
+
+	// Create the experiment. The first island's scorer is recorded as the experiment's scorer, once there are islands.
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, selector, nil)
+
+	// Ensure the interface defined in the config is valid.
+	experiment.config.NeuralNetInOut.validate()
+
+	// The selector must agree with the sorter on which way selection scores run.
+	validSelectionOrPanic(experiment.sorter, experiment.selector)
+
+	// The worst trial must be the worst for the sorter.
+	validAggregationOrPanic(experiment.sorter, experiment.config.Scoring)
+
+	// The islands. All the islands share the gene ids, so the same gene id is the same gene on any island.
+	var islands []*geneticIsland = newIslands(experiment.config, newScorer)
+	experiment.scorer = islands[0].scorer
+
+	// Run a generation of the experiment.
+	experiment.run(func(generationNum uint64) (result generationResult) {
+
+		// Give each island's scorer the champions so far, if it wants them.
+		for _, island := range islands {
+			experiment.shareHallOfFame(island.scorer)
+		}
+
+		// Evolve all the islands at the same time.
+		var waitGroup sync.WaitGroup
+		for _, island := range islands {
+			waitGroup.Add(1)
+			go func(island *geneticIsland) {
+				defer waitGroup.Done()
+				island.evolve(generationNum)
+			}(island)
+		}
+		waitGroup.Wait()
+
+		// Select the fittest on each island. The best score is the best of any island.
+		result.bestScore = noFeasibleBestScore()
+		for _, island := range islands {
+			island.selectFittest(experiment.sorter, experiment.selector)
+			if isBetterScore(experiment.sorter.IsMaximize(), island.bestScore, result.bestScore) {
+				result.bestScore = island.bestScore
+			}
+			result.sorted = append(result.sorted, island.sorted...)
+		}
+
+		// Migrate every so often.
+		if generationNum%uint64(experiment.config.Islands.MigrationInterval) == 0 {
+			migrate(islands, experiment.config.Islands)
+		}
+
+		// Every island's species make up the whole population. Champions can be put back on any island.
+		for _, island := range islands {
+			result.population.species = append(result.population.species, island.population.species...)
+			result.reinjectInto = append(result.reinjectInto, &island.population)
+		}
+		return result
+	}, func(generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64, result generationResult) {
+		experiment.recordIslandGenerations(islands, generationNum, bestExperimentScore, stagnantGenerationCount)
+	})
+}
+
+// recordIslandGenerations records a single generation of each island.
+func (e *geneticExperiment) recordIslandGenerations(islands []*geneticIsland, generationNum uint64, bestExperimentScore float64, stagnantGenerationCount uint64) {
+	for _, island := range islands {
+		var best string = fmt.Sprintf("island: %d, %s", island.number, island.best)
+		e.recordIslandGeneration(island.number, island.scorer.GenerationDetails(), island.sorterBytes, generationNum, bestExperimentScore, stagnantGenerationCount, best, island.constraints, island.population)
+	}
+}
@@ -0,0 +1,188 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+	"sync"
+)
+
+// Create a suite.
+type IslandsSuite struct{}
+
+var _ = Suite(&IslandsSuite{})
+
+// TearDownTest puts the gene ids back, the neural nets created here use up gene ids.
+func (s *IslandsSuite) TearDownTest(c *C) { setMaxGeneId(0) }
+
+// islandsTestConfig has three islands, the last with a bigger population, and everyone in a single species.
+func islandsTestConfig() Config {
+	var population ConfigPopulation = populationTestConfig(4)
+	population.Mutate.AlterConnectionWeight = 1
+	var bigPopulation ConfigPopulation = population
+	bigPopulation.PopulationSize = 6
+	return Config{
+		NeuralNetInOut: populationTestInOut(),
+		Population:     population,
+		Islands: ConfigIslands{
+			Islands:           []ConfigIsland{ConfigIsland{}, ConfigIsland{}, ConfigIsland{Population: &bigPopulation}},
+			MigrationInterval: 5,
+			MigrationCount:    1,
+		},
+	}
+}
+
+// islandScores are the scores of an island's specimens, in order.
+func islandScores(island *geneticIsland) (scores []float64) {
+	scores = []float64{}
+	for _, specimen := range island.population.allSpecimens() {
+		scores = append(scores, specimen.Score)
+	}
+	return scores
+}
+
+// Add the tests.
+
+func (s *IslandsSuite) Test_NewIslands(c *C) {
+	var scorerIslands []int
+	var islands []*geneticIsland = newIslands(islandsTestConfig(), func(island int) Scorer {
+		scorerIslands = append(scorerIslands, island)
+		return &behaviorTestScorer{}
+	})
+
+	// Each island has its own scorer and a single specimen to start, and may manage its population differently.
+	c.Check(scorerIslands, DeepEquals, []int{1, 2, 3})
+	c.Check(len(islands), Equals, 3)
+	for i, island := range islands {
+		c.Check(island.number, Equals, i+1)
+		c.Check(len(island.population.allSpecimens()), Equals, 1)
+		c.Check(island.noveltySearch, IsNil)
+	}
+	c.Check(islands[0].population.config.PopulationSize, Equals, 4)
+	c.Check(islands[2].population.config.PopulationSize, Equals, 6)
+}
+
+func (s *IslandsSuite) Test_EvolveSelect(c *C) {
+	rand.Seed(1)
+
+	var islands []*geneticIsland = newIslands(islandsTestConfig(), func(island int) Scorer { return &behaviorTestScorer{} })
+
+	// Islands evolve at the same time. The behavior test scorer scores by index.
+	var waitGroup sync.WaitGroup
+	for _, island := range islands {
+		waitGroup.Add(1)
+		go func(island *geneticIsland) {
+			defer waitGroup.Done()
+			island.evolve(1)
+		}(island)
+	}
+	waitGroup.Wait()
+	c.Check(islandScores(islands[0]), DeepEquals, []float64{0.0, 1.0, 2.0, 3.0})
+	c.Check(islandScores(islands[2]), DeepEquals, []float64{0.0, 1.0, 2.0, 3.0, 4.0, 5.0})
+
+	// Each island sorts and selects on its own.
+	for _, island := range islands {
+		island.selectFittest(NewSorterSimpleMaximize(), &SelectorElitism{KeepCount: 2})
+	}
+	c.Check(islands[0].bestScore, Equals, 3.0)
+	c.Check(islands[2].bestScore, Equals, 5.0)
+	c.Check(islands[2].best, Equals, "score: 5.000000, bonus: 0.000000, speciesmembercount: 6")
+	c.Check(islands[2].constraints, Equals, constraintSummary{specimens: 6, feasibleSpecimens: 6})
+	c.Check(len(islands[2].sorted), Equals, 6)
+	c.Check(islandScores(islands[2]), DeepEquals, []float64{5.0, 4.0})
+}
+
+func (s *IslandsSuite) Test_Migrate(c *C) {
+	var islands []*geneticIsland
+	for i := 0; i < 3; i++ {
+		var island *geneticIsland = &geneticIsland{number: i + 1, population: newPopulation(islandsTestConfig().Population)}
+		for n := 2; n >= 0; n-- {
+			var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1}}}}
+			island.sorted = append(island.sorted, Specimen{NeuralNet: neuralNet, Score: float64(10*(i+1) + n), SpeciesMemberCount: 1})
+		}
+		islands = append(islands, island)
+	}
+
+	// A ring sends the fittest to the next island, the last to the first.
+	migrate(islands, ConfigIslands{MigrationCount: 2, Topology: ISLAND_TOPOLOGY_RING})
+	c.Check(islandScores(islands[0]), DeepEquals, []float64{32.0, 31.0})
+	c.Check(islandScores(islands[1]), DeepEquals, []float64{12.0, 11.0})
+	c.Check(islandScores(islands[2]), DeepEquals, []float64{22.0, 21.0})
+
+	// Migrants are copies.
+	islands[0].population.allSpecimens()[0].NeuralNet.Genome.Genes[0].Weight = 1.0
+	c.Check(islands[2].sorted[0].NeuralNet.Genome.Genes[0].Weight, Equals, 0.0)
+
+	// Fully connected sends to every other island, and no more migrate than there are.
+	for _, island := range islands {
+		island.population = newPopulation(islandsTestConfig().Population)
+	}
+	migrate(islands, ConfigIslands{MigrationCount: 5, Topology: ISLAND_TOPOLOGY_FULLY_CONNECTED})
+	c.Check(islandScores(islands[0]), DeepEquals, []float64{22.0, 21.0, 20.0, 32.0, 31.0, 30.0})
+	c.Check(islandScores(islands[1]), DeepEquals, []float64{12.0, 11.0, 10.0, 32.0, 31.0, 30.0})
+}
+
+func (s *IslandsSuite) Test_MigrationDestinations(c *C) {
+	c.Check(migrationDestinations(ISLAND_TOPOLOGY_RING, 0, 3), DeepEquals, []int{1})
+	c.Check(migrationDestinations(ISLAND_TOPOLOGY_RING, 2, 3), DeepEquals, []int{0})
+	c.Check(migrationDestinations("", 1, 3), DeepEquals, []int{2}) // A ring.
+	c.Check(migrationDestinations(ISLAND_TOPOLOGY_FULLY_CONNECTED, 1, 4), DeepEquals, []int{0, 2, 3})
+
+	// Random is never the island itself.
+	for i := 0; i < 20; i++ {
+		var destinations []int = migrationDestinations(ISLAND_TOPOLOGY_RANDOM, 1, 3)
+		c.Check(len(destinations), Equals, 1)
+		c.Check(destinations[0], Not(Equals), 1)
+	}
+	c.Check(migrationDestinations(ISLAND_TOPOLOGY_RANDOM, 0, 2), DeepEquals, []int{1})
+
+	c.Check(func() { migrationDestinations("star", 0, 3) }, Panics, "Unknown island Topology: 'star'")
+}
+
+func (s *IslandsSuite) Test_GeneIds_Concurrent(c *C) {
+	setMaxGeneId(0)
+
+	// Islands evolving at the same time never get the same gene id.
+	var waitGroup sync.WaitGroup
+	var geneIds [][]uint64 = make([][]uint64, 4)
+	for i := range geneIds {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			for n := 0; n < 1000; n++ {
+				geneIds[i] = append(geneIds[i], newGeneId())
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	var isSeen map[uint64]bool = map[uint64]bool{}
+	for _, islandGeneIds := range geneIds {
+		for _, geneId := range islandGeneIds {
+			isSeen[geneId] = true
+		}
+	}
+	c.Check(len(isSeen), Equals, 4000)
+	c.Check(newGeneId(), Equals, uint64(4001))
+}
+
+func (s *IslandsSuite) Test_ConfigIslands_ValidOrPanic(c *C) {
+	var config ConfigIslands
+
+	config = islandsTestConfig().Islands
+	config.validOrPanic() // Valid.
+
+	config.Islands = config.Islands[:1]
+	c.Check(func() { config.validOrPanic() }, Panics, "Islands needs at least two islands: 1")
+
+	config = islandsTestConfig().Islands
+	config.MigrationInterval = 0
+	c.Check(func() { config.validOrPanic() }, Panics, "Islands MigrationInterval must be one or more: 0")
+
+	config = islandsTestConfig().Islands
+	config.MigrationCount = -1
+	c.Check(func() { config.validOrPanic() }, Panics, "Islands MigrationCount cannot be negative: -1")
+
+	config = islandsTestConfig().Islands
+	config.Topology = "star"
+	c.Check(func() { config.validOrPanic() }, Panics, "Unknown island Topology: 'star'")
+}
@@ -0,0 +1,415 @@
+package genetic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// How the behavior descriptors of MAP-Elites are divided into cells.
+	MAP_ELITES_GRID = "grid" // Equal bins in each descriptor.
+	MAP_ELITES_CVT  = "cvt"  // A centroidal Voronoi tessellation, for many descriptors where a grid would have too many cells.
+
+	_DEFAULT_CVT_SAMPLES_PER_CELL = 100
+	_DEFAULT_CVT_ITERATIONS       = 20
+)
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigMapElites) validOrPanic() {
+	if len(c.Descriptors) == 0 {
+		log.Panic("MAP-Elites needs at least one descriptor")
+	}
+	for _, descriptor := range c.Descriptors {
+		if descriptor.Max <= descriptor.Min {
+			log.Panicf("MAP-Elites descriptor '%s' Max (%f) must be more than Min (%f)", descriptor.Name, descriptor.Max, descriptor.Min)
+		}
+	}
+	switch c.Tessellation {
+	case "", MAP_ELITES_GRID:
+		for _, descriptor := range c.Descriptors {
+			if descriptor.Bins < 1 {
+				log.Panicf("MAP-Elites descriptor '%s' must have one or more Bins: %d", descriptor.Name, descriptor.Bins)
+			}
+		}
+	case MAP_ELITES_CVT:
+		if c.CvtCells < 1 {
+			log.Panicf("MAP-Elites CvtCells must be one or more: %d", c.CvtCells)
+		}
+		if c.CvtSamples != 0 && c.CvtSamples < c.CvtCells {
+			log.Panicf("MAP-Elites CvtSamples (%d) must be at least CvtCells (%d)", c.CvtSamples, c.CvtCells)
+		}
+	default:
+		log.Panicf("Unknown MAP-Elites tessellation: '%s'", c.Tessellation)
+	}
+	if c.InitialSize < 1 || c.BatchSize < 1 {
+		log.Panicf("MAP-Elites InitialSize (%d) and BatchSize (%d) must be one or more", c.InitialSize, c.BatchSize)
+	}
+}
+
+// mapElitesTessellation divides the space of behavior descriptors into cells.
+type mapElitesTessellation interface {
+	cellCount() int
+	cellOf(descriptor []float64) (cell int)
+}
+
+// normalizeDescriptor scales each descriptor value to 0.0 to 1.0 of its range, clipping values outside of the range.
+func normalizeDescriptor(descriptors []ConfigDescriptor, descriptor []float64) (normalized []float64) {
+	if len(descriptor) != len(descriptors) {
+		log.Panicf("MAP-Elites expected a behavior of %d values: %v", len(descriptors), descriptor)
+	}
+	for i, value := range descriptor {
+		var scaled float64 = (value - descriptors[i].Min) / (descriptors[i].Max - descriptors[i].Min)
+		normalized = append(normalized, math.Min(math.Max(scaled, 0.0), 1.0))
+	}
+	return normalized
+}
+
+// gridTessellation divides each descriptor into equal bins. Cells are numbered with the first descriptor changing slowest.
+type gridTessellation struct {
+	descriptors []ConfigDescriptor
+}
+
+// cellCount is how many cells the grid has.
+func (g *gridTessellation) cellCount() int {
+	var count int = 1
+	for _, descriptor := range g.descriptors {
+		count *= descriptor.Bins
+	}
+	return count
+}
+
+// cellOf is the cell the descriptor falls in.
+func (g *gridTessellation) cellOf(descriptor []float64) (cell int) {
+	for i, value := range normalizeDescriptor(g.descriptors, descriptor) {
+		var bins int = g.descriptors[i].Bins
+		var bin int = int(value * float64(bins))
+		if bin == bins {
+			bin = bins - 1 // The max value is in the last bin.
+		}
+		cell = cell*bins + bin
+	}
+	return cell
+}
+
+// cvtTessellation divides the descriptors into cells around centroids spread evenly through the (normalized) space by
+// k-means over random samples.
+//
+// Reference: Vassiliades, V., Chatzilygeroudis, K. and Mouret, J.-B. (2017) Using Centroidal Voronoi Tessellations to
+// Scale Up the Multidimensional Archive of Phenotypic Elites Algorithm.
+type cvtTessellation struct {
+	descriptors []ConfigDescriptor
+	centroids   [][]float64 // Normalized.
+}
+
+// newCvtTessellation places the centroids. Random, so assume the seed has been set.
+func newCvtTessellation(descriptors []ConfigDescriptor, cells int, samples int, iterations int) *cvtTessellation {
+
+	// Random points throughout the space.
+	var points [][]float64
+	for i := 0; i < samples; i++ {
+		var point []float64
+		for range descriptors {
+			point = append(point, rand.Float64())
+		}
+		points = append(points, point)
+	}
+
+	// Start the centroids on the first points, then move each to the middle of the points nearest it.
+	var cvt *cvtTessellation = &cvtTessellation{descriptors: descriptors}
+	for _, point := range points[:cells] {
+		cvt.centroids = append(cvt.centroids, append([]float64{}, point...))
+	}
+	for iteration := 0; iteration < iterations; iteration++ {
+		var sums [][]float64 = make([][]float64, cells)
+		var counts []int = make([]int, cells)
+		for _, point := range points {
+			var cell int = cvt.nearest(point)
+			if sums[cell] == nil {
+				sums[cell] = make([]float64, len(point))
+			}
+			for i, value := range point {
+				sums[cell][i] += value
+			}
+			counts[cell]++
+		}
+		for cell := range cvt.centroids {
+			if counts[cell] == 0 {
+				continue // Nothing near this centroid, leave it where it is.
+			}
+			for i := range cvt.centroids[cell] {
+				cvt.centroids[cell][i] = sums[cell][i] / float64(counts[cell])
+			}
+		}
+	}
+	return cvt
+}
+
+// cellCount is how many cells (centroids) there are.
+func (t *cvtTessellation) cellCount() int { return len(t.centroids) }
+
+// cellOf is the cell with the centroid nearest the descriptor.
+func (t *cvtTessellation) cellOf(descriptor []float64) (cell int) {
+	return t.nearest(normalizeDescriptor(t.descriptors, descriptor))
+}
+
+// nearest is the index of the centroid nearest a normalized point.
+func (t *cvtTessellation) nearest(point []float64) (cell int) {
+	var nearestDistance float64 = math.Inf(1)
+	for i, centroid := range t.centroids {
+		var distance float64 = euclideanDistance(point, centroid)
+		if distance < nearestDistance {
+			nearestDistance = distance
+			cell = i
+		}
+	}
+	return cell
+}
+
+// MapElite is the best neural net found for one cell of a MAP-Elites archive.
+type MapElite struct {
+	Cell       int           // The cell of the archive.
+	Descriptor []float64     // The behavior that put the neural net in this cell.
+	Score      float64       // The score, what makes it the best of the cell.
+	Bonus      float64       // The bonus from the scorer, not used by MAP-Elites.
+	Outcomes   []float64     // The multi-outcomes from the scorer, not used by MAP-Elites.
+	NeuralNet  NeatNeuralNet // The neural net.
+}
+
+// MapElitesArchive keeps the best neural net for each cell of the behavior descriptors.
+type MapElitesArchive struct {
+	config       ConfigMapElites
+	tessellation mapElitesTessellation
+	elites       map[int]*MapElite // Keyed by cell.
+}
+
+// NewMapElitesArchive creates an empty archive. A "cvt" tessellation is placed randomly, so assume the seed has been set.
+func NewMapElitesArchive(config ConfigMapElites) *MapElitesArchive {
+	config.validOrPanic()
+
+	var tessellation mapElitesTessellation
+	switch config.Tessellation {
+	case MAP_ELITES_CVT:
+		var samples int = config.CvtSamples
+		if samples == 0 {
+			samples = _DEFAULT_CVT_SAMPLES_PER_CELL * config.CvtCells
+		}
+		var iterations int = config.CvtIterations
+		if iterations == 0 {
+			iterations = _DEFAULT_CVT_ITERATIONS
+		}
+		tessellation = newCvtTessellation(config.Descriptors, config.CvtCells, samples, iterations)
+	default:
+		tessellation = &gridTessellation{descriptors: config.Descriptors}
+	}
+
+	return &MapElitesArchive{
+		config:       config,
+		tessellation: tessellation,
+		elites:       map[int]*MapElite{},
+	}
+}
+
+// Add puts the neural net in the archive if it is feasible and its cell is empty or it is better than the elite already there.
+// The scorer must have reported a behavior as the descriptor.
+func (a *MapElitesArchive) Add(neuralNet NeatNeuralNet, result ScoreResult) (isAdded bool) {
+	if result.Behavior == nil {
+		log.Panic("MAP-Elites needs a Behavior from the scorer for every neural net")
+	}
+	var cell int = a.tessellation.cellOf(result.Behavior)
+
+	// A candidate that broke the scorer's constraints is never an elite, so it can never be the best.
+	if result.ConstraintViolation > 0.0 {
+		return false
+	}
+
+	// Is there already a better elite?
+	var elite *MapElite
+	var ok bool
+	if elite, ok = a.elites[cell]; ok {
+		if a.config.Maximize && result.Score <= elite.Score {
+			return false
+		}
+		if !a.config.Maximize && result.Score >= elite.Score {
+			return false
+		}
+	}
+
+	a.elites[cell] = &MapElite{
+		Cell:       cell,
+		Descriptor: result.Behavior,
+		Score:      result.Score,
+		Bonus:      result.Bonus,
+		Outcomes:   result.Outcomes,
+		NeuralNet:  neuralNet,
+	}
+	return true
+}
+
+// Elites are all the elites of the archive, in cell order.
+func (a *MapElitesArchive) Elites() (elites []MapElite) {
+	for _, elite := range a.elites {
+		elites = append(elites, *elite)
+	}
+	sort.Sort(byMapEliteCell(elites))
+	return elites
+}
+
+// byMapEliteCell implements sort.Interface to sort ascending by cell.
+// Example: sort.Sort(byMapEliteCell(elites))
+type byMapEliteCell []MapElite
+
+func (a byMapEliteCell) Len() int           { return len(a) }
+func (a byMapEliteCell) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byMapEliteCell) Less(i, j int) bool { return a[i].Cell < a[j].Cell }
+
+// Coverage is the fraction of cells that have an elite, 0.0 to 1.0.
+func (a *MapElitesArchive) Coverage() float64 {
+	return float64(len(a.elites)) / float64(a.tessellation.cellCount())
+}
+
+// QdScore is the quality-diversity score, the sum of every elite's score. When minimizing, scores are negated so that
+// a higher QD-score is always better.
+func (a *MapElitesArchive) QdScore() (qdScore float64) {
+	for _, elite := range a.elites {
+		if a.config.Maximize {
+			qdScore += elite.Score
+		} else {
+			qdScore -= elite.Score
+		}
+	}
+	return qdScore
+}
+
+// Best is the elite with the best score. false if the archive is empty.
+func (a *MapElitesArchive) Best() (best MapElite, ok bool) {
+	for _, elite := range a.Elites() {
+		if !ok || (a.config.Maximize && elite.Score > best.Score) || (!a.config.Maximize && elite.Score < best.Score) {
+			best = elite
+			ok = true
+		}
+	}
+	return best, ok
+}
+
+// Summary describes the archive in a single line.
+func (a *MapElitesArchive) Summary() string {
+	var best MapElite
+	var ok bool
+	if best, ok = a.Best(); !ok {
+		return fmt.Sprintf("coverage: %f, qd-score: %f, elites: 0", a.Coverage(), a.QdScore())
+	}
+	return fmt.Sprintf("coverage: %f, qd-score: %f, elites: %d, best score: %f, best cell: %d", a.Coverage(), a.QdScore(), len(a.elites), best.Score, best.Cell)
+}
+
+// WriteJson exports the elites, in cell order, as json.
+func (a *MapElitesArchive) WriteJson(writer io.Writer) error {
+	var err error
+	var bytes []byte
+	if bytes, err = json.Marshal(a.Elites()); err != nil {
+		return err
+	}
+	_, err = writer.Write(bytes)
+	return err
+}
+
+// breed creates new candidates by mutating random elites, or mating them with other elites.
+func (a *MapElitesArchive) breed(count int, config ConfigMutate) (candidates []NeatNeuralNet) {
+	var specimens []Specimen
+	for _, elite := range a.Elites() {
+		specimens = append(specimens, newSpecimen(elite.NeuralNet, elite.Score, elite.Bonus, elite.Outcomes))
+	}
+	for i := 0; i < count; i++ {
+		var specimenIndex int = rand.Intn(len(specimens))
+		var child Specimen = specimens[specimenIndex].mateMutate(specimens, specimenIndex, config)
+		candidates = append(candidates, child.NeuralNet)
+	}
+	return candidates
+}
+
+// RunMapElites runs a MAP-Elites quality-diversity experiment until stopped manually or an end condition is met. Rather
+// than a single champion, it finds the best neural net for each kind of behavior (see ConfigMapElites). The scorer must
+// report a behavior (see ResultScorer), which is where the neural net falls in the archive.
+//
+// Each generation is a batch of candidates bred from random elites with the normal mating and mutation, and a generation
+// is stagnant if none of its candidates made it into the archive. The archive is returned for exporting, and is recorded
+// as the results of the experiment.
+//
+// Reference: Mouret, J.-B. and Clune, J. (2015) Illuminating search spaces by mapping elites.
+func RunMapElites(experimentName string, config Config, scorer Scorer) *MapElitesArchive {
+
+	// Create the experiment. There is no selector, the archive does the selecting. The sorter only says which way the
+	// scores run.
+	var sorter Sorter = NewSorterSimpleMinimize()
+	if config.MapElites.Maximize {
+		sorter = NewSorterSimpleMaximize()
+	}
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, nil, scorer)
+
+	// Ensure the interface defined in the config is valid.
+	experiment.config.NeuralNetInOut.validate()
+
+	// Is each candidate scored in several trials? An elite is never scored again, so it has no lifetime to average.
+	experiment.config.Scoring.validOrPanic()
+	validAggregationOrPanic(experiment.sorter, experiment.config.Scoring)
+	if experiment.config.Scoring.LifetimeAverage {
+		log.Panic("MAP-Elites never scores an elite again, Scoring LifetimeAverage cannot be used")
+	}
+
+	// The archive already keeps the best of every niche.
+	if experiment.config.HallOfFame != (ConfigHallOfFame{}) {
+		log.Panic("MAP-Elites keeps its elites in its archive, HallOfFame cannot be used")
+	}
+
+	// The empty archive.
+	var archive *MapElitesArchive = NewMapElitesArchive(experiment.config.MapElites)
+
+	// A single neural net seeds the archive. It is mutated into the first batch of candidates.
+	var seed Specimen = newSpecimen(newNeatNeuralNet(experiment.config.NeuralNetInOut), 0.0, 0.0, nil)
+
+	// Run a generation of the experiment.
+	experiment.run(func(generationNum uint64) (result generationResult) {
+
+		// Tell the scorer that a new generation has started.
+		experiment.scorer.GenerationStart(generationNum)
+
+		// Breed the candidates, from the seed until there are elites.
+		var candidates []NeatNeuralNet
+		if len(archive.elites) == 0 {
+			for i := 0; i < experiment.config.MapElites.InitialSize; i++ {
+				candidates = append(candidates, seed.mateMutate([]Specimen{seed}, 0, experiment.config.Population.Mutate).NeuralNet)
+			}
+		} else {
+			candidates = archive.breed(experiment.config.MapElites.BatchSize, experiment.config.Population.Mutate)
+		}
+
+		// Score the candidates and keep the elites. Any new elite is an improvement.
+		var results []ScoreResult = scoreNeuralNetTrials(experiment.scorer, candidates, experiment.config.Scoring)
+		for i, candidate := range candidates {
+			result.constraints.add(results[i].ConstraintViolation)
+			if archive.Add(candidate, results[i]) {
+				result.isImproved = true
+			}
+		}
+		result.isImprovementJudged = true
+
+		// The best is the best elite so far.
+		result.bestScore = noFeasibleBestScore()
+		var best MapElite
+		var ok bool
+		if best, ok = archive.Best(); ok {
+			result.bestScore = best.Score
+		}
+		result.best = archive.Summary()
+
+		// The archive is the results of the experiment.
+		result.endResults = archive.Elites()
+		return result
+	}, experiment.recordPopulationGeneration)
+
+	return archive
+}
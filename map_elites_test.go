@@ -0,0 +1,192 @@
+package genetic
+
+import (
+	"bytes"
+	"encoding/json"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type MapElitesSuite struct{}
+
+var _ = Suite(&MapElitesSuite{})
+
+// mapElitesTestConfig is a 3 x 2 grid.
+func mapElitesTestConfig() ConfigMapElites {
+	return ConfigMapElites{
+		Descriptors: []ConfigDescriptor{
+			ConfigDescriptor{Name: "speed", Min: 0.0, Max: 3.0, Bins: 3},
+			ConfigDescriptor{Name: "height", Min: -1.0, Max: 1.0, Bins: 2},
+		},
+		InitialSize: 10,
+		BatchSize:   10,
+		Maximize:    true,
+	}
+}
+
+// Add the tests.
+
+func (s *MapElitesSuite) Test_GridTessellation(c *C) {
+	var grid gridTessellation = gridTessellation{descriptors: mapElitesTestConfig().Descriptors}
+	c.Check(grid.cellCount(), Equals, 6)
+	c.Check(grid.cellOf([]float64{0.0, -1.0}), Equals, 0)
+	c.Check(grid.cellOf([]float64{0.5, 0.5}), Equals, 1)
+	c.Check(grid.cellOf([]float64{1.5, -0.5}), Equals, 2)
+	c.Check(grid.cellOf([]float64{3.0, 1.0}), Equals, 5)    // The max is in the last bin.
+	c.Check(grid.cellOf([]float64{-10.0, 10.0}), Equals, 1) // Out of range values are clipped.
+	c.Check(func() { grid.cellOf([]float64{1.0}) }, Panics, "MAP-Elites expected a behavior of 2 values: [1]")
+}
+
+func (s *MapElitesSuite) Test_CvtTessellation(c *C) {
+	rand.Seed(1)
+
+	var descriptors []ConfigDescriptor = []ConfigDescriptor{
+		ConfigDescriptor{Min: 0.0, Max: 10.0},
+		ConfigDescriptor{Min: 0.0, Max: 1.0},
+		ConfigDescriptor{Min: -5.0, Max: 5.0},
+	}
+	var cvt *cvtTessellation = newCvtTessellation(descriptors, 8, 800, 20)
+	c.Check(cvt.cellCount(), Equals, 8)
+
+	// The centroids are spread through the space, each the nearest to itself.
+	var cells map[int]bool = map[int]bool{}
+	for i, centroid := range cvt.centroids {
+		var descriptor []float64
+		for d, value := range centroid {
+			descriptor = append(descriptor, descriptors[d].Min+value*(descriptors[d].Max-descriptors[d].Min))
+		}
+		c.Check(cvt.cellOf(descriptor), Equals, i)
+		cells[i] = true
+	}
+	c.Check(len(cells), Equals, 8)
+
+	// Opposite corners are different cells.
+	c.Check(cvt.cellOf([]float64{0.0, 0.0, -5.0}), Not(Equals), cvt.cellOf([]float64{10.0, 1.0, 5.0}))
+}
+
+func (s *MapElitesSuite) Test_MapElitesArchive(c *C) {
+	var archive *MapElitesArchive = NewMapElitesArchive(mapElitesTestConfig())
+	var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1}}}}
+
+	// Empty.
+	c.Check(archive.Coverage(), Equals, 0.0)
+	c.Check(archive.QdScore(), Equals, 0.0)
+	c.Check(archive.Summary(), Equals, "coverage: 0.000000, qd-score: 0.000000, elites: 0")
+	var ok bool
+	_, ok = archive.Best()
+	c.Check(ok, Equals, false)
+
+	// Each cell keeps its best.
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 2.0, Behavior: []float64{2.5, 0.5}}), Equals, true)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 1.0, Behavior: []float64{2.9, 0.9}}), Equals, false)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 2.0, Behavior: []float64{2.9, 0.9}}), Equals, false) // Ties keep the elite.
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 4.0, Behavior: []float64{2.9, 0.9}, Bonus: 1.0}), Equals, true)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 3.0, Behavior: []float64{0.0, 0.0}}), Equals, true)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 9.0, Behavior: []float64{0.0, 0.0}, ConstraintViolation: 1.0}), Equals, false) // Infeasible.
+	c.Check(func() { archive.Add(neuralNet, ScoreResult{Score: 3.0}) }, Panics, "MAP-Elites needs a Behavior from the scorer for every neural net")
+
+	c.Check(archive.Elites(), DeepEquals, []MapElite{
+		MapElite{Cell: 1, Descriptor: []float64{0.0, 0.0}, Score: 3.0, NeuralNet: neuralNet},
+		MapElite{Cell: 5, Descriptor: []float64{2.9, 0.9}, Score: 4.0, Bonus: 1.0, NeuralNet: neuralNet},
+	})
+	c.Check(archive.Coverage(), Equals, 2.0/6.0)
+	c.Check(archive.QdScore(), Equals, 7.0)
+	var best MapElite
+	best, ok = archive.Best()
+	c.Check(ok, Equals, true)
+	c.Check(best.Cell, Equals, 5)
+	c.Check(archive.Summary(), Equals, "coverage: 0.333333, qd-score: 7.000000, elites: 2, best score: 4.000000, best cell: 5")
+
+	// Exported as json.
+	var buffer bytes.Buffer
+	c.Assert(archive.WriteJson(&buffer), IsNil)
+	var exported []MapElite
+	c.Assert(json.Unmarshal(buffer.Bytes(), &exported), IsNil)
+	c.Check(exported, DeepEquals, archive.Elites())
+
+	// When minimizing, lower scores are better and the QD-score is still higher for better.
+	var config ConfigMapElites = mapElitesTestConfig()
+	config.Maximize = false
+	archive = NewMapElitesArchive(config)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 2.0, Behavior: []float64{2.5, 0.5}}), Equals, true)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 1.0, Behavior: []float64{2.9, 0.9}}), Equals, true)
+	c.Check(archive.Add(neuralNet, ScoreResult{Score: 3.0, Behavior: []float64{0.0, 0.0}}), Equals, true)
+	c.Check(archive.QdScore(), Equals, -4.0)
+	best, _ = archive.Best()
+	c.Check(best.Score, Equals, 1.0)
+}
+
+func (s *MapElitesSuite) Test_MapElitesArchive_Breed(c *C) {
+	var inOut NeuralNetInOut = populationTestInOut()
+
+	var archive *MapElitesArchive = NewMapElitesArchive(mapElitesTestConfig())
+	archive.Add(newNeatNeuralNet(inOut), ScoreResult{Score: 1.0, Behavior: []float64{0.0, 0.0}})
+	archive.Add(newNeatNeuralNet(inOut), ScoreResult{Score: 2.0, Behavior: []float64{3.0, 1.0}})
+
+	var candidates []NeatNeuralNet = archive.breed(20, populationTestConfig(0).Mutate)
+	c.Check(len(candidates), Equals, 20)
+	for _, candidate := range candidates {
+		c.Check(candidate.InOut, DeepEquals, inOut)
+		candidate.Compute(map[string]float64{"in1": 1.0, "in2": 0.5}) // Well formed.
+	}
+
+	// The elites are untouched.
+	c.Check(len(archive.Elites()), Equals, 2)
+}
+
+func (s *MapElitesSuite) Test_TrackJudgedImprovement(c *C) {
+	var bestExperimentScore float64
+	var stagnantGenerationCount uint64
+
+	// Any new elite is an improvement, even if the best elite is no better.
+	bestExperimentScore, stagnantGenerationCount = trackJudgedImprovement(true, 2.0, 5)
+	c.Check(bestExperimentScore, Equals, 2.0)
+	c.Check(stagnantGenerationCount, Equals, uint64(0))
+
+	// No new elite is another stagnant generation.
+	bestExperimentScore, stagnantGenerationCount = trackJudgedImprovement(false, 2.0, 5)
+	c.Check(bestExperimentScore, Equals, 2.0)
+	c.Check(stagnantGenerationCount, Equals, uint64(6))
+}
+
+func (s *MapElitesSuite) Test_ConfigMapElites_ValidOrPanic(c *C) {
+	var config ConfigMapElites
+
+	config = mapElitesTestConfig()
+	config.Descriptors = nil
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites needs at least one descriptor")
+
+	config = mapElitesTestConfig()
+	config.Descriptors[0].Max = 0.0
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites descriptor 'speed' Max (0.000000) must be more than Min (0.000000)")
+
+	config = mapElitesTestConfig()
+	config.Descriptors[1].Bins = 0
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites descriptor 'height' must have one or more Bins: 0")
+
+	config = mapElitesTestConfig()
+	config.Tessellation = MAP_ELITES_CVT
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites CvtCells must be one or more: 0")
+
+	config.CvtCells = 10
+	config.CvtSamples = 5
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites CvtSamples (5) must be at least CvtCells (10)")
+
+	config = mapElitesTestConfig()
+	config.Tessellation = "hexagon"
+	c.Check(func() { config.validOrPanic() }, Panics, "Unknown MAP-Elites tessellation: 'hexagon'")
+
+	config = mapElitesTestConfig()
+	config.BatchSize = 0
+	c.Check(func() { config.validOrPanic() }, Panics, "MAP-Elites InitialSize (10) and BatchSize (0) must be one or more")
+}
+
+func (s *MapElitesSuite) Test_TypeNameOf(c *C) {
+	var sorter Sorter
+	c.Check(typeNameOf(sorter), Equals, "")
+	var selector *SelectorElitism
+	c.Check(typeNameOf(selector), Equals, "")
+	sorter = NewSorterSimpleMaximize()
+	c.Check(typeNameOf(sorter), Equals, "genetic.sorterSimple")
+}
@@ -0,0 +1,241 @@
+package genetic
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+const (
+	// Which genome a gene found in only one genome came from.
+	DIFF_GENOME_FROM = "from"
+	DIFF_GENOME_TO   = "to"
+)
+
+// GenomeDiff describes every difference between two neural net genomes, aligned by gene id the same way the speciation
+// distance aligns them. Usually "from" is a parent and "to" is its child.
+type GenomeDiff struct {
+	Matching []GeneMatch      // Genes in both genomes, in gene id order.
+	Disjoint []GeneDifference // Genes in only one genome, within the gene id range of the other genome, in gene id order.
+	Excess   []GeneDifference // Genes in only one genome, newer than any gene in the other genome, in gene id order.
+}
+
+// GeneMatch is a gene found in both genomes, and how it changed.
+type GeneMatch struct {
+	GeneId            uint64
+	Type              string  // The type of gene this is.
+	From              string  // Describing the source of a connection.
+	To                string  // Describing the sink of a connection.
+	FromWeight        float64 // The weight in the "from" genome.
+	ToWeight          float64 // The weight in the "to" genome.
+	WeightDelta       float64 // How much the weight changed, to weight - from weight.
+	FromIsEnabled     bool    // Whether the gene was enabled in the "from" genome.
+	ToIsEnabled       bool    // Whether the gene was enabled in the "to" genome.
+	IsEnabledFlipped  bool    // The gene was enabled in one genome and disabled in the other.
+	FromFunction      string  // The activation function in the "from" genome, for node genes.
+	ToFunction        string  // The activation function in the "to" genome, for node genes.
+	IsFunctionChanged bool    // The activation function is different between the genomes.
+}
+
+// GeneDifference is a gene found in only one genome.
+type GeneDifference struct {
+	Genome    string // Which genome has the gene, DIFF_GENOME_FROM or DIFF_GENOME_TO.
+	GeneId    uint64
+	Type      string  // The type of gene this is.
+	From      string  // Describing the source of a connection.
+	To        string  // Describing the sink of a connection.
+	Weight    float64 // The weight of a connection.
+	IsEnabled bool    // Whether the gene is enabled.
+	Function  string  // The activation function for node genes.
+}
+
+// IsChanged is true if anything about the gene differs between the two genomes.
+func (m *GeneMatch) IsChanged() bool {
+	return m.WeightDelta != 0.0 || m.IsEnabledFlipped || m.IsFunctionChanged
+}
+
+// IsIdentical is true if the two genomes have exactly the same genes.
+func (d *GenomeDiff) IsIdentical() bool {
+	if len(d.Disjoint) > 0 || len(d.Excess) > 0 {
+		return false
+	}
+	for _, match := range d.Matching {
+		if match.IsChanged() {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffNeuralNets compares the genomes of two neural nets. The diff is plain data so it can be marshalled to json for other tools.
+func DiffNeuralNets(from NeatNeuralNet, to NeatNeuralNet) (diff GenomeDiff) {
+
+	// Pull all the genes together in gene id order. Genes in both genomes end up back-to-back, "from" gene first.
+	var fromCount int = len(from.Genome.Genes)
+	var genes []neatGene
+	genes = append(genes, from.Genome.Genes...)
+	genes = append(genes, to.Genome.Genes...)
+	var genomes []string = make([]string, len(genes))
+	for i := range genes {
+		genomes[i] = DIFF_GENOME_FROM
+		if i >= fromCount {
+			genomes[i] = DIFF_GENOME_TO
+		}
+	}
+	sort.Stable(genesWithGenomes{genes: genes, genomes: genomes})
+
+	// Genes newer than the newest gene of the other genome are excess. An empty genome makes every gene excess.
+	var maxGeneIds map[string]uint64 = map[string]uint64{
+		DIFF_GENOME_FROM: maxGeneIdOf(from.Genome.Genes),
+		DIFF_GENOME_TO:   maxGeneIdOf(to.Genome.Genes),
+	}
+	var otherGenome map[string]string = map[string]string{
+		DIFF_GENOME_FROM: DIFF_GENOME_TO,
+		DIFF_GENOME_TO:   DIFF_GENOME_FROM,
+	}
+
+	for i := 0; i < len(genes); i++ {
+		var gene neatGene = genes[i]
+
+		// Is this gene back-to-back with a gene of the same id (a shared gene)?
+		if i+1 < len(genes) && genes[i+1].GeneId == gene.GeneId {
+			var toGene neatGene = genes[i+1]
+			diff.Matching = append(diff.Matching, GeneMatch{
+				GeneId:            gene.GeneId,
+				Type:              gene.Type,
+				From:              gene.From,
+				To:                gene.To,
+				FromWeight:        gene.Weight,
+				ToWeight:          toGene.Weight,
+				WeightDelta:       toGene.Weight - gene.Weight,
+				FromIsEnabled:     gene.IsEnabled,
+				ToIsEnabled:       toGene.IsEnabled,
+				IsEnabledFlipped:  gene.IsEnabled != toGene.IsEnabled,
+				FromFunction:      gene.Function,
+				ToFunction:        toGene.Function,
+				IsFunctionChanged: gene.Function != toGene.Function,
+			})
+
+			// We've just "consumed" two genes instead of one.
+			i++
+			continue
+		}
+
+		// This gene is only in one genome.
+		var difference GeneDifference = GeneDifference{
+			Genome:    genomes[i],
+			GeneId:    gene.GeneId,
+			Type:      gene.Type,
+			From:      gene.From,
+			To:        gene.To,
+			Weight:    gene.Weight,
+			IsEnabled: gene.IsEnabled,
+			Function:  gene.Function,
+		}
+		if gene.GeneId > maxGeneIds[otherGenome[genomes[i]]] {
+			diff.Excess = append(diff.Excess, difference)
+		} else {
+			diff.Disjoint = append(diff.Disjoint, difference)
+		}
+	}
+
+	return diff
+}
+
+// String describes the diff for a person to read, one line per changed gene. Unchanged matching genes are only counted.
+func (d GenomeDiff) String() string {
+	var text bytes.Buffer
+
+	var changedCount int
+	for _, match := range d.Matching {
+		if match.IsChanged() {
+			changedCount++
+		}
+	}
+	fmt.Fprintf(&text, "matching: %d (changed: %d), disjoint: %d, excess: %d\n", len(d.Matching), changedCount, len(d.Disjoint), len(d.Excess))
+
+	for _, match := range d.Matching {
+		if !match.IsChanged() {
+			continue
+		}
+		fmt.Fprintf(&text, "  ~ %s", describeGene(match.GeneId, match.Type, match.From, match.To))
+		if match.WeightDelta != 0.0 {
+			fmt.Fprintf(&text, " weight %.6f -> %.6f (%+.6f)", match.FromWeight, match.ToWeight, match.WeightDelta)
+		}
+		if match.IsEnabledFlipped {
+			fmt.Fprintf(&text, " %s -> %s", describeEnabled(match.FromIsEnabled), describeEnabled(match.ToIsEnabled))
+		}
+		if match.IsFunctionChanged {
+			fmt.Fprintf(&text, " function %s -> %s", match.FromFunction, match.ToFunction)
+		}
+		fmt.Fprintln(&text)
+	}
+
+	// Genes only in "from" were lost (-), genes only in "to" were gained (+).
+	var differences = []struct {
+		kind        string
+		differences []GeneDifference
+	}{
+		{kind: "disjoint", differences: d.Disjoint},
+		{kind: "excess", differences: d.Excess},
+	}
+	for _, group := range differences {
+		for _, difference := range group.differences {
+			var sign string = "+"
+			if difference.Genome == DIFF_GENOME_FROM {
+				sign = "-"
+			}
+			fmt.Fprintf(&text, "  %s %s (%s)", sign, describeGene(difference.GeneId, difference.Type, difference.From, difference.To), group.kind)
+			if difference.Type == _GENE_TYPE_NODE {
+				fmt.Fprintf(&text, " function %s", difference.Function)
+			} else {
+				fmt.Fprintf(&text, " weight %.6f", difference.Weight)
+			}
+			if !difference.IsEnabled {
+				fmt.Fprintf(&text, " %s", describeEnabled(difference.IsEnabled))
+			}
+			fmt.Fprintln(&text)
+		}
+	}
+
+	return text.String()
+}
+
+// describeGene names a gene for a person to read.
+func describeGene(geneId uint64, geneType string, from string, to string) string {
+	if geneType == _GENE_TYPE_CONNECTION {
+		return fmt.Sprintf("%d connection %s -> %s:", geneId, from, to)
+	}
+	return fmt.Sprintf("%d %s:", geneId, geneType)
+}
+
+// describeEnabled names whether a gene is enabled for a person to read.
+func describeEnabled(isEnabled bool) string {
+	if isEnabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// maxGeneIdOf is the newest gene id in a list of genes, or 0 if there are none.
+func maxGeneIdOf(genes []neatGene) (maxGeneId uint64) {
+	for _, gene := range genes {
+		if gene.GeneId > maxGeneId {
+			maxGeneId = gene.GeneId
+		}
+	}
+	return maxGeneId
+}
+
+// genesWithGenomes implements sort.Interface to sort ascending by GeneId, keeping track of which genome each gene came from.
+type genesWithGenomes struct {
+	genes   []neatGene
+	genomes []string
+}
+
+func (a genesWithGenomes) Len() int { return len(a.genes) }
+func (a genesWithGenomes) Swap(i, j int) {
+	a.genes[i], a.genes[j] = a.genes[j], a.genes[i]
+	a.genomes[i], a.genomes[j] = a.genomes[j], a.genomes[i]
+}
+func (a genesWithGenomes) Less(i, j int) bool { return a.genes[i].GeneId < a.genes[j].GeneId }
@@ -0,0 +1,110 @@
+package genetic
+
+import (
+	"encoding/json"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type NeatGenomeDiffSuite struct{}
+
+var _ = Suite(&NeatGenomeDiffSuite{})
+
+// Add the tests.
+
+func (s *NeatGenomeDiffSuite) Test_DiffNeuralNets(c *C) {
+	var inOut NeuralNetInOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2"},
+		Outputs: []string{"o1"},
+	}
+
+	// A parent and a child that changed in every way possible.
+	var from NeatNeuralNet = NeatNeuralNet{
+		InOut: inOut,
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.5},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o1", Weight: 0.25},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+			neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "3", Weight: 0.1}, // Disjoint.
+			neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "3", To: "o1", Weight: 0.2},
+		}},
+	}
+	var to NeatNeuralNet = NeatNeuralNet{
+		InOut: inOut,
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: false, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.5}, // Disabled.
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o1", Weight: 0.75}, // Weight changed.
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_COSINE},              // Function changed.
+			neatGene{GeneId: 5, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "3", Weight: 0.3},   // Disjoint.
+			neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "3", To: "o1", Weight: 0.2},   // Unchanged.
+			neatGene{GeneId: 7, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_GAUSSIAN},            // Excess.
+		}},
+	}
+
+	var diff GenomeDiff = DiffNeuralNets(from, to)
+	c.Check(diff, DeepEquals, GenomeDiff{
+		Matching: []GeneMatch{
+			GeneMatch{GeneId: 1, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", FromWeight: 0.5, ToWeight: 0.5, FromIsEnabled: true, ToIsEnabled: false, IsEnabledFlipped: true},
+			GeneMatch{GeneId: 2, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o1", FromWeight: 0.25, ToWeight: 0.75, WeightDelta: 0.5, FromIsEnabled: true, ToIsEnabled: true},
+			GeneMatch{GeneId: 3, Type: _GENE_TYPE_NODE, FromIsEnabled: true, ToIsEnabled: true, FromFunction: ACTIVATION_SINE, ToFunction: ACTIVATION_COSINE, IsFunctionChanged: true},
+			GeneMatch{GeneId: 6, Type: _GENE_TYPE_CONNECTION, From: "3", To: "o1", FromWeight: 0.2, ToWeight: 0.2, FromIsEnabled: true, ToIsEnabled: true},
+		},
+		Disjoint: []GeneDifference{
+			GeneDifference{Genome: DIFF_GENOME_FROM, GeneId: 4, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "3", Weight: 0.1, IsEnabled: true},
+			GeneDifference{Genome: DIFF_GENOME_TO, GeneId: 5, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "3", Weight: 0.3, IsEnabled: true},
+		},
+		Excess: []GeneDifference{
+			GeneDifference{Genome: DIFF_GENOME_TO, GeneId: 7, Type: _GENE_TYPE_NODE, IsEnabled: true, Function: ACTIVATION_GAUSSIAN},
+		},
+	})
+	c.Check(diff.IsIdentical(), Equals, false)
+
+	// The person-readable form.
+	c.Check(diff.String(), Equals, "matching: 4 (changed: 3), disjoint: 2, excess: 1\n"+
+		"  ~ 1 connection i1 -> o1: enabled -> disabled\n"+
+		"  ~ 2 connection i2 -> o1: weight 0.250000 -> 0.750000 (+0.500000)\n"+
+		"  ~ 3 node: function "+ACTIVATION_SINE+" -> "+ACTIVATION_COSINE+"\n"+
+		"  - 4 connection i1 -> 3: (disjoint) weight 0.100000\n"+
+		"  + 5 connection i2 -> 3: (disjoint) weight 0.300000\n"+
+		"  + 7 node: (excess) function "+ACTIVATION_GAUSSIAN+"\n")
+
+	// The machine-readable form.
+	var bytes []byte
+	var err error
+	bytes, err = json.Marshal(diff)
+	c.Assert(err, IsNil)
+	var unmarshalled GenomeDiff
+	c.Assert(json.Unmarshal(bytes, &unmarshalled), IsNil)
+	c.Check(unmarshalled, DeepEquals, diff)
+
+	// The "from" genome can be the younger one too.
+	var reversed GenomeDiff = DiffNeuralNets(to, from)
+	c.Check(len(reversed.Matching), Equals, 4)
+	c.Check(reversed.Matching[1].WeightDelta, Equals, -0.5)
+	c.Check(len(reversed.Disjoint), Equals, 2)
+	c.Check(reversed.Excess, DeepEquals, []GeneDifference{
+		GeneDifference{Genome: DIFF_GENOME_FROM, GeneId: 7, Type: _GENE_TYPE_NODE, IsEnabled: true, Function: ACTIVATION_GAUSSIAN},
+	})
+}
+
+func (s *NeatGenomeDiffSuite) Test_DiffNeuralNets_Identical(c *C) {
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1"},
+			Outputs: []string{"o1"},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.5},
+		}},
+	}
+
+	var diff GenomeDiff = DiffNeuralNets(neuralNet, neuralNet.makeClone())
+	c.Check(diff.IsIdentical(), Equals, true)
+	c.Check(diff.String(), Equals, "matching: 1 (changed: 0), disjoint: 0, excess: 0\n")
+
+	// Every gene of an empty genome's partner is excess.
+	diff = DiffNeuralNets(NeatNeuralNet{InOut: neuralNet.InOut}, neuralNet)
+	c.Check(len(diff.Matching), Equals, 0)
+	c.Check(len(diff.Disjoint), Equals, 0)
+	c.Check(len(diff.Excess), Equals, 1)
+}
@@ -1,7 +1,10 @@
 package genetic
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"math/rand"
 	"sort"
 	"strconv"
@@ -16,6 +19,25 @@ type NeatNeuralNet struct {
 	topology computeTopology
 }
 
+// LoadNeatNeuralNet loads a neural net saved as json, such as a specimen pulled from recorded experiment results.
+func LoadNeatNeuralNet(filename string) (NeatNeuralNet, error) {
+	var err error
+	var bytes []byte
+	var neuralNet NeatNeuralNet
+
+	log.Printf("Loading NeatNeuralNet: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return NeatNeuralNet{}, err
+	}
+	if err = json.Unmarshal(bytes, &neuralNet); err != nil {
+		return NeatNeuralNet{}, err
+	}
+
+	return neuralNet, error(nil)
+}
+
 // newNeatNeuralNet creates a new well-formed NEAT neural net for the given inputs/outputs. All outputs must be able to produce a value when
 // the neural net is run so one random connction to an inptu will be made for each output. The new next innovation number (used to
 // identify genes across the experiment)
@@ -27,12 +49,13 @@ func newNeatNeuralNet(inOut NeuralNetInOut) (neuralNet NeatNeuralNet) {
 	}
 
 	// Connect every output to one of the input values.
+	var networkInputs []string = neuralNet.InOut.networkInputs()
 	for _, out := range neuralNet.InOut.Outputs {
 
 		// Pick a random input.
 		var ok bool
-		var inputIndex int = rand.Intn(len(neuralNet.InOut.Inputs))
-		var in string = neuralNet.InOut.Inputs[inputIndex]
+		var inputIndex int = rand.Intn(len(networkInputs))
+		var in string = networkInputs[inputIndex]
 
 		// Pick a random weight.
 		var weight float64
@@ -80,7 +103,8 @@ func (c *NeatNeuralNet) addConnection(from string, to string, weight float64) (w
 	}
 
 	// Connections cannot be made to inputs.
-	if inStrings(c.InOut.Inputs, to) {
+	var networkInputs []string = c.InOut.networkInputs()
+	if inStrings(networkInputs, to) {
 		panic(fmt.Sprintf("Cannot use input as sink: '%s'", to))
 	}
 
@@ -100,7 +124,7 @@ func (c *NeatNeuralNet) addConnection(from string, to string, weight float64) (w
 
 	// We don't need to test for circular dependencies of the from is an input or bias, and to is an output.
 	var isFromInput bool
-	if from == NODE_BIAS || inStrings(c.InOut.Inputs, from) {
+	if from == NODE_BIAS || inStrings(networkInputs, from) {
 		isFromInput = true
 	}
 	var isToOutput bool
@@ -211,9 +235,9 @@ func (c *NeatNeuralNet) mutateAddConnection(maxAttempts int) (wasAdded bool) {
 	}
 
 	// What are all the nodes we can make a connection from?
-	var fromNodes []string = []string{NODE_BIAS}     // Start with the bias node itself.
-	fromNodes = append(fromNodes, c.InOut.Inputs...) // Add the inputs.
-	fromNodes = append(fromNodes, hiddenNodes...)    // Add the hidden nodes.
+	var fromNodes []string = []string{NODE_BIAS}              // Start with the bias node itself.
+	fromNodes = append(fromNodes, c.InOut.networkInputs()...) // Add the inputs.
+	fromNodes = append(fromNodes, hiddenNodes...)             // Add the hidden nodes.
 
 	// What are all the nodes we can make a connection to?
 	var toNodes []string
@@ -368,21 +392,23 @@ func (c *NeatNeuralNet) Compute(inputs map[string]float64) (outputs map[string]f
 	// Add a sanity double-check to ensure we are not making any mistakes.
 	var sinkTally map[string]uint = map[string]uint{}
 
-	// Start by putting in all the input values.
+	// Start by putting in all the input values, normalized if the in/out asks for it.
 	for _, in := range c.InOut.Inputs {
 		// Did we pass in this input?
 		var value float64
 		if value, ok = inputs[in]; !ok {
 			panic(fmt.Sprintf("Missing input: '%s'", in))
 		}
-		nodeValues[in] = value
+		c.InOut.normalizeInput(in, value, nodeValues)
+	}
+	for _, in := range c.InOut.networkInputs() {
 		sinkTally[in] = 0 // Inputs will never have other nodes use them as a sink.
 	}
 
-	// All inputs passed in should now have node values.
+	// All inputs passed in should be known inputs.
 	// Sanity check we didn't pass in any invalid inputs.
 	for in, _ := range inputs {
-		if _, ok = nodeValues[in]; !ok {
+		if !inStrings(c.InOut.Inputs, in) {
 			panic(fmt.Sprintf("Unknown input: '%s'", in))
 		}
 	}
@@ -407,8 +433,10 @@ func (c *NeatNeuralNet) Compute(inputs map[string]float64) (outputs map[string]f
 		var value float64 = nodeValues[nodeId]
 
 		// If this node has a function, run the function on the value to get the value it will pass on.
+		// Keep the activated value, it is what an output node reports.
 		if node.function != "" {
 			value = activate(node.function, value)
+			nodeValues[nodeId] = value
 		}
 
 		// Send this node to each sink it has, applying the weight of the connection.
@@ -441,5 +469,9 @@ func (c *NeatNeuralNet) Compute(inputs map[string]float64) (outputs map[string]f
 		}
 		outputs[out] = value
 	}
+
+	// Some outputs may be squashed together as a group.
+	c.InOut.applySoftmaxGroups(outputs)
+
 	return outputs
 }
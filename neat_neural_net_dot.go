@@ -0,0 +1,180 @@
+package genetic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+const (
+	// Connection lines are drawn between these widths, scaled by the size of the connection weight.
+	_DRAW_MIN_EDGE_WIDTH = 0.5
+	_DRAW_MAX_EDGE_WIDTH = 5.0
+
+	// Connection lines are colored by the sign of the connection weight.
+	_DRAW_POSITIVE_COLOR = "blue"
+	_DRAW_NEGATIVE_COLOR = "red"
+	_DRAW_DISABLED_COLOR = "gray"
+)
+
+// DrawOptions describes how a neural net is drawn.
+type DrawOptions struct {
+	ShowDisabled bool // Draw disabled connections as dashed lines.
+}
+
+// neuralNetLayout is a neural net organized for drawing. Nodes are in ranked layers, with the bias and inputs in the
+// first layer, the outputs in the last layer, and each hidden node in the layer after the deepest node that feeds it.
+type neuralNetLayout struct {
+	layers       [][]string        // The nodes in each layer, in the order to draw them.
+	labels       map[string]string // The text to show for each node.
+	connections  []neatGene        // The connections to draw.
+	maxAbsWeight float64           // The largest weight of any connection, for scaling line widths.
+}
+
+// newNeuralNetLayout organizes a neural net for drawing.
+func newNeuralNetLayout(neuralNet NeatNeuralNet, options DrawOptions) (layout neuralNetLayout, err error) {
+
+	// Get the neural net in the order it is computed. Each node is computed after every node that feeds it.
+	var topology computeTopology
+	var ok bool
+	if topology, ok = makeComputeTopology(neuralNet.InOut, neuralNet.Genome.Genes); !ok {
+		return neuralNetLayout{}, fmt.Errorf("neural net has a circular dependency in Genome: %+v", neuralNet.Genome.Genes)
+	}
+
+	// Each node is one layer deeper than the deepest node feeding it.
+	var depths map[string]int = map[string]int{}
+	var maxDepth int = 1 // Outputs are always at least one layer past the inputs.
+	for _, nodeId := range topology.orderedNodes {
+		for sink := range topology.nodes[nodeId].sinks {
+			if depths[sink] < depths[nodeId]+1 {
+				depths[sink] = depths[nodeId] + 1
+			}
+			if depths[sink] > maxDepth {
+				maxDepth = depths[sink]
+			}
+		}
+	}
+
+	// Every output is in the last layer, however deep it really is.
+	for _, out := range neuralNet.InOut.Outputs {
+		depths[out] = maxDepth
+	}
+
+	// Put the nodes in their layers, bias first, then inputs, hidden nodes, and outputs.
+	var nodeIds []string = []string{NODE_BIAS}
+	nodeIds = append(nodeIds, neuralNet.InOut.networkInputs()...)
+	var hiddenNodeIds []string
+	for _, gene := range neuralNet.Genome.Genes {
+		if gene.IsEnabled == true && gene.Type == _GENE_TYPE_NODE {
+			hiddenNodeIds = append(hiddenNodeIds, strconv.FormatUint(gene.GeneId, _BASE_10))
+		}
+	}
+	nodeIds = append(nodeIds, hiddenNodeIds...)
+	nodeIds = append(nodeIds, neuralNet.InOut.Outputs...)
+	layout.layers = make([][]string, maxDepth+1)
+	for _, nodeId := range nodeIds {
+		layout.layers[depths[nodeId]] = append(layout.layers[depths[nodeId]], nodeId)
+	}
+
+	// Label every node. Nodes with a function show it.
+	layout.labels = map[string]string{}
+	for _, nodeId := range nodeIds {
+		var label string = nodeId
+		var function string = topology.nodes[nodeId].function
+		if function != "" {
+			label = nodeId + "\n" + function
+		}
+		layout.labels[nodeId] = label
+	}
+
+	// Gather the connections to draw.
+	for _, gene := range neuralNet.Genome.Genes {
+		if gene.Type != _GENE_TYPE_CONNECTION {
+			continue
+		}
+		// Only draw disabled connections if asked, and only if both ends are still drawn.
+		if !gene.IsEnabled {
+			if !options.ShowDisabled {
+				continue
+			}
+			if _, ok = layout.labels[gene.From]; !ok {
+				continue
+			}
+			if _, ok = layout.labels[gene.To]; !ok {
+				continue
+			}
+		}
+		layout.connections = append(layout.connections, gene)
+		layout.maxAbsWeight = math.Max(layout.maxAbsWeight, math.Abs(gene.Weight))
+	}
+
+	return layout, nil
+}
+
+// edgeWidth is the width to draw a connection, thicker for larger weights.
+func (l *neuralNetLayout) edgeWidth(weight float64) float64 {
+	if l.maxAbsWeight == 0.0 {
+		return _DRAW_MIN_EDGE_WIDTH
+	}
+	return _DRAW_MIN_EDGE_WIDTH + (_DRAW_MAX_EDGE_WIDTH-_DRAW_MIN_EDGE_WIDTH)*math.Abs(weight)/l.maxAbsWeight
+}
+
+// edgeColor is the color to draw a connection.
+func edgeColor(gene neatGene) string {
+	if !gene.IsEnabled {
+		return _DRAW_DISABLED_COLOR
+	}
+	if gene.Weight < 0.0 {
+		return _DRAW_NEGATIVE_COLOR
+	}
+	return _DRAW_POSITIVE_COLOR
+}
+
+// WriteDot writes the neural net as a Graphviz DOT graph.
+// Reference: https://graphviz.org/doc/info/lang.html
+func (c *NeatNeuralNet) WriteDot(writer io.Writer, options DrawOptions) (err error) {
+	var layout neuralNetLayout
+	if layout, err = newNeuralNetLayout(*c, options); err != nil {
+		return err
+	}
+
+	// Quickly know what kind of node each node is.
+	var networkInputs []string = c.InOut.networkInputs()
+
+	var dot bytes.Buffer
+	fmt.Fprintln(&dot, "digraph neural_net {")
+	fmt.Fprintln(&dot, "\trankdir=LR;")
+	fmt.Fprintln(&dot, "\tnode [shape=circle];")
+
+	// Each layer is drawn at the same rank.
+	for i, layer := range layout.layers {
+		fmt.Fprintf(&dot, "\tsubgraph layer_%d {\n", i)
+		fmt.Fprintln(&dot, "\t\trank=same;")
+		for _, nodeId := range layer {
+			var shape string = "circle"
+			if nodeId == NODE_BIAS || inStrings(networkInputs, nodeId) {
+				shape = "box"
+			} else if inStrings(c.InOut.Outputs, nodeId) {
+				shape = "doublecircle"
+			}
+			fmt.Fprintf(&dot, "\t\t%s [label=%s, shape=%s];\n", strconv.Quote(nodeId), strconv.Quote(layout.labels[nodeId]), shape)
+		}
+		fmt.Fprintln(&dot, "\t}")
+	}
+
+	// The connections, in gene order.
+	for _, gene := range layout.connections {
+		var style string
+		if !gene.IsEnabled {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&dot, "\t%s -> %s [color=%s, penwidth=%.2f, label=\"%.3f\"%s];\n", strconv.Quote(gene.From), strconv.Quote(gene.To), edgeColor(gene), layout.edgeWidth(gene.Weight), gene.Weight, style)
+	}
+
+	fmt.Fprintln(&dot, "}")
+
+	_, err = writer.Write(dot.Bytes())
+	return err
+}
@@ -0,0 +1,120 @@
+package genetic
+
+import (
+	"bytes"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"strings"
+)
+
+// Create a suite.
+type NeatNeuralNetDrawSuite struct{}
+
+var _ = Suite(&NeatNeuralNetDrawSuite{})
+
+// drawTestNeuralNet is a small neural net with a hidden node and a disabled connection.
+func drawTestNeuralNet() NeatNeuralNet {
+	return NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1"},
+			Outputs: []string{"o1"},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: false, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.5},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "2", Weight: 2.0},
+			neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "o1", Weight: -1.0},
+		}},
+	}
+}
+
+// Add the tests.
+
+func (s *NeatNeuralNetDrawSuite) Test_NewNeuralNetLayout(c *C) {
+	var neuralNet NeatNeuralNet = drawTestNeuralNet()
+
+	var layout neuralNetLayout
+	var err error
+	layout, err = newNeuralNetLayout(neuralNet, DrawOptions{})
+	c.Assert(err, IsNil)
+	c.Check(layout.layers, DeepEquals, [][]string{{"b", "i1"}, {"2"}, {"o1"}})
+	c.Check(layout.labels, DeepEquals, map[string]string{"b": "b", "i1": "i1", "2": "2\n" + ACTIVATION_SINE, "o1": "o1"})
+	c.Check(len(layout.connections), Equals, 2)
+	c.Check(layout.maxAbsWeight, Equals, 2.0)
+	c.Check(layout.edgeWidth(2.0), Equals, _DRAW_MAX_EDGE_WIDTH)
+	c.Check(layout.edgeWidth(0.0), Equals, _DRAW_MIN_EDGE_WIDTH)
+
+	// Disabled connections only when asked.
+	layout, err = newNeuralNetLayout(neuralNet, DrawOptions{ShowDisabled: true})
+	c.Assert(err, IsNil)
+	c.Check(len(layout.connections), Equals, 3)
+
+	// Circular neural nets cannot be drawn.
+	neuralNet.Genome.Genes = append(neuralNet.Genome.Genes, neatGene{GeneId: 5, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "2", Weight: 1.0})
+	_, err = newNeuralNetLayout(neuralNet, DrawOptions{})
+	c.Check(err, ErrorMatches, `neural net has a circular dependency.*`)
+}
+
+func (s *NeatNeuralNetDrawSuite) Test_EdgeColor(c *C) {
+	c.Check(edgeColor(neatGene{IsEnabled: true, Weight: 1.0}), Equals, _DRAW_POSITIVE_COLOR)
+	c.Check(edgeColor(neatGene{IsEnabled: true, Weight: -1.0}), Equals, _DRAW_NEGATIVE_COLOR)
+	c.Check(edgeColor(neatGene{IsEnabled: false, Weight: -1.0}), Equals, _DRAW_DISABLED_COLOR)
+}
+
+func (s *NeatNeuralNetDrawSuite) Test_NeatNeuralNet_WriteDot(c *C) {
+	var neuralNet NeatNeuralNet = drawTestNeuralNet()
+
+	var dot bytes.Buffer
+	c.Assert(neuralNet.WriteDot(&dot, DrawOptions{ShowDisabled: true}), IsNil)
+	c.Check(dot.String(), Equals, `digraph neural_net {
+	rankdir=LR;
+	node [shape=circle];
+	subgraph layer_0 {
+		rank=same;
+		"b" [label="b", shape=box];
+		"i1" [label="i1", shape=box];
+	}
+	subgraph layer_1 {
+		rank=same;
+		"2" [label="2\n`+ACTIVATION_SINE+`", shape=circle];
+	}
+	subgraph layer_2 {
+		rank=same;
+		"o1" [label="o1", shape=doublecircle];
+	}
+	"i1" -> "o1" [color=gray, penwidth=1.62, label="0.500", style=dashed];
+	"i1" -> "2" [color=blue, penwidth=5.00, label="2.000"];
+	"2" -> "o1" [color=red, penwidth=2.75, label="-1.000"];
+}
+`)
+
+	// The same neural net always draws the same way.
+	var again bytes.Buffer
+	c.Assert(neuralNet.WriteDot(&again, DrawOptions{ShowDisabled: true}), IsNil)
+	c.Check(again.String(), Equals, dot.String())
+}
+
+func (s *NeatNeuralNetDrawSuite) Test_NeatNeuralNet_WriteSvg(c *C) {
+	var neuralNet NeatNeuralNet = drawTestNeuralNet()
+
+	var svg bytes.Buffer
+	c.Assert(neuralNet.WriteSvg(&svg, DrawOptions{}), IsNil)
+	var drawing string = svg.String()
+
+	c.Check(strings.HasPrefix(drawing, _SVG_DRAWING_HEADER), Equals, true)
+	c.Check(strings.HasSuffix(drawing, "</svg>\n"), Equals, true)
+	c.Check(strings.Count(drawing, "<circle "), Equals, 4)
+	c.Check(strings.Count(drawing, "<line "), Equals, 2)
+	c.Check(strings.Contains(drawing, "stroke-dasharray"), Equals, false)
+	c.Check(strings.Contains(drawing, ">"+ACTIVATION_SINE+"</text>"), Equals, true)
+	c.Check(strings.Contains(drawing, "<title>2 -&gt; o1: -1.000</title>"), Equals, true)
+
+	// Disabled connections are dashed.
+	svg.Reset()
+	c.Assert(neuralNet.WriteSvg(&svg, DrawOptions{ShowDisabled: true}), IsNil)
+	c.Check(strings.Count(svg.String(), "<line "), Equals, 3)
+	c.Check(strings.Count(svg.String(), "stroke-dasharray"), Equals, 1)
+}
+
+func (s *NeatNeuralNetDrawSuite) Test_SvgEscape(c *C) {
+	c.Check(svgEscape(`a<b & "c"`), Equals, "a&lt;b &amp; &#34;c&#34;")
+}
@@ -0,0 +1,346 @@
+package genetic
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goSourceActivations are the standalone Go functions for each activation function, matching activation_function.go
+// exactly so generated code computes the same values. Each is a format string taking the function name.
+var goSourceActivations map[string]string = map[string]string{
+	ACTIVATION_SIGMOID: `func %s(input float64) float64 {
+	if input < -100.0 {
+		return 0.0
+	}
+	return 1.0 / (1.0 + math.Exp(-1.0*input))
+}`,
+	ACTIVATION_BIPOLAR_SIGMOID: `func %s(input float64) float64 {
+	if input < -100.0 {
+		return -1.0
+	}
+	return (1.0 - math.Exp(-1.0*input)) / (1.0 + math.Exp(-1.0*input))
+}`,
+	ACTIVATION_GAUSSIAN: `func %s(input float64) float64 {
+	return math.Exp(-1.0 * (input * input))
+}`,
+	ACTIVATION_INVERSE: `func %s(input float64) float64 {
+	return -1.0 * input
+}`,
+	ACTIVATION_SINE: `func %s(input float64) float64 {
+	return math.Sin(input)
+}`,
+	ACTIVATION_COSINE: `func %s(input float64) float64 {
+	return math.Cos(input)
+}`,
+	ACTIVATION_TANGENT: `func %s(input float64) float64 {
+	return math.Tan(input)
+}`,
+	ACTIVATION_HYPERBOLIC_TANGENT: `func %s(input float64) float64 {
+	return math.Tanh(input)
+}`,
+	ACTIVATION_RAMP: `func %s(input float64) float64 {
+	return 1.0 - 2.0*(input-math.Floor(input))
+}`,
+	ACTIVATION_STEP: `func %s(input float64) float64 {
+	if math.Mod(math.Floor(input), 2.0) == 0.0 {
+		return 1.0
+	}
+	return -1.0
+}`,
+	ACTIVATION_SPIKE: `func %s(input float64) float64 {
+	if math.Mod(math.Abs(math.Floor(input)), 2.0) == 0.0 {
+		return 1.0 - 2.0*(input-math.Floor(input))
+	}
+	return -1.0 + 2.0*(input-math.Floor(input))
+}`,
+}
+
+// _GO_SOURCE_SOFTMAX is the standalone Go function for squashing a softmax group, matching softmax() exactly.
+const _GO_SOURCE_SOFTMAX = `func %s(inputs ...float64) []float64 {
+	var max float64 = inputs[0]
+	for _, input := range inputs {
+		if input > max {
+			max = input
+		}
+	}
+	var outputs []float64
+	var total float64
+	for _, input := range inputs {
+		var exp float64 = math.Exp(input - max)
+		outputs = append(outputs, exp)
+		total += exp
+	}
+	for i := range outputs {
+		outputs[i] /= total
+	}
+	return outputs
+}`
+
+// GenerateGoSource turns the neural net into standalone Go source, a single function with no dependency on this package.
+// The function takes a struct with a float64 field for each input and returns a struct with a float64 field for each output.
+// Field names are the input and output names made into exported Go identifiers. The computation is unrolled in
+// topological order, skipping anything that cannot affect an output. Input normalization, output functions, and softmax
+// groups are all included. Helper functions and types are prefixed with the function name so several generated
+// neural nets can live in the same package.
+func GenerateGoSource(neuralNet NeatNeuralNet, packageName string, funcName string) (source []byte, err error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("invalid package name: '%s'", packageName)
+	}
+	if !token.IsIdentifier(funcName) || funcName == "_" {
+		return nil, fmt.Errorf("invalid function name: '%s'", funcName)
+	}
+
+	// The neural net must be computable.
+	var topology computeTopology
+	var ok bool
+	if topology, ok = makeComputeTopology(neuralNet.InOut, neuralNet.Genome.Genes); !ok {
+		return nil, fmt.Errorf("neural net has a circular dependency in Genome: %+v", neuralNet.Genome.Genes)
+	}
+
+	// Name everything.
+	var inputsType string = funcName + "Inputs"
+	var outputsType string = funcName + "Outputs"
+	var helperPrefix string = string(unicode.ToLower(rune(funcName[0]))) + funcName[1:]
+	var inputFields map[string]string
+	if inputFields, err = goFieldNames(neuralNet.InOut.Inputs); err != nil {
+		return nil, err
+	}
+	var outputFields map[string]string
+	if outputFields, err = goFieldNames(neuralNet.InOut.Outputs); err != nil {
+		return nil, err
+	}
+
+	// Connection weights are written as Go constants.
+	for _, gene := range neuralNet.Genome.Genes {
+		if gene.IsEnabled == true && gene.Type == _GENE_TYPE_CONNECTION && (math.IsNaN(gene.Weight) || math.IsInf(gene.Weight, 0)) {
+			return nil, fmt.Errorf("connection gene %d has a weight that cannot be written as Go: %v", gene.GeneId, gene.Weight)
+		}
+	}
+
+	// Only nodes that can reach an output are computed, in an order that is always the same.
+	var orderedNodes []string
+	var sources map[string][]neatGene
+	orderedNodes, sources = makeLiveComputeOrder(neuralNet.InOut, neuralNet.Genome.Genes)
+
+	// Every node gets a simple variable name.
+	var variables map[string]string = map[string]string{}
+	for i, nodeId := range orderedNodes {
+		variables[nodeId] = "v" + strconv.Itoa(i)
+	}
+
+	// Where does each one-hot category node come from?
+	type oneHotCategory struct {
+		in    string
+		value float64
+	}
+	var oneHotCategories map[string]oneHotCategory = map[string]oneHotCategory{}
+	for in, normalization := range neuralNet.InOut.InputNormalizations {
+		for _, category := range normalization.Categories {
+			oneHotCategories[oneHotInput(in, category.Name)] = oneHotCategory{in: in, value: category.Value}
+		}
+	}
+
+	// The helper functions needed.
+	var helpers map[string]string = map[string]string{}
+	var helperName func(function string) string = func(function string) string {
+		var name string = helperPrefix + goIdentifier(function)
+		helpers[function] = name
+		return name
+	}
+
+	// Unroll the computation.
+	var body bytes.Buffer
+	for _, nodeId := range orderedNodes {
+		var variable string = variables[nodeId]
+
+		// The bias.
+		if nodeId == NODE_BIAS {
+			fmt.Fprintf(&body, "// Bias.\n")
+			fmt.Fprintf(&body, "var %s float64 = 1.0\n", variable)
+			continue
+		}
+
+		// A one-hot category of an input.
+		var category oneHotCategory
+		if category, ok = oneHotCategories[nodeId]; ok {
+			fmt.Fprintf(&body, "// Input %s.\n", strconv.Quote(nodeId))
+			fmt.Fprintf(&body, "var %s float64\n", variable)
+			fmt.Fprintf(&body, "if inputs.%s == %s {\n%s = 1.0\n}\n", inputFields[category.in], goFloat(category.value), variable)
+			continue
+		}
+
+		// An input, normalized if the in/out asks for it.
+		if inStrings(neuralNet.InOut.Inputs, nodeId) {
+			var normalization InputNormalization = neuralNet.InOut.InputNormalizations[nodeId]
+			var field string = "inputs." + inputFields[nodeId]
+			fmt.Fprintf(&body, "// Input %s.\n", strconv.Quote(nodeId))
+			switch normalization.Method {
+			case "":
+				fmt.Fprintf(&body, "var %s float64 = %s\n", variable, field)
+			case NORMALIZE_MIN_MAX:
+				fmt.Fprintf(&body, "var %s float64 = (%s - %s) / (%s - %s)\n", variable, field, goFloat(normalization.Min), goFloat(normalization.Max), goFloat(normalization.Min))
+			case NORMALIZE_Z_SCORE:
+				fmt.Fprintf(&body, "var %s float64 = (%s - %s) / %s\n", variable, field, goFloat(normalization.Mean), goFloat(normalization.StdDev))
+			default:
+				return nil, fmt.Errorf("input '%s' has a normalization method that cannot be written as Go: '%s'", nodeId, normalization.Method)
+			}
+			continue
+		}
+
+		// A hidden node or output, the weighted sum of the nodes feeding it.
+		var terms []string
+		for _, gene := range sources[nodeId] {
+			terms = append(terms, variables[gene.From]+"*"+goFloat(gene.Weight))
+		}
+		var value string = "0.0"
+		if len(terms) > 0 {
+			value = strings.Join(terms, " + ")
+		}
+		var function string = topology.nodes[nodeId].function
+		if function != "" {
+			if _, ok = goSourceActivations[function]; !ok {
+				return nil, fmt.Errorf("node '%s' has an unknown activation function: '%s'", nodeId, function)
+			}
+			value = helperName(function) + "(" + value + ")"
+		}
+		if inStrings(neuralNet.InOut.Outputs, nodeId) {
+			fmt.Fprintf(&body, "// Output %s.\n", strconv.Quote(nodeId))
+		} else {
+			fmt.Fprintf(&body, "// Node %s.\n", strconv.Quote(nodeId))
+		}
+		fmt.Fprintf(&body, "var %s float64 = %s\n", variable, value)
+	}
+
+	// Gather the outputs.
+	fmt.Fprintf(&body, "\n")
+	for _, out := range neuralNet.InOut.Outputs {
+		fmt.Fprintf(&body, "outputs.%s = %s\n", outputFields[out], variables[out])
+	}
+
+	// Squash each softmax group, in name order so the generated code is always the same.
+	var groupNames []string
+	for group := range neuralNet.InOut.SoftmaxGroups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+	var softmaxName string = helperPrefix + "Softmax"
+	for i, group := range groupNames {
+		var outs []string = neuralNet.InOut.SoftmaxGroups[group]
+		var groupVariable string = "softmax" + strconv.Itoa(i)
+		var values []string
+		for _, out := range outs {
+			values = append(values, variables[out])
+		}
+		fmt.Fprintf(&body, "\n// Softmax group %s.\n", strconv.Quote(group))
+		fmt.Fprintf(&body, "var %s []float64 = %s(%s)\n", groupVariable, softmaxName, strings.Join(values, ", "))
+		for j, out := range outs {
+			fmt.Fprintf(&body, "outputs.%s = %s[%d]\n", outputFields[out], groupVariable, j)
+		}
+	}
+
+	// Put the whole file together.
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by go-genetic GenerateGoSource. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&file, "package %s\n\n", packageName)
+	if len(helpers) > 0 || len(groupNames) > 0 {
+		fmt.Fprintf(&file, "import \"math\"\n\n")
+	}
+
+	fmt.Fprintf(&file, "// %s are the inputs of %s.\n", inputsType, funcName)
+	fmt.Fprintf(&file, "type %s struct {\n", inputsType)
+	for _, in := range neuralNet.InOut.Inputs {
+		fmt.Fprintf(&file, "%s float64 // %s\n", inputFields[in], strconv.Quote(in))
+	}
+	fmt.Fprintf(&file, "}\n\n")
+
+	fmt.Fprintf(&file, "// %s are the outputs of %s.\n", outputsType, funcName)
+	fmt.Fprintf(&file, "type %s struct {\n", outputsType)
+	for _, out := range neuralNet.InOut.Outputs {
+		fmt.Fprintf(&file, "%s float64 // %s\n", outputFields[out], strconv.Quote(out))
+	}
+	fmt.Fprintf(&file, "}\n\n")
+
+	fmt.Fprintf(&file, "// %s computes the neural net.\n", funcName)
+	fmt.Fprintf(&file, "func %s(inputs %s) (outputs %s) {\n", funcName, inputsType, outputsType)
+	file.Write(body.Bytes())
+	fmt.Fprintf(&file, "return outputs\n}\n")
+
+	// The helpers, in name order so the generated code is always the same.
+	var functions []string
+	for function := range helpers {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+	for _, function := range functions {
+		fmt.Fprintf(&file, "\n// %s is the %s activation function.\n", helpers[function], function)
+		fmt.Fprintf(&file, goSourceActivations[function]+"\n", helpers[function])
+	}
+	if len(groupNames) > 0 {
+		fmt.Fprintf(&file, "\n// %s squashes a softmax group.\n", softmaxName)
+		fmt.Fprintf(&file, _GO_SOURCE_SOFTMAX+"\n", softmaxName)
+	}
+
+	// Make it look like any other Go source.
+	if source, err = format.Source(file.Bytes()); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// goFieldNames makes each name an exported Go identifier, failing if two names end up the same.
+func goFieldNames(names []string) (fields map[string]string, err error) {
+	fields = map[string]string{}
+	var nameOfField map[string]string = map[string]string{}
+	for _, name := range names {
+		var field string = goIdentifier(name)
+		var otherName string
+		var ok bool
+		if otherName, ok = nameOfField[field]; ok {
+			return nil, fmt.Errorf("'%s' and '%s' are both the Go name '%s'", otherName, name, field)
+		}
+		nameOfField[field] = name
+		fields[name] = field
+	}
+	return fields, nil
+}
+
+// goIdentifier makes a name into an exported Go identifier. Anything other than letters and digits is dropped, with the
+// next letter capitalized instead (e.g. "card_id" becomes "CardId").
+func goIdentifier(name string) string {
+	var identifier []rune
+	var isWordStart bool = true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			isWordStart = true
+			continue
+		}
+		if isWordStart {
+			r = unicode.ToUpper(r)
+			isWordStart = false
+		}
+		identifier = append(identifier, r)
+	}
+
+	// Identifiers cannot start with a digit, or be empty.
+	if len(identifier) == 0 || unicode.IsDigit(identifier[0]) {
+		identifier = append([]rune("X"), identifier...)
+	}
+	return string(identifier)
+}
+
+// goFloat writes a finite float as Go source, exactly.
+func goFloat(value float64) string {
+	var text string = strconv.FormatFloat(value, 'g', -1, 64)
+	// Keep it a float constant so it never reads as an integer.
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
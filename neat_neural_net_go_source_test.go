@@ -0,0 +1,223 @@
+package genetic
+
+import (
+	"bytes"
+	"encoding/json"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Create a suite.
+type NeatNeuralNetGoSourceSuite struct{}
+
+var _ = Suite(&NeatNeuralNetGoSourceSuite{})
+
+// goSourceTestNeuralNet is a neural net using every activation function, normalization, output function, and a softmax group.
+func goSourceTestNeuralNet() NeatNeuralNet {
+	var genes []neatGene
+	var geneId uint64
+	var addGene = func(gene neatGene) {
+		geneId++
+		gene.GeneId = geneId
+		gene.IsEnabled = true
+		genes = append(genes, gene)
+	}
+
+	// A chain of hidden nodes, one per activation function, fed by every input.
+	var functions []string = []string{
+		ACTIVATION_SIGMOID, ACTIVATION_BIPOLAR_SIGMOID, ACTIVATION_GAUSSIAN, ACTIVATION_INVERSE, ACTIVATION_SINE, ACTIVATION_COSINE,
+		ACTIVATION_TANGENT, ACTIVATION_HYPERBOLIC_TANGENT, ACTIVATION_RAMP, ACTIVATION_STEP, ACTIVATION_SPIKE,
+	}
+	var previous string = "raw value"
+	for i, function := range functions {
+		addGene(neatGene{Type: _GENE_TYPE_NODE, Function: function})
+		var nodeId string = strconv.FormatUint(geneId, _BASE_10)
+		addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: previous, To: nodeId, Weight: 0.3 + 0.1*float64(i)})
+		addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: NODE_BIAS, To: nodeId, Weight: -0.2})
+		addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "scaled", To: nodeId, Weight: 0.15})
+		addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "z", To: nodeId, Weight: -0.05})
+		previous = nodeId
+	}
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: previous, To: "out_a", Weight: 1.5})
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "suit:hearts", To: "out_a", Weight: 0.75})
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: previous, To: "out b", Weight: -2.0})
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "suit:spades", To: "out b", Weight: 1.25})
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "raw value", To: "squashed", Weight: 0.5})
+	// A dead node that can never affect an output.
+	addGene(neatGene{Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE})
+	addGene(neatGene{Type: _GENE_TYPE_CONNECTION, From: "raw value", To: strconv.FormatUint(geneId, _BASE_10), Weight: 0.5})
+
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"raw value", "scaled", "z", "suit"},
+			Outputs: []string{"out_a", "out b", "squashed"},
+			InputNormalizations: map[string]InputNormalization{
+				"scaled": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: -10.0, Max: 10.0},
+				"z":      InputNormalization{Method: NORMALIZE_Z_SCORE, Mean: 1.0, StdDev: 2.5},
+				"suit": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{
+					InputCategory{Name: "hearts", Value: 1.0},
+					InputCategory{Name: "spades", Value: 2.0},
+					InputCategory{Name: "clubs", Value: 3.0},
+				}},
+			},
+			OutputFunctions: map[string]string{"squashed": ACTIVATION_SIGMOID},
+			SoftmaxGroups:   map[string][]string{"choice": []string{"out_a", "out b"}},
+		},
+		Genome: neatGenome{Genes: genes},
+	}
+	neuralNet.InOut.validate()
+	return neuralNet
+}
+
+// Add the tests.
+
+func (s *NeatNeuralNetGoSourceSuite) Test_GenerateGoSource(c *C) {
+	var neuralNet NeatNeuralNet = goSourceTestNeuralNet()
+
+	var source []byte
+	var err error
+	source, err = GenerateGoSource(neuralNet, "champion", "Predict")
+	c.Assert(err, IsNil)
+
+	var text string = string(source)
+	c.Check(strings.HasPrefix(text, "// Code generated by go-genetic GenerateGoSource. DO NOT EDIT.\n\npackage champion\n"), Equals, true)
+	c.Check(strings.Contains(text, "type PredictInputs struct {\n\tRawValue float64 // \"raw value\"\n"), Equals, true)
+	c.Check(strings.Contains(text, "type PredictOutputs struct {\n\tOutB     float64 // \"out b\"\n"), Equals, true)
+	c.Check(strings.Contains(text, "func Predict(inputs PredictInputs) (outputs PredictOutputs) {"), Equals, true)
+	c.Check(strings.Contains(text, "func predictBipolarSigmoid(input float64) float64 {"), Equals, true)
+	c.Check(strings.Contains(text, "func predictSoftmax(inputs ...float64) []float64 {"), Equals, true)
+
+	// Dead structure and unused one-hot categories are left out.
+	c.Check(strings.Contains(text, "suit:clubs"), Equals, false)
+	c.Check(strings.Count(text, "predictSine("), Equals, 2) // Once called, once declared.
+
+	// The same neural net always generates the same source.
+	var again []byte
+	again, err = GenerateGoSource(neuralNet, "champion", "Predict")
+	c.Assert(err, IsNil)
+	c.Check(string(again), Equals, text)
+}
+
+func (s *NeatNeuralNetGoSourceSuite) Test_GenerateGoSource_Errors(c *C) {
+	var neuralNet NeatNeuralNet = goSourceTestNeuralNet()
+	var err error
+
+	_, err = GenerateGoSource(neuralNet, "not a package", "Predict")
+	c.Check(err, ErrorMatches, `invalid package name: 'not a package'`)
+	_, err = GenerateGoSource(neuralNet, "champion", "9Predict")
+	c.Check(err, ErrorMatches, `invalid function name: '9Predict'`)
+
+	// Names that end up the same in Go.
+	neuralNet.InOut.Inputs = append(neuralNet.InOut.Inputs, "raw_value")
+	_, err = GenerateGoSource(neuralNet, "champion", "Predict")
+	c.Check(err, ErrorMatches, `'raw value' and 'raw_value' are both the Go name 'RawValue'`)
+}
+
+func (s *NeatNeuralNetGoSourceSuite) Test_GoIdentifier(c *C) {
+	c.Check(goIdentifier("card_id"), Equals, "CardId")
+	c.Check(goIdentifier("suit:hearts"), Equals, "SuitHearts")
+	c.Check(goIdentifier("Value"), Equals, "Value")
+	c.Check(goIdentifier("2nd"), Equals, "X2nd")
+	c.Check(goIdentifier("!!"), Equals, "X")
+}
+
+func (s *NeatNeuralNetGoSourceSuite) Test_GoFloat(c *C) {
+	c.Check(goFloat(1.0), Equals, "1.0")
+	c.Check(goFloat(-0.25), Equals, "-0.25")
+	c.Check(goFloat(1e-20), Equals, "1e-20")
+	var tenth float64 = 0.1
+	c.Check(goFloat(tenth+0.2), Equals, "0.30000000000000004")
+}
+
+// Test_GenerateGoSource_MatchesCompute builds and runs the generated source and compares it to Compute.
+func (s *NeatNeuralNetGoSourceSuite) Test_GenerateGoSource_MatchesCompute(c *C) {
+	var goBinary string
+	var err error
+	if goBinary, err = exec.LookPath("go"); err != nil {
+		c.Skip("go is not available to build generated source")
+	}
+
+	var neuralNet NeatNeuralNet = goSourceTestNeuralNet()
+
+	var source []byte
+	source, err = GenerateGoSource(neuralNet, "main", "compute")
+	c.Assert(err, IsNil)
+
+	// A program that computes each line of json inputs and writes json outputs.
+	var harness string = `package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+func main() {
+	var inputs []computeInputs
+	if err := json.NewDecoder(os.Stdin).Decode(&inputs); err != nil {
+		panic(err)
+	}
+	var outputs []computeOutputs
+	for _, in := range inputs {
+		outputs = append(outputs, compute(in))
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(outputs); err != nil {
+		panic(err)
+	}
+}
+`
+	var dir string = c.MkDir()
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "neural_net.go"), source, 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(harness), 0644), IsNil)
+
+	// Random inputs, with one-hot inputs sometimes matching no category at all.
+	var trials []map[string]float64
+	for i := 0; i < 200; i++ {
+		var inputs map[string]float64 = randomInputs(neuralNet.InOut)
+		if i%10 == 0 {
+			inputs["suit"] = 99.0
+		}
+		trials = append(trials, inputs)
+	}
+	var generatedInputs []map[string]float64
+	for _, inputs := range trials {
+		generatedInputs = append(generatedInputs, map[string]float64{
+			"RawValue": inputs["raw value"],
+			"Scaled":   inputs["scaled"],
+			"Z":        inputs["z"],
+			"Suit":     inputs["suit"],
+		})
+	}
+	var stdin []byte
+	stdin, err = json.Marshal(generatedInputs)
+	c.Assert(err, IsNil)
+
+	// Run it as a plain Go program outside any module.
+	var command *exec.Cmd = exec.Command(goBinary, "run", "main.go", "neural_net.go")
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GOFLAGS=", "GO111MODULE=on")
+	command.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err = command.Run(); err != nil {
+		c.Fatalf("generated source failed: %s\n%s\n%s", err, stderr.String(), string(source))
+	}
+
+	var generatedOutputs []map[string]float64
+	c.Assert(json.Unmarshal(stdout.Bytes(), &generatedOutputs), IsNil)
+	c.Assert(len(generatedOutputs), Equals, len(trials))
+
+	// Every output matches.
+	var fields map[string]string = map[string]string{"out_a": "OutA", "out b": "OutB", "squashed": "Squashed"}
+	for i, inputs := range trials {
+		var outputs map[string]float64 = neuralNet.Compute(inputs)
+		for out, field := range fields {
+			c.Check(isNearlyEqual(outputs[out], generatedOutputs[i][field]), Equals, true, Commentf("output '%s' for inputs %v: %v != %v", out, inputs, outputs[out], generatedOutputs[i][field]))
+		}
+	}
+}
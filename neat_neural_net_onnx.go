@@ -0,0 +1,393 @@
+package genetic
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+const (
+	// The ONNX versions models are written for.
+	// Reference: https://github.com/onnx/onnx/blob/main/docs/Versioning.md
+	_ONNX_IR_VERSION    = 7
+	_ONNX_OPSET_VERSION = 13
+	_ONNX_PRODUCER_NAME = "go-genetic"
+	_ONNX_GRAPH_NAME    = "neat_neural_net"
+
+	// ONNX tensor element types. Values are doubles to compute exactly what Compute computes.
+	_ONNX_DATA_TYPE_DOUBLE = 11
+
+	// ONNX attribute types.
+	_ONNX_ATTRIBUTE_INT = 2
+
+	// Field numbers of the ONNX protobuf messages.
+	// Reference: https://github.com/onnx/onnx/blob/main/onnx/onnx.proto
+	_ONNX_MODEL_IR_VERSION        = 1
+	_ONNX_MODEL_PRODUCER_NAME     = 2
+	_ONNX_MODEL_GRAPH             = 7
+	_ONNX_MODEL_OPSET_IMPORT      = 8
+	_ONNX_OPSET_DOMAIN            = 1
+	_ONNX_OPSET_VERSION_FIELD     = 2
+	_ONNX_GRAPH_NODE              = 1
+	_ONNX_GRAPH_NAME_FIELD        = 2
+	_ONNX_GRAPH_INITIALIZER       = 5
+	_ONNX_GRAPH_INPUT             = 11
+	_ONNX_GRAPH_OUTPUT            = 12
+	_ONNX_NODE_INPUT              = 1
+	_ONNX_NODE_OUTPUT             = 2
+	_ONNX_NODE_NAME               = 3
+	_ONNX_NODE_OP_TYPE            = 4
+	_ONNX_NODE_ATTRIBUTE          = 5
+	_ONNX_ATTRIBUTE_NAME          = 1
+	_ONNX_ATTRIBUTE_I             = 3
+	_ONNX_ATTRIBUTE_TYPE          = 20
+	_ONNX_TENSOR_DIMS             = 1
+	_ONNX_TENSOR_DATA_TYPE        = 2
+	_ONNX_TENSOR_NAME             = 8
+	_ONNX_TENSOR_DOUBLE_DATA      = 10
+	_ONNX_VALUE_INFO_NAME         = 1
+	_ONNX_VALUE_INFO_TYPE         = 2
+	_ONNX_TYPE_TENSOR_TYPE        = 1
+	_ONNX_TENSOR_TYPE_ELEM_TYPE   = 1
+	_ONNX_TENSOR_TYPE_SHAPE       = 2
+	_ONNX_SHAPE_DIM               = 1
+	_ONNX_SHAPE_DIMENSION_VALUE   = 1
+	_ONNX_TENSOR_VALUE_DIMENSIONS = 1 // Every value in the graph is a tensor holding a single value.
+)
+
+// onnxNode is a single operation in an ONNX graph.
+type onnxNode struct {
+	name       string
+	opType     string
+	inputs     []string
+	outputs    []string
+	attributes map[string]int64 // Only integer attributes are ever needed.
+}
+
+// onnxGraph builds an ONNX graph one operation at a time. Every value is a tensor holding a single double, and every
+// operation is added after the operations it depends on.
+type onnxGraph struct {
+	inputs       []string           // The graph inputs.
+	outputs      []string           // The graph outputs.
+	nodes        []onnxNode         // The operations, in order.
+	initializers []string           // The constants, in order.
+	constants    map[string]float64 // The value of each constant.
+	constantOf   map[float64]string // Each constant value is only stored once.
+	usedNames    map[string]bool    // Every value name must be unique.
+	nextName     int                // For naming intermediate values.
+}
+
+// newOnnxGraph creates an empty graph with the given inputs and outputs. The input and output names are reserved.
+func newOnnxGraph(inputs []string, outputs []string) (graph onnxGraph) {
+	graph = onnxGraph{
+		inputs:     inputs,
+		outputs:    outputs,
+		constants:  map[string]float64{},
+		constantOf: map[float64]string{},
+		usedNames:  map[string]bool{},
+	}
+	for _, name := range inputs {
+		graph.usedNames[name] = true
+	}
+	for _, name := range outputs {
+		graph.usedNames[name] = true
+	}
+	return graph
+}
+
+// newName creates a unique name for an intermediate value.
+func (g *onnxGraph) newName(prefix string) (name string) {
+	for {
+		name = prefix + strconv.Itoa(g.nextName)
+		g.nextName++
+		if !g.usedNames[name] {
+			g.usedNames[name] = true
+			return name
+		}
+	}
+}
+
+// constant is the name of a constant value in the graph.
+func (g *onnxGraph) constant(value float64) (name string) {
+	var ok bool
+	if name, ok = g.constantOf[value]; ok {
+		return name
+	}
+	name = g.newName("const_")
+	g.constantOf[value] = name
+	g.constants[name] = value
+	g.initializers = append(g.initializers, name)
+	return name
+}
+
+// add adds an operation and returns the name of the value it produces.
+func (g *onnxGraph) add(opType string, inputs ...string) (output string) {
+	output = g.newName("value_")
+	g.addNamed(opType, output, nil, inputs...)
+	return output
+}
+
+// addNamed adds an operation producing a named value.
+func (g *onnxGraph) addNamed(opType string, output string, attributes map[string]int64, inputs ...string) {
+	g.nodes = append(g.nodes, onnxNode{
+		name:       "node_" + strconv.Itoa(len(g.nodes)),
+		opType:     opType,
+		inputs:     inputs,
+		outputs:    []string{output},
+		attributes: attributes,
+	})
+}
+
+// floorMod2 is x mod 2 for a whole number x, always 0.0 or 1.0 even for negative numbers.
+func (g *onnxGraph) floorMod2(x string) string {
+	var half string = g.add("Floor", g.add("Mul", x, g.constant(0.5)))
+	return g.add("Sub", x, g.add("Mul", half, g.constant(2.0)))
+}
+
+// activate adds the operations for an activation function. Where ONNX has no operation for the function, it is composed
+// from simpler operations the same way activation_function.go computes it.
+func (g *onnxGraph) activate(function string, x string) (output string, err error) {
+	switch function {
+	case ACTIVATION_SIGMOID:
+		return g.add("Sigmoid", x), nil
+	case ACTIVATION_BIPOLAR_SIGMOID:
+		// (1 - e^-x) / (1 + e^-x) is tanh(x/2).
+		return g.add("Tanh", g.add("Mul", x, g.constant(0.5))), nil
+	case ACTIVATION_GAUSSIAN:
+		return g.add("Exp", g.add("Neg", g.add("Mul", x, x))), nil
+	case ACTIVATION_INVERSE:
+		return g.add("Neg", x), nil
+	case ACTIVATION_SINE:
+		return g.add("Sin", x), nil
+	case ACTIVATION_COSINE:
+		return g.add("Cos", x), nil
+	case ACTIVATION_TANGENT:
+		return g.add("Tan", x), nil
+	case ACTIVATION_HYPERBOLIC_TANGENT:
+		return g.add("Tanh", x), nil
+	case ACTIVATION_RAMP:
+		// 1 - 2 * (x - floor(x))
+		var fraction string = g.add("Sub", x, g.add("Floor", x))
+		return g.add("Sub", g.constant(1.0), g.add("Mul", fraction, g.constant(2.0))), nil
+	case ACTIVATION_STEP:
+		// 1 for even floors, -1 for odd floors: 1 - 2 * (floor(x) mod 2)
+		var odd string = g.floorMod2(g.add("Floor", x))
+		return g.add("Sub", g.constant(1.0), g.add("Mul", odd, g.constant(2.0))), nil
+	case ACTIVATION_SPIKE:
+		// The ramp for even floors, the ramp flipped for odd floors: (1 - 2 * (|floor(x)| mod 2)) * (1 - 2 * (x - floor(x)))
+		var floor string = g.add("Floor", x)
+		var odd string = g.floorMod2(g.add("Abs", floor))
+		var sign string = g.add("Sub", g.constant(1.0), g.add("Mul", odd, g.constant(2.0)))
+		var ramp string = g.add("Sub", g.constant(1.0), g.add("Mul", g.add("Sub", x, floor), g.constant(2.0)))
+		return g.add("Mul", sign, ramp), nil
+	}
+	return "", fmt.Errorf("activation function '%s' has no ONNX equivalent", function)
+}
+
+// WriteOnnx writes the neural net as an ONNX model, so it can be served by an ONNX runtime.
+// Reference: https://onnx.ai/onnx/intro/concepts.html
+//
+// Each input and output is its own graph input or output, a tensor of one double, named after the input or output.
+// Connections become weighted sums, and input normalization, output functions, and softmax groups are all included.
+// Only structure that can affect an output is written. Anything that cannot be written gives an error instead.
+func (c *NeatNeuralNet) WriteOnnx(writer io.Writer) (err error) {
+
+	// The neural net must be feed-forward.
+	var topology computeTopology
+	var ok bool
+	if topology, ok = makeComputeTopology(c.InOut, c.Genome.Genes); !ok {
+		return fmt.Errorf("neural net has a circular dependency in Genome: %+v", c.Genome.Genes)
+	}
+
+	var graph onnxGraph = newOnnxGraph(c.InOut.Inputs, c.InOut.Outputs)
+
+	// Where does each one-hot category node come from?
+	var oneHotCategories map[string]InputCategory = map[string]InputCategory{}
+	var oneHotInputs map[string]string = map[string]string{}
+	for in, normalization := range c.InOut.InputNormalizations {
+		for _, category := range normalization.Categories {
+			oneHotCategories[oneHotInput(in, category.Name)] = category
+			oneHotInputs[oneHotInput(in, category.Name)] = in
+		}
+	}
+
+	// Add every node that can affect an output, in order.
+	var orderedNodes []string
+	var sources map[string][]neatGene
+	orderedNodes, sources = makeLiveComputeOrder(c.InOut, c.Genome.Genes)
+	var values map[string]string = map[string]string{}
+	for _, nodeId := range orderedNodes {
+
+		// The bias.
+		if nodeId == NODE_BIAS {
+			values[nodeId] = graph.constant(1.0)
+			continue
+		}
+
+		// A one-hot category of an input is 1.0 when the input is the category's value, otherwise 0.0.
+		var category InputCategory
+		if category, ok = oneHotCategories[nodeId]; ok {
+			var isCategory string = graph.add("Equal", oneHotInputs[nodeId], graph.constant(category.Value))
+			values[nodeId] = graph.newName("value_")
+			graph.addNamed("Cast", values[nodeId], map[string]int64{"to": _ONNX_DATA_TYPE_DOUBLE}, isCategory)
+			continue
+		}
+
+		// An input, normalized if the in/out asks for it.
+		if inStrings(c.InOut.Inputs, nodeId) {
+			var normalization InputNormalization = c.InOut.InputNormalizations[nodeId]
+			switch normalization.Method {
+			case "":
+				values[nodeId] = nodeId
+			case NORMALIZE_MIN_MAX:
+				values[nodeId] = graph.add("Div", graph.add("Sub", nodeId, graph.constant(normalization.Min)), graph.constant(normalization.Max-normalization.Min))
+			case NORMALIZE_Z_SCORE:
+				values[nodeId] = graph.add("Div", graph.add("Sub", nodeId, graph.constant(normalization.Mean)), graph.constant(normalization.StdDev))
+			default:
+				return fmt.Errorf("input '%s' has a normalization method with no ONNX equivalent: '%s'", nodeId, normalization.Method)
+			}
+			continue
+		}
+
+		// A hidden node or output, the weighted sum of the nodes feeding it.
+		var terms []string
+		for _, gene := range sources[nodeId] {
+			terms = append(terms, graph.add("Mul", values[gene.From], graph.constant(gene.Weight)))
+		}
+		var value string
+		switch len(terms) {
+		case 0:
+			return fmt.Errorf("node '%s' has nothing feeding it", nodeId)
+		case 1:
+			value = terms[0]
+		default:
+			value = graph.add("Sum", terms...)
+		}
+
+		// Run the function, if the node has one.
+		var function string = topology.nodes[nodeId].function
+		if function != "" {
+			if value, err = graph.activate(function, value); err != nil {
+				return fmt.Errorf("node '%s': %s", nodeId, err)
+			}
+		}
+		values[nodeId] = value
+	}
+
+	// Squash each softmax group, in name order so the model is always the same. Composed from simpler operations the same way
+	// softmax() computes it, since the ONNX operation works on a single tensor rather than separate outputs.
+	var groupNames []string
+	for group := range c.InOut.SoftmaxGroups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+	for _, group := range groupNames {
+		var outs []string = c.InOut.SoftmaxGroups[group]
+		var groupValues []string
+		for _, out := range outs {
+			groupValues = append(groupValues, values[out])
+		}
+		var max string = graph.add("Max", groupValues...)
+		var exps []string
+		for _, value := range groupValues {
+			exps = append(exps, graph.add("Exp", graph.add("Sub", value, max)))
+		}
+		var total string = graph.add("Sum", exps...)
+		for i, out := range outs {
+			values[out] = graph.add("Div", exps[i], total)
+		}
+	}
+
+	// Name the outputs.
+	for _, out := range c.InOut.Outputs {
+		graph.addNamed("Identity", out, nil, values[out])
+	}
+
+	_, err = writer.Write(graph.encodeModel())
+	return err
+}
+
+// encodeModel writes the graph as an ONNX model protobuf.
+func (g *onnxGraph) encodeModel() []byte {
+	var model protobufWriter
+	model.writeInt(_ONNX_MODEL_IR_VERSION, _ONNX_IR_VERSION)
+	model.writeString(_ONNX_MODEL_PRODUCER_NAME, _ONNX_PRODUCER_NAME)
+	var graph protobufWriter = g.encodeGraph()
+	model.writeMessage(_ONNX_MODEL_GRAPH, &graph)
+	var opset protobufWriter
+	opset.writeString(_ONNX_OPSET_DOMAIN, "")
+	opset.writeInt(_ONNX_OPSET_VERSION_FIELD, _ONNX_OPSET_VERSION)
+	model.writeMessage(_ONNX_MODEL_OPSET_IMPORT, &opset)
+	return model.bytes()
+}
+
+// encodeGraph writes the graph as an ONNX graph protobuf.
+func (g *onnxGraph) encodeGraph() (graph protobufWriter) {
+	for _, node := range g.nodes {
+		var encoded protobufWriter = node.encode()
+		graph.writeMessage(_ONNX_GRAPH_NODE, &encoded)
+	}
+	graph.writeString(_ONNX_GRAPH_NAME_FIELD, _ONNX_GRAPH_NAME)
+	for _, name := range g.initializers {
+		var tensor protobufWriter
+		tensor.writePackedInts(_ONNX_TENSOR_DIMS, []int64{_ONNX_TENSOR_VALUE_DIMENSIONS})
+		tensor.writeInt(_ONNX_TENSOR_DATA_TYPE, _ONNX_DATA_TYPE_DOUBLE)
+		tensor.writeString(_ONNX_TENSOR_NAME, name)
+		tensor.writePackedDoubles(_ONNX_TENSOR_DOUBLE_DATA, []float64{g.constants[name]})
+		graph.writeMessage(_ONNX_GRAPH_INITIALIZER, &tensor)
+	}
+	for _, name := range g.inputs {
+		var valueInfo protobufWriter = encodeOnnxValueInfo(name)
+		graph.writeMessage(_ONNX_GRAPH_INPUT, &valueInfo)
+	}
+	for _, name := range g.outputs {
+		var valueInfo protobufWriter = encodeOnnxValueInfo(name)
+		graph.writeMessage(_ONNX_GRAPH_OUTPUT, &valueInfo)
+	}
+	return graph
+}
+
+// encode writes the operation as an ONNX node protobuf.
+func (n *onnxNode) encode() (node protobufWriter) {
+	for _, input := range n.inputs {
+		node.writeString(_ONNX_NODE_INPUT, input)
+	}
+	for _, output := range n.outputs {
+		node.writeString(_ONNX_NODE_OUTPUT, output)
+	}
+	node.writeString(_ONNX_NODE_NAME, n.name)
+	node.writeString(_ONNX_NODE_OP_TYPE, n.opType)
+
+	// Attributes in name order so the model is always the same.
+	var names []string
+	for name := range n.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var attribute protobufWriter
+		attribute.writeString(_ONNX_ATTRIBUTE_NAME, name)
+		attribute.writeInt(_ONNX_ATTRIBUTE_I, n.attributes[name])
+		attribute.writeInt(_ONNX_ATTRIBUTE_TYPE, _ONNX_ATTRIBUTE_INT)
+		node.writeMessage(_ONNX_NODE_ATTRIBUTE, &attribute)
+	}
+	return node
+}
+
+// encodeOnnxValueInfo writes a graph input or output, a tensor of one double, as an ONNX value info protobuf.
+func encodeOnnxValueInfo(name string) (valueInfo protobufWriter) {
+	var dimension protobufWriter
+	dimension.writeInt(_ONNX_SHAPE_DIMENSION_VALUE, _ONNX_TENSOR_VALUE_DIMENSIONS)
+	var shape protobufWriter
+	shape.writeMessage(_ONNX_SHAPE_DIM, &dimension)
+	var tensorType protobufWriter
+	tensorType.writeInt(_ONNX_TENSOR_TYPE_ELEM_TYPE, _ONNX_DATA_TYPE_DOUBLE)
+	tensorType.writeMessage(_ONNX_TENSOR_TYPE_SHAPE, &shape)
+	var typeProto protobufWriter
+	typeProto.writeMessage(_ONNX_TYPE_TENSOR_TYPE, &tensorType)
+
+	valueInfo.writeString(_ONNX_VALUE_INFO_NAME, name)
+	valueInfo.writeMessage(_ONNX_VALUE_INFO_TYPE, &typeProto)
+	return valueInfo
+}
@@ -0,0 +1,115 @@
+package genetic
+
+import (
+	"bytes"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type NeatNeuralNetOnnxSuite struct{}
+
+var _ = Suite(&NeatNeuralNetOnnxSuite{})
+
+// Add the tests.
+
+func (s *NeatNeuralNetOnnxSuite) Test_NeatNeuralNet_WriteOnnx(c *C) {
+	var neuralNet NeatNeuralNet = goSourceTestNeuralNet()
+
+	var onnx bytes.Buffer
+	c.Assert(neuralNet.WriteOnnx(&onnx), IsNil)
+
+	// Read the model back.
+	var model onnxTestModel
+	var err error
+	model, err = readOnnxTestModel(onnx.Bytes())
+	c.Assert(err, IsNil)
+	c.Check(model.irVersion, Equals, uint64(_ONNX_IR_VERSION))
+	c.Check(model.producerName, Equals, _ONNX_PRODUCER_NAME)
+	c.Check(model.opsetDomain, Equals, "")
+	c.Check(model.opsetVersion, Equals, uint64(_ONNX_OPSET_VERSION))
+	c.Check(model.graphName, Equals, _ONNX_GRAPH_NAME)
+	c.Check(model.inputs, DeepEquals, neuralNet.InOut.Inputs)
+	c.Check(model.outputs, DeepEquals, neuralNet.InOut.Outputs)
+
+	// The same neural net always writes the same model.
+	var again bytes.Buffer
+	c.Assert(neuralNet.WriteOnnx(&again), IsNil)
+	c.Check(again.Bytes(), DeepEquals, onnx.Bytes())
+
+	// The model computes what the neural net computes, with one-hot inputs sometimes matching no category at all.
+	for i := 0; i < 200; i++ {
+		var inputs map[string]float64 = randomInputs(neuralNet.InOut)
+		if i%10 == 0 {
+			inputs["suit"] = 99.0
+		}
+		var outputs map[string]float64 = neuralNet.Compute(inputs)
+		var onnxOutputs map[string]float64
+		onnxOutputs, err = model.run(inputs)
+		c.Assert(err, IsNil)
+		for _, out := range neuralNet.InOut.Outputs {
+			c.Check(isNearlyEqual(outputs[out], onnxOutputs[out]), Equals, true, Commentf("output '%s' for inputs %v: %v != %v", out, inputs, outputs[out], onnxOutputs[out]))
+		}
+	}
+}
+
+func (s *NeatNeuralNetOnnxSuite) Test_OnnxGraph_Activate(c *C) {
+	// Every activation function matches activation_function.go, including around the edges of each step.
+	var inputs []float64 = []float64{-150.0, -3.5, -3.0, -2.5, -1.0, -0.75, -0.5, 0.0, 0.25, 0.5, 1.0, 1.5, 2.0, 2.75, 7.0, 150.0}
+	for _, function := range []string{
+		ACTIVATION_SIGMOID, ACTIVATION_BIPOLAR_SIGMOID, ACTIVATION_GAUSSIAN, ACTIVATION_INVERSE, ACTIVATION_SINE, ACTIVATION_COSINE,
+		ACTIVATION_TANGENT, ACTIVATION_HYPERBOLIC_TANGENT, ACTIVATION_RAMP, ACTIVATION_STEP, ACTIVATION_SPIKE,
+	} {
+		var graph onnxGraph = newOnnxGraph([]string{"x"}, []string{"y"})
+		var value string
+		var err error
+		value, err = graph.activate(function, "x")
+		c.Assert(err, IsNil)
+		graph.addNamed("Identity", "y", nil, value)
+
+		var model onnxTestModel
+		model, err = readOnnxTestModel(graph.encodeModel())
+		c.Assert(err, IsNil)
+		for _, input := range inputs {
+			var outputs map[string]float64
+			outputs, err = model.run(map[string]float64{"x": input})
+			c.Assert(err, IsNil)
+			c.Check(isNearlyEqual(outputs["y"], activate(function, input)), Equals, true, Commentf("%s(%v): %v != %v", function, input, outputs["y"], activate(function, input)))
+		}
+	}
+
+	// Unknown functions have no equivalent.
+	var graph onnxGraph = newOnnxGraph([]string{"x"}, []string{"y"})
+	var err error
+	_, err = graph.activate("unknown", "x")
+	c.Check(err, ErrorMatches, `activation function 'unknown' has no ONNX equivalent`)
+}
+
+func (s *NeatNeuralNetOnnxSuite) Test_OnnxGraph_Names(c *C) {
+	// Generated names never take an input or output name, and constants are only stored once.
+	var graph onnxGraph = newOnnxGraph([]string{"value_0"}, []string{"const_2"})
+	c.Check(graph.add("Neg", "value_0"), Equals, "value_1")
+	c.Check(graph.constant(1.5), Equals, "const_3")
+	c.Check(graph.constant(1.5), Equals, "const_3")
+	c.Check(graph.initializers, DeepEquals, []string{"const_3"})
+}
+
+func (s *NeatNeuralNetOnnxSuite) Test_NeatNeuralNet_WriteOnnx_Errors(c *C) {
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1"},
+			Outputs: []string{"o1"},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: "unknown"},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "1", Weight: 0.5},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: "o1", Weight: 0.5},
+		}},
+	}
+	var onnx bytes.Buffer
+	c.Check(neuralNet.WriteOnnx(&onnx), ErrorMatches, `node '1': activation function 'unknown' has no ONNX equivalent`)
+
+	// Circular neural nets are not feed-forward.
+	neuralNet.Genome.Genes[0].Function = ACTIVATION_SINE
+	neuralNet.Genome.Genes = append(neuralNet.Genome.Genes, neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "1", To: "1", Weight: 0.5})
+	c.Check(neuralNet.WriteOnnx(&onnx), ErrorMatches, `neural net has a circular dependency.*`)
+}
@@ -0,0 +1,138 @@
+package genetic
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+const (
+	// Random inputs for verifying two neural nets compute the same thing are between -range and +range.
+	_VERIFY_INPUT_RANGE = 10.0
+
+	// Two neural nets may add up the same values in a different order, so allow for rounding differences.
+	_VERIFY_TOLERANCE = 0.000000001
+)
+
+// SimplifyReport describes what was removed from a neural net when it was simplified.
+type SimplifyReport struct {
+	DisabledGenes   []uint64 // Genes that were disabled, only kept to compare ancestry during evolution.
+	DeadNodes       []string // Hidden nodes with no path to any output.
+	DeadConnections []uint64 // Enabled connections into dead nodes.
+}
+
+// String summarizes the report.
+func (r SimplifyReport) String() string {
+	return fmt.Sprintf("disabled genes: %d, dead nodes: %d, dead connections: %d", len(r.DisabledGenes), len(r.DeadNodes), len(r.DeadConnections))
+}
+
+// Simplify returns a neural net that computes exactly what this neural net computes, but with everything that cannot affect
+// an output removed: disabled genes, hidden nodes with no path to an output, and the connections feeding those nodes.
+// The remaining genes keep their gene ids. Evolved champions are much smaller and easier to read simplified, but a simplified
+// neural net has lost the ancestry it would need to keep evolving.
+func (c *NeatNeuralNet) Simplify() (simplified NeatNeuralNet, report SimplifyReport) {
+
+	// What nodes feed each node?
+	var sources map[string][]string = map[string][]string{}
+	for _, gene := range c.Genome.Genes {
+		if gene.IsEnabled == true && gene.Type == _GENE_TYPE_CONNECTION {
+			sources[gene.To] = append(sources[gene.To], gene.From)
+		}
+	}
+
+	// Walk backwards from the outputs. Every node reached has a path to an output.
+	var isLive map[string]bool = map[string]bool{}
+	var toVisit []string
+	toVisit = append(toVisit, c.InOut.Outputs...)
+	for len(toVisit) > 0 {
+		var nodeId string = toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if isLive[nodeId] {
+			continue
+		}
+		isLive[nodeId] = true
+		toVisit = append(toVisit, sources[nodeId]...)
+	}
+
+	// Keep only the genes that can affect an output.
+	simplified = NeatNeuralNet{
+		InOut:  c.InOut, // in/out is fixed for an experiment so not a problem if it gets cross referenced in anyway.
+		Genome: neatGenome{},
+	}
+	for _, gene := range c.Genome.Genes {
+
+		// Disabled genes never affect anything.
+		if !gene.IsEnabled {
+			report.DisabledGenes = append(report.DisabledGenes, gene.GeneId)
+			continue
+		}
+
+		switch gene.Type {
+		case _GENE_TYPE_NODE:
+			var nodeId string = strconv.FormatUint(gene.GeneId, _BASE_10)
+			if !isLive[nodeId] {
+				report.DeadNodes = append(report.DeadNodes, nodeId)
+				continue
+			}
+		case _GENE_TYPE_CONNECTION:
+			// If a connection feeds a live node, the node it comes from is live too.
+			if !isLive[gene.To] {
+				report.DeadConnections = append(report.DeadConnections, gene.GeneId)
+				continue
+			}
+		}
+
+		simplified.Genome.Genes = append(simplified.Genome.Genes, gene)
+	}
+
+	return simplified, report
+}
+
+// VerifyFunctionallyIdentical computes both neural nets on the same random inputs for the given number of trials and returns
+// an error describing the first output that differs. Both neural nets must share the same inputs and outputs.
+func VerifyFunctionallyIdentical(neuralNetA NeatNeuralNet, neuralNetB NeatNeuralNet, trials int) (err error) {
+	for i := 0; i < trials; i++ {
+
+		// Run both neural nets on the same inputs.
+		var inputs map[string]float64 = randomInputs(neuralNetA.InOut)
+		var outputsA map[string]float64 = neuralNetA.Compute(inputs)
+		var outputsB map[string]float64 = neuralNetB.Compute(inputs)
+
+		// Every output must match.
+		for _, out := range neuralNetA.InOut.Outputs {
+			if !isNearlyEqual(outputsA[out], outputsB[out]) {
+				return fmt.Errorf("output '%s' differs (%v != %v) for inputs: %v", out, outputsA[out], outputsB[out], inputs)
+			}
+		}
+	}
+	return nil
+}
+
+// randomInputs creates a random value for every input of a neural net. One-hot inputs are given one of their categories,
+// since any other value switches every category off.
+func randomInputs(inOut NeuralNetInOut) (inputs map[string]float64) {
+	inputs = map[string]float64{}
+	for _, in := range inOut.Inputs {
+		var normalization InputNormalization = inOut.InputNormalizations[in]
+		if normalization.Method == NORMALIZE_ONE_HOT {
+			inputs[in] = normalization.Categories[rand.Intn(len(normalization.Categories))].Value
+		} else {
+			inputs[in] = (rand.Float64()*2.0 - 1.0) * _VERIFY_INPUT_RANGE
+		}
+	}
+	return inputs
+}
+
+// isNearlyEqual compares two computed values, allowing for rounding differences relative to their size.
+func isNearlyEqual(a float64, b float64) bool {
+	// Values that blew up (e.g. a tangent) must blow up the same way.
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	var scale float64 = math.Max(1.0, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= _VERIFY_TOLERANCE*scale
+}
@@ -0,0 +1,97 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math"
+)
+
+// Create a suite.
+type NeatNeuralNetSimplifySuite struct{}
+
+var _ = Suite(&NeatNeuralNetSimplifySuite{})
+
+// Add the tests.
+
+func (s *NeatNeuralNetSimplifySuite) Test_NeatNeuralNet_Simplify(c *C) {
+
+	// A neural net with some structure that can never affect an output.
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1", "i2"},
+			Outputs: []string{"o1", "o2"},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: false, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.1}, // Disabled.
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "2", Weight: 0.2},
+			neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "o1", Weight: 0.3},
+			neatGene{GeneId: 5, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_COSINE}, // Dead, goes nowhere.
+			neatGene{GeneId: 6, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "5", Weight: 0.4},
+			neatGene{GeneId: 7, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_GAUSSIAN}, // Dead, only feeds a dead node.
+			neatGene{GeneId: 8, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "b", To: "7", Weight: 0.5},
+			neatGene{GeneId: 9, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "7", To: "5", Weight: 0.6},
+			neatGene{GeneId: 10, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o2", Weight: 0.7},
+			neatGene{GeneId: 11, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "o2", Weight: 0.8},
+		}},
+	}
+
+	// Simplify.
+	var simplified NeatNeuralNet
+	var report SimplifyReport
+	simplified, report = neuralNet.Simplify()
+
+	// Only the live structure remains.
+	c.Check(simplified.InOut, DeepEquals, neuralNet.InOut)
+	c.Check(simplified.Genome, DeepEquals, neatGenome{Genes: []neatGene{
+		neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_NODE, Function: ACTIVATION_SINE},
+		neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "2", Weight: 0.2},
+		neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "o1", Weight: 0.3},
+		neatGene{GeneId: 10, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o2", Weight: 0.7},
+		neatGene{GeneId: 11, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "2", To: "o2", Weight: 0.8},
+	}})
+
+	// The report says what was removed.
+	c.Check(report, DeepEquals, SimplifyReport{
+		DisabledGenes:   []uint64{1},
+		DeadNodes:       []string{"5", "7"},
+		DeadConnections: []uint64{6, 8, 9},
+	})
+	c.Check(report.String(), Equals, "disabled genes: 1, dead nodes: 2, dead connections: 3")
+
+	// The original is untouched.
+	c.Check(len(neuralNet.Genome.Genes), Equals, 11)
+
+	// They compute the same thing.
+	c.Check(VerifyFunctionallyIdentical(neuralNet, simplified, 100), IsNil)
+}
+
+func (s *NeatNeuralNetSimplifySuite) Test_VerifyFunctionallyIdentical(c *C) {
+
+	// Two neural nets that differ by a weight.
+	var neuralNetA NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1"},
+			Outputs: []string{"o1"},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.1},
+		}},
+	}
+	var neuralNetB NeatNeuralNet = neuralNetA.makeClone()
+	neuralNetB.Genome.Genes[0].Weight = 0.2
+
+	// Identical neural nets verify, different ones do not.
+	c.Check(VerifyFunctionallyIdentical(neuralNetA, neuralNetA.makeClone(), 10), IsNil)
+	c.Check(VerifyFunctionallyIdentical(neuralNetA, neuralNetB, 10), ErrorMatches, `output 'o1' differs .*`)
+}
+
+func (s *NeatNeuralNetSimplifySuite) Test_IsNearlyEqual(c *C) {
+	c.Check(isNearlyEqual(1.0, 1.0), Equals, true)
+	c.Check(isNearlyEqual(1.0, 1.0+0.0000000000001), Equals, true)
+	c.Check(isNearlyEqual(1000000000.0, 1000000000.0+0.0001), Equals, true) // Relative to size.
+	c.Check(isNearlyEqual(1.0, 1.001), Equals, false)
+	c.Check(isNearlyEqual(math.NaN(), math.NaN()), Equals, true)
+	c.Check(isNearlyEqual(math.NaN(), 1.0), Equals, false)
+	c.Check(isNearlyEqual(math.Inf(1), math.Inf(1)), Equals, true)
+	c.Check(isNearlyEqual(math.Inf(1), math.Inf(-1)), Equals, false)
+}
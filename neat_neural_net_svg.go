@@ -0,0 +1,114 @@
+package genetic
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// The spacing of a neural net drawn as an SVG image.
+	_SVG_NODE_RADIUS    = 24.0  // The size of each node circle.
+	_SVG_LAYER_SPACING  = 160.0 // The distance between layers, left to right.
+	_SVG_NODE_SPACING   = 70.0  // The distance between nodes in a layer, top to bottom.
+	_SVG_MARGIN         = 40.0  // The empty space around the drawing.
+	_SVG_FONT_SIZE      = 10.0  // The size of node label text.
+	_SVG_LINE_HEIGHT    = 12.0  // The distance between lines of node label text.
+	_SVG_INPUT_FILL     = "#e0e0e0"
+	_SVG_HIDDEN_FILL    = "#ffffff"
+	_SVG_OUTPUT_FILL    = "#fff0c0"
+	_SVG_NODE_STROKE    = "#000000"
+	_SVG_DISABLED_DASH  = "6,4"
+	_SVG_EDGE_OPACITY   = 0.7
+	_SVG_DRAWING_HEADER = `<?xml version="1.0" encoding="UTF-8"?>`
+)
+
+// svgPoint is the center of a node in the drawing.
+type svgPoint struct {
+	x float64
+	y float64
+}
+
+// WriteSvg writes the neural net as an SVG image. It lays out the neural net the same way WriteDot does, but needs no
+// Graphviz install to see it. Each layer is a column, centered top to bottom.
+func (c *NeatNeuralNet) WriteSvg(writer io.Writer, options DrawOptions) (err error) {
+	var layout neuralNetLayout
+	if layout, err = newNeuralNetLayout(*c, options); err != nil {
+		return err
+	}
+
+	// How tall is the tallest layer?
+	var maxLayerSize int
+	for _, layer := range layout.layers {
+		if len(layer) > maxLayerSize {
+			maxLayerSize = len(layer)
+		}
+	}
+	var width float64 = 2.0*_SVG_MARGIN + float64(len(layout.layers)-1)*_SVG_LAYER_SPACING
+	var height float64 = 2.0*_SVG_MARGIN + float64(maxLayerSize-1)*_SVG_NODE_SPACING
+
+	// Place every node, each layer centered top to bottom.
+	var points map[string]svgPoint = map[string]svgPoint{}
+	for i, layer := range layout.layers {
+		var layerHeight float64 = float64(len(layer)-1) * _SVG_NODE_SPACING
+		var top float64 = (height - layerHeight) / 2.0
+		for j, nodeId := range layer {
+			points[nodeId] = svgPoint{
+				x: _SVG_MARGIN + float64(i)*_SVG_LAYER_SPACING,
+				y: top + float64(j)*_SVG_NODE_SPACING,
+			}
+		}
+	}
+
+	var svg bytes.Buffer
+	fmt.Fprintln(&svg, _SVG_DRAWING_HEADER)
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n", width, height, width, height)
+
+	// Draw the connections first so the nodes sit on top of them.
+	for _, gene := range layout.connections {
+		var from svgPoint = points[gene.From]
+		var to svgPoint = points[gene.To]
+		var dash string
+		if !gene.IsEnabled {
+			dash = fmt.Sprintf(" stroke-dasharray=\"%s\"", _SVG_DISABLED_DASH)
+		}
+		fmt.Fprintf(&svg, "\t<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%.2f\" stroke-opacity=\"%.1f\"%s><title>%s</title></line>\n",
+			from.x, from.y, to.x, to.y, edgeColor(gene), layout.edgeWidth(gene.Weight), _SVG_EDGE_OPACITY, dash, svgEscape(fmt.Sprintf("%s -> %s: %.3f", gene.From, gene.To, gene.Weight)))
+	}
+
+	// Draw the nodes, shaded by the kind of node.
+	var networkInputs []string = c.InOut.networkInputs()
+	for _, layer := range layout.layers {
+		for _, nodeId := range layer {
+			var point svgPoint = points[nodeId]
+			var fill string = _SVG_HIDDEN_FILL
+			if nodeId == NODE_BIAS || inStrings(networkInputs, nodeId) {
+				fill = _SVG_INPUT_FILL
+			} else if inStrings(c.InOut.Outputs, nodeId) {
+				fill = _SVG_OUTPUT_FILL
+			}
+			fmt.Fprintf(&svg, "\t<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\" stroke=\"%s\"/>\n", point.x, point.y, _SVG_NODE_RADIUS, fill, _SVG_NODE_STROKE)
+
+			// Each line of the label is centered on the node.
+			var lines []string = strings.Split(layout.labels[nodeId], "\n")
+			var firstLineY float64 = point.y - float64(len(lines)-1)*_SVG_LINE_HEIGHT/2.0 + _SVG_FONT_SIZE/3.0
+			for k, line := range lines {
+				fmt.Fprintf(&svg, "\t<text x=\"%.1f\" y=\"%.1f\" font-family=\"sans-serif\" font-size=\"%.0f\" text-anchor=\"middle\">%s</text>\n", point.x, firstLineY+float64(k)*_SVG_LINE_HEIGHT, _SVG_FONT_SIZE, svgEscape(line))
+			}
+		}
+	}
+
+	fmt.Fprintln(&svg, "</svg>")
+
+	_, err = writer.Write(svg.Bytes())
+	return err
+}
+
+// svgEscape makes text safe to put inside an SVG element.
+func svgEscape(text string) string {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
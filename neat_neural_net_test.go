@@ -2,6 +2,7 @@ package genetic
 
 import (
 	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math"
 	"math/rand"
 	"time"
 )
@@ -564,6 +565,77 @@ func (s *NeatNeuralNetSuite) Test_NeatNeuralNet_Compute(c *C) {
 	c.Check(func() { neuralNet.Compute(map[string]float64{"i1": 10.0, "i2": 100.0, "i3": 100.0}) }, Panics, `Unknown input: 'i3'`)
 }
 
+func (s *NeatNeuralNetSuite) Test_NeatNeuralNet_Compute_OutputFunctions(c *C) {
+
+	// Make a new neural net (avoiding randomness).
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:          []string{"i1", "i2"},
+			Outputs:         []string{"o1", "o2", "o3", "o4"},
+			OutputFunctions: map[string]string{"o1": ACTIVATION_HYPERBOLIC_TANGENT, "o3": ACTIVATION_INVERSE},
+			SoftmaxGroups:   map[string][]string{"choice": []string{"o3", "o4"}},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.1},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2", To: "o2", Weight: 0.5},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "b", To: "o3", Weight: 1.0},
+			neatGene{GeneId: 4, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "b", To: "o4", Weight: 1.0},
+		}},
+	}
+
+	// Compute and get the expected outputs.
+	//
+	// i1 = 10.0
+	// i2 = 100.0
+	// b  = 1.0
+	// o1 = tanh(10.0 * 0.1) = tanh(1.0)
+	// o2 = 100.0 * 0.5 = 50.0 (no function, raw sum)
+	// o3 = -1.0 * 1.0 = -1.0 before softmax
+	// o4 = 1.0 * 1.0 = 1.0 before softmax
+	var outputs map[string]float64 = neuralNet.Compute(map[string]float64{"i1": 10.0, "i2": 100.0})
+	c.Assert(len(outputs), Equals, 4)
+	c.Check(outputs["o1"], Equals, math.Tanh(1.0))
+	c.Check(outputs["o2"], Equals, 50.0)
+	c.Check(outputs["o3"], Equals, softmax([]float64{-1.0, 1.0})[0])
+	c.Check(outputs["o4"], Equals, softmax([]float64{-1.0, 1.0})[1])
+	c.Check(math.Abs(outputs["o3"]+outputs["o4"]-1.0) < 0.0000001, Equals, true)
+}
+
+func (s *NeatNeuralNetSuite) Test_NeatNeuralNet_Compute_InputNormalizations(c *C) {
+
+	// Make a new neural net (avoiding randomness).
+	var neuralNet NeatNeuralNet = NeatNeuralNet{
+		InOut: NeuralNetInOut{
+			Inputs:  []string{"i1", "i2"},
+			Outputs: []string{"o1", "o2"},
+			InputNormalizations: map[string]InputNormalization{
+				"i1": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: 0.0, Max: 100.0},
+				"i2": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 7.0}, {Name: "b", Value: 9.0}}},
+			},
+		},
+		Genome: neatGenome{Genes: []neatGene{
+			neatGene{GeneId: 1, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i1", To: "o1", Weight: 0.5},
+			neatGene{GeneId: 2, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2:a", To: "o2", Weight: 0.25},
+			neatGene{GeneId: 3, IsEnabled: true, Type: _GENE_TYPE_CONNECTION, From: "i2:b", To: "o2", Weight: 0.75},
+		}},
+	}
+
+	// Compute and get the expected outputs.
+	//
+	// i1 = 50.0 -> 0.5
+	// i2 = 9.0 -> i2:a = 0.0, i2:b = 1.0
+	// o1 = 0.5 * 0.5 = 0.25
+	// o2 = 0.0 * 0.25 + 1.0 * 0.75 = 0.75
+	var outputs map[string]float64 = neuralNet.Compute(map[string]float64{"i1": 50.0, "i2": 9.0})
+	c.Check(outputs, DeepEquals, map[string]float64{"o1": 0.25, "o2": 0.75})
+
+	// The expanded inputs are not inputs that can be passed in.
+	c.Check(func() { neuralNet.Compute(map[string]float64{"i1": 50.0, "i2": 9.0, "i2:a": 1.0}) }, Panics, `Unknown input: 'i2:a'`)
+
+	// New connections cannot use the expanded inputs as sinks.
+	c.Check(func() { neuralNet.addConnection("i1", "i2:b", 0.5) }, Panics, `Cannot use input as sink: 'i2:b'`)
+}
+
 func (s *NeatNeuralNetSuite) Test_NeatNeuralNet_PrepareComputeTopology_CircularDependency(c *C) {
 
 	// Make a new neural net (avoiding randomness).
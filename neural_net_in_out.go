@@ -13,6 +13,14 @@ const (
 	// String to integer parsing parameters.
 	_BASE_10     = 10
 	_BIT_SIZE_64 = 64
+
+	// The ways a raw input can be normalized before the neural net sees it.
+	NORMALIZE_MIN_MAX = "min_max" // Scale linearly so Min becomes 0.0 and Max becomes 1.0.
+	NORMALIZE_Z_SCORE = "z_score" // Shift and scale by a known mean and standard deviation.
+	NORMALIZE_ONE_HOT = "one_hot" // Expand into one neural net input per category, 1.0 for the matching category and 0.0 for the rest.
+
+	// A one-hot input is expanded into neural net inputs named "<input>:<category>".
+	_ONE_HOT_SEPARATOR = ":"
 )
 
 // NeuralNetInOut is the inputs and outputs for a neural net. All neural nets in a single experiment must share the same
@@ -22,6 +30,31 @@ type NeuralNetInOut struct {
 	Inputs  []string
 	Outputs []string
 	// There is always an assumed bias called "b"
+
+	// Optionally, outputs can be squashed before they are returned from the neural net.
+	OutputFunctions map[string]string   // The activation function for an output, keyed by output. An output without one is the raw sum of its inputs.
+	SoftmaxGroups   map[string][]string // Named groups of outputs squashed together so the group sums to 1.0. Applied after any output activation function.
+
+	// Optionally, inputs can be normalized before the neural net sees them.
+	InputNormalizations map[string]InputNormalization // How to normalize an input, keyed by input. An input without one is passed in raw.
+}
+
+// InputNormalization describes how a single raw input is transformed before the neural net sees it. Since it is part of the
+// in/out, it is recorded with the experiment and travels with every neural net, so a scorer and a deployed champion
+// always agree on it.
+type InputNormalization struct {
+	Method     string          // One of NORMALIZE_MIN_MAX, NORMALIZE_Z_SCORE, or NORMALIZE_ONE_HOT.
+	Min        float64         // For min/max, the raw value that becomes 0.0.
+	Max        float64         // For min/max, the raw value that becomes 1.0.
+	Mean       float64         // For z-score, the mean of the raw values.
+	StdDev     float64         // For z-score, the standard deviation of the raw values.
+	Categories []InputCategory // For one-hot, the raw values the input can take. A raw value matching none of them turns every category off.
+}
+
+// InputCategory is a single named raw value of a one-hot input.
+type InputCategory struct {
+	Name  string  // The name, used to name the neural net input for this category.
+	Value float64 // The raw value that turns this category on.
 }
 
 // Validate confirms that the neural net in/out is well-formed for running the experiment.
@@ -73,4 +106,155 @@ func (i *NeuralNetInOut) validate() {
 			panic(fmt.Sprintf("NeuralNetInOut has output named as a number '%s'. Used for hidden nodes.", out))
 		}
 	}
+
+	// Output functions must be for real outputs and be real activation functions.
+	for out, function := range i.OutputFunctions {
+		if !inStrings(i.Outputs, out) {
+			panic(fmt.Sprintf("NeuralNetInOut has output function for unknown output '%s'", out))
+		}
+		if !isActivationFunction(function) {
+			panic(fmt.Sprintf("NeuralNetInOut has unknown activation function '%s' for output '%s'", function, out))
+		}
+	}
+
+	// Each softmax group must be made of real outputs, and an output can only be in one group.
+	var groupedOutputs map[string]string = map[string]string{}
+	for group, outs := range i.SoftmaxGroups {
+		if len(outs) < 2 {
+			panic(fmt.Sprintf("NeuralNetInOut has softmax group '%s' with fewer than two outputs", group))
+		}
+		for _, out := range outs {
+			if !inStrings(i.Outputs, out) {
+				panic(fmt.Sprintf("NeuralNetInOut has softmax group '%s' with unknown output '%s'", group, out))
+			}
+			var otherGroup string
+			var ok bool
+			if otherGroup, ok = groupedOutputs[out]; ok {
+				panic(fmt.Sprintf("NeuralNetInOut has output '%s' in both softmax groups '%s' and '%s'", out, otherGroup, group))
+			}
+			groupedOutputs[out] = group
+		}
+	}
+
+	// Input normalizations must be for real inputs and be well-formed.
+	for in, normalization := range i.InputNormalizations {
+		if !inStrings(i.Inputs, in) {
+			panic(fmt.Sprintf("NeuralNetInOut has input normalization for unknown input '%s'", in))
+		}
+		normalization.validate(in)
+	}
+
+	// The expanded one-hot inputs cannot collide with any other names.
+	var networkInputs []string = i.networkInputs()
+	for j := 1; j < len(networkInputs); j++ {
+		if networkInputs[j-1] == networkInputs[j] {
+			panic(fmt.Sprintf("NeuralNetInOut has more than one input named '%s'", networkInputs[j]))
+		}
+	}
+	for _, in := range networkInputs {
+		if inStrings(i.Outputs, in) {
+			panic(fmt.Sprintf("NeuralNetInOut has both input and output named '%s'", in))
+		}
+	}
+}
+
+// validate confirms the normalization of an input is well-formed.
+func (n *InputNormalization) validate(in string) {
+	switch n.Method {
+	case NORMALIZE_MIN_MAX:
+		if n.Min >= n.Max {
+			panic(fmt.Sprintf("NeuralNetInOut input '%s' has min (%f) that is not less than max (%f)", in, n.Min, n.Max))
+		}
+	case NORMALIZE_Z_SCORE:
+		if n.StdDev <= 0.0 {
+			panic(fmt.Sprintf("NeuralNetInOut input '%s' has a standard deviation (%f) that is not positive", in, n.StdDev))
+		}
+	case NORMALIZE_ONE_HOT:
+		if len(n.Categories) == 0 {
+			panic(fmt.Sprintf("NeuralNetInOut input '%s' is one-hot with no categories", in))
+		}
+		var names map[string]bool = map[string]bool{}
+		var values map[float64]bool = map[float64]bool{}
+		for _, category := range n.Categories {
+			if category.Name == "" {
+				panic(fmt.Sprintf("NeuralNetInOut input '%s' has a one-hot category with no name", in))
+			}
+			if names[category.Name] {
+				panic(fmt.Sprintf("NeuralNetInOut input '%s' has one-hot category named '%s' more than once", in, category.Name))
+			}
+			if values[category.Value] {
+				panic(fmt.Sprintf("NeuralNetInOut input '%s' has one-hot category value %f more than once", in, category.Value))
+			}
+			names[category.Name] = true
+			values[category.Value] = true
+		}
+	default:
+		panic(fmt.Sprintf("NeuralNetInOut input '%s' has unknown normalization method '%s'", in, n.Method))
+	}
+}
+
+// networkInputs are the names of the input nodes inside the neural net, sorted. They are the inputs, except a one-hot input
+// is replaced with one node per category.
+func (i *NeuralNetInOut) networkInputs() (networkInputs []string) {
+	// Without normalization the inputs are the nodes.
+	if len(i.InputNormalizations) == 0 {
+		return i.Inputs
+	}
+	for _, in := range i.Inputs {
+		var normalization InputNormalization = i.InputNormalizations[in]
+		if normalization.Method == NORMALIZE_ONE_HOT {
+			for _, category := range normalization.Categories {
+				networkInputs = append(networkInputs, oneHotInput(in, category.Name))
+			}
+		} else {
+			networkInputs = append(networkInputs, in)
+		}
+	}
+	sort.Strings(networkInputs)
+	return networkInputs
+}
+
+// oneHotInput names the neural net input node for a single category of a one-hot input.
+func oneHotInput(in string, category string) string {
+	return in + _ONE_HOT_SEPARATOR + category
+}
+
+// normalizeInput sets the value of the neural net input node (or nodes for one-hot) from a single raw input value.
+func (i *NeuralNetInOut) normalizeInput(in string, value float64, nodeValues map[string]float64) {
+	var normalization InputNormalization = i.InputNormalizations[in]
+	switch normalization.Method {
+	case "":
+		nodeValues[in] = value
+	case NORMALIZE_MIN_MAX:
+		nodeValues[in] = (value - normalization.Min) / (normalization.Max - normalization.Min)
+	case NORMALIZE_Z_SCORE:
+		nodeValues[in] = (value - normalization.Mean) / normalization.StdDev
+	case NORMALIZE_ONE_HOT:
+		for _, category := range normalization.Categories {
+			var nodeValue float64 = 0.0
+			if value == category.Value {
+				nodeValue = 1.0
+			}
+			nodeValues[oneHotInput(in, category.Name)] = nodeValue
+		}
+	default:
+		panic(fmt.Sprintf("Unknown normalization method '%s' for input '%s'", normalization.Method, in))
+	}
+}
+
+// applySoftmaxGroups squashes the outputs of each softmax group together, leaving other outputs unchanged.
+func (i *NeuralNetInOut) applySoftmaxGroups(outputs map[string]float64) {
+	for _, outs := range i.SoftmaxGroups {
+
+		// Gather the group's values in the group's order.
+		var values []float64
+		for _, out := range outs {
+			values = append(values, outputs[out])
+		}
+
+		// Put the squashed values back.
+		for j, value := range softmax(values) {
+			outputs[outs[j]] = value
+		}
+	}
 }
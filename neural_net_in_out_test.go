@@ -100,3 +100,188 @@ func (s *NeuralNetInOutSuite) Test_NeuralNetInOut_Validate(c *C) {
 	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has no outputs.`)
 
 }
+
+func (s *NeuralNetInOutSuite) Test_NeuralNetInOut_Validate_OutputFunctions(c *C) {
+	var inOut NeuralNetInOut
+
+	// A well-formed in/out with output functions and a softmax group.
+	inOut = NeuralNetInOut{
+		Inputs:          []string{"i1", "i2"},
+		Outputs:         []string{"o1", "o2", "o3"},
+		OutputFunctions: map[string]string{"o1": ACTIVATION_SIGMOID},
+		SoftmaxGroups:   map[string][]string{"choice": []string{"o2", "o3"}},
+	}
+	inOut.validate() // No panic.
+
+	// An output function for an unknown output.
+	inOut = NeuralNetInOut{
+		Inputs:          []string{"i1", "i2"},
+		Outputs:         []string{"o1", "o2", "o3"},
+		OutputFunctions: map[string]string{"o4": ACTIVATION_SIGMOID},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has output function for unknown output 'o4'`)
+
+	// An unknown output function.
+	inOut = NeuralNetInOut{
+		Inputs:          []string{"i1", "i2"},
+		Outputs:         []string{"o1", "o2", "o3"},
+		OutputFunctions: map[string]string{"o1": "BOOGA"},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has unknown activation function 'BOOGA' for output 'o1'`)
+
+	// A softmax group that is too small.
+	inOut = NeuralNetInOut{
+		Inputs:        []string{"i1", "i2"},
+		Outputs:       []string{"o1", "o2", "o3"},
+		SoftmaxGroups: map[string][]string{"choice": []string{"o2"}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has softmax group 'choice' with fewer than two outputs`)
+
+	// A softmax group with an unknown output.
+	inOut = NeuralNetInOut{
+		Inputs:        []string{"i1", "i2"},
+		Outputs:       []string{"o1", "o2", "o3"},
+		SoftmaxGroups: map[string][]string{"choice": []string{"o2", "o4"}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has softmax group 'choice' with unknown output 'o4'`)
+
+	// An output in two softmax groups.
+	inOut = NeuralNetInOut{
+		Inputs:        []string{"i1", "i2"},
+		Outputs:       []string{"o1", "o2", "o3"},
+		SoftmaxGroups: map[string][]string{"a": []string{"o1", "o2"}, "b": []string{"o2", "o3"}},
+	}
+	c.Assert(func() { inOut.validate() }, PanicMatches, `NeuralNetInOut has output 'o2' in both softmax groups '.' and '.'`)
+}
+
+func (s *NeuralNetInOutSuite) Test_NeuralNetInOut_Validate_InputNormalizations(c *C) {
+	var inOut NeuralNetInOut
+
+	// A well-formed in/out with every kind of normalization.
+	inOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2", "i3"},
+		Outputs: []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{
+			"i1": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: 0.0, Max: 10.0},
+			"i2": InputNormalization{Method: NORMALIZE_Z_SCORE, Mean: 5.0, StdDev: 2.0},
+			"i3": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 1.0}, {Name: "b", Value: 2.0}}},
+		},
+	}
+	inOut.validate() // No panic.
+
+	// A normalization for an unknown input.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i2": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: 0.0, Max: 10.0}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has input normalization for unknown input 'i2'`)
+
+	// An unknown method.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: "BOOGA"}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' has unknown normalization method 'BOOGA'`)
+
+	// A backwards min/max.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: 10.0, Max: 10.0}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' has min (10.000000) that is not less than max (10.000000)`)
+
+	// A z-score with no spread.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_Z_SCORE, Mean: 5.0}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' has a standard deviation (0.000000) that is not positive`)
+
+	// A one-hot with no categories.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_ONE_HOT}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' is one-hot with no categories`)
+
+	// A one-hot with a repeated category.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 1.0}, {Name: "a", Value: 2.0}}}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' has one-hot category named 'a' more than once`)
+
+	// A one-hot with a repeated value.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 1.0}, {Name: "b", Value: 1.0}}}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut input 'i1' has one-hot category value 1.000000 more than once`)
+
+	// A one-hot category colliding with an output name.
+	inOut = NeuralNetInOut{
+		Inputs:              []string{"i1"},
+		Outputs:             []string{"i1:a"},
+		InputNormalizations: map[string]InputNormalization{"i1": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 1.0}}}},
+	}
+	c.Assert(func() { inOut.validate() }, Panics, `NeuralNetInOut has both input and output named 'i1:a'`)
+}
+
+func (s *NeuralNetInOutSuite) Test_NeuralNetInOut_NetworkInputs(c *C) {
+	var inOut NeuralNetInOut
+
+	// Without normalization the inputs are the network inputs.
+	inOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2"},
+		Outputs: []string{"o1"},
+	}
+	c.Check(inOut.networkInputs(), DeepEquals, []string{"i1", "i2"})
+
+	// One-hot inputs expand into a network input per category.
+	inOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2", "j3"},
+		Outputs: []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{
+			"i1": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: 0.0, Max: 10.0},
+			"i2": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "z", Value: 1.0}, {Name: "a", Value: 2.0}}},
+		},
+	}
+	c.Check(inOut.networkInputs(), DeepEquals, []string{"i1", "i2:a", "i2:z", "j3"})
+}
+
+func (s *NeuralNetInOutSuite) Test_NeuralNetInOut_NormalizeInput(c *C) {
+	var inOut NeuralNetInOut = NeuralNetInOut{
+		Inputs:  []string{"i1", "i2", "i3", "i4"},
+		Outputs: []string{"o1"},
+		InputNormalizations: map[string]InputNormalization{
+			"i1": InputNormalization{Method: NORMALIZE_MIN_MAX, Min: -10.0, Max: 10.0},
+			"i2": InputNormalization{Method: NORMALIZE_Z_SCORE, Mean: 5.0, StdDev: 2.0},
+			"i3": InputNormalization{Method: NORMALIZE_ONE_HOT, Categories: []InputCategory{{Name: "a", Value: 1.0}, {Name: "b", Value: 2.0}}},
+		},
+	}
+
+	var nodeValues map[string]float64 = map[string]float64{}
+	inOut.normalizeInput("i1", 5.0, nodeValues)
+	inOut.normalizeInput("i2", 1.0, nodeValues)
+	inOut.normalizeInput("i3", 2.0, nodeValues)
+	inOut.normalizeInput("i4", 1000.0, nodeValues)
+	c.Check(nodeValues, DeepEquals, map[string]float64{
+		"i1":   0.75,   // (5 - -10) / (10 - -10)
+		"i2":   -2.0,   // (1 - 5) / 2
+		"i3:a": 0.0,    // Not the category.
+		"i3:b": 1.0,    // The category.
+		"i4":   1000.0, // Not normalized.
+	})
+
+	// A one-hot value that is no category turns them all off.
+	inOut.normalizeInput("i3", 3.0, nodeValues)
+	c.Check(nodeValues["i3:a"], Equals, 0.0)
+	c.Check(nodeValues["i3:b"], Equals, 0.0)
+}
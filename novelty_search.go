@@ -1,10 +1,27 @@
 package genetic
 
 import (
+	"log"
+	"math"
 	"math/rand"
 	"sort"
 )
 
+const (
+	// The built-in behavior distances.
+	BEHAVIOR_DISTANCE_EUCLIDEAN = "euclidean"
+	BEHAVIOR_DISTANCE_MANHATTAN = "manhattan"
+	BEHAVIOR_DISTANCE_CHEBYSHEV = "chebyshev"
+
+	// How behaviors are added to the novelty archive.
+	NOVELTY_ARCHIVE_THRESHOLD = "threshold" // Behaviors more novel than a threshold.
+	NOVELTY_ARCHIVE_RANDOM    = "random"    // Behaviors picked at random.
+
+	// Where novelty is given to a specimen.
+	NOVELTY_TARGET_BONUS   = "bonus"   // Added to the bonus.
+	NOVELTY_TARGET_OUTCOME = "outcome" // Appended as the last outcome, for multi-outcome sorters.
+)
+
 // NoveltySearchTally is a structure for keeping track of how many times a result has been seen.
 // Novelty searches reward new outcomes higher than previously seen outcomes.
 // Internally, the fingerprint is stored twice. The fingerprint is expected to be a md5 in text format as a 32 digit hexadecimal number.
@@ -72,3 +89,197 @@ func (n *NoveltySearchTally) removeRandomEntry() {
 	// Remove the count.
 	delete(n.fingerprintCounts, fingerprintToRemove)
 }
+
+// BehaviorDistance is how far apart two behaviors are.
+type BehaviorDistance func(a []float64, b []float64) float64
+
+// behaviorDistances are the distances a novelty search can use, by name.
+var behaviorDistances map[string]BehaviorDistance = map[string]BehaviorDistance{
+	BEHAVIOR_DISTANCE_EUCLIDEAN: euclideanDistance,
+	BEHAVIOR_DISTANCE_MANHATTAN: manhattanDistance,
+	BEHAVIOR_DISTANCE_CHEBYSHEV: chebyshevDistance,
+}
+
+// RegisterBehaviorDistance makes a distance available to novelty searches by name, for behaviors that are not points in
+// space (e.g. hands of cards).
+func RegisterBehaviorDistance(name string, distance BehaviorDistance) {
+	if name == "" || distance == nil {
+		log.Panicf("Behavior distance needs a name and a function: '%s'", name)
+	}
+	behaviorDistances[name] = distance
+}
+
+// euclideanDistance is the straight line distance between two behaviors.
+func euclideanDistance(a []float64, b []float64) float64 {
+	behaviorsSameLengthOrPanic(a, b)
+	var sum float64
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// manhattanDistance is the sum of the differences between two behaviors.
+func manhattanDistance(a []float64, b []float64) float64 {
+	behaviorsSameLengthOrPanic(a, b)
+	var sum float64
+	for i := range a {
+		sum += math.Abs(a[i] - b[i])
+	}
+	return sum
+}
+
+// chebyshevDistance is the largest difference between two behaviors.
+func chebyshevDistance(a []float64, b []float64) float64 {
+	behaviorsSameLengthOrPanic(a, b)
+	var largest float64
+	for i := range a {
+		largest = math.Max(largest, math.Abs(a[i]-b[i]))
+	}
+	return largest
+}
+
+// behaviorsSameLengthOrPanic panics if two behaviors cannot be compared.
+func behaviorsSameLengthOrPanic(a []float64, b []float64) {
+	if len(a) != len(b) {
+		log.Panicf("Behaviors must be the same length to compare: %v, %v", a, b)
+	}
+}
+
+// NoveltySearch measures how novel each behavior of a population is, against the rest of the population and an archive
+// of behaviors from earlier generations.
+//
+// Reference: Lehman, J. and Stanley, K. O. (2011) Abandoning Objectives: Evolution Through the Search for Novelty Alone.
+type NoveltySearch struct {
+	config   ConfigNoveltySearch
+	distance BehaviorDistance
+	archive  [][]float64 // The behaviors remembered from earlier generations.
+}
+
+// NewNoveltySearch creates a novelty search with an empty archive.
+func NewNoveltySearch(config ConfigNoveltySearch) *NoveltySearch {
+	config.validOrPanic()
+	if config.Distance == "" {
+		config.Distance = BEHAVIOR_DISTANCE_EUCLIDEAN
+	}
+	if config.Weight == 0.0 {
+		config.Weight = 1.0
+	}
+	return &NoveltySearch{
+		config:   config,
+		distance: behaviorDistances[config.Distance],
+	}
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigNoveltySearch) validOrPanic() {
+	if c.K < 1 {
+		log.Panicf("Novelty search K must be one or more: %d", c.K)
+	}
+	var ok bool
+	if _, ok = behaviorDistances[c.Distance]; c.Distance != "" && !ok {
+		log.Panicf("Unknown behavior distance: '%s'", c.Distance)
+	}
+	switch c.ArchivePolicy {
+	case NOVELTY_ARCHIVE_THRESHOLD:
+	case NOVELTY_ARCHIVE_RANDOM:
+		if c.ArchiveProbability < 0.0 || c.ArchiveProbability > 1.0 {
+			log.Panicf("ArchiveProbability must be 0.0 to 1.0: %f", c.ArchiveProbability)
+		}
+	default:
+		log.Panicf("Unknown novelty archive policy: '%s'", c.ArchivePolicy)
+	}
+	if c.ArchiveMaxSize < 0 {
+		log.Panicf("ArchiveMaxSize cannot be negative: %d", c.ArchiveMaxSize)
+	}
+	if c.Target != NOVELTY_TARGET_BONUS && c.Target != NOVELTY_TARGET_OUTCOME {
+		log.Panicf("Unknown novelty target: '%s'", c.Target)
+	}
+}
+
+// ArchiveSize is how many behaviors are in the archive.
+func (n *NoveltySearch) ArchiveSize() int { return len(n.archive) }
+
+// Novelty measures each behavior's mean distance to its K nearest neighbors among the other behaviors and the archive.
+// If there are fewer than K neighbors, all of them are used. Afterwards, behaviors are added to the archive by its policy,
+// so a generation is never compared against itself twice.
+func (n *NoveltySearch) Novelty(behaviors [][]float64) (novelty []float64) {
+	novelty = make([]float64, len(behaviors))
+	for i, behavior := range behaviors {
+		var distances []float64
+		for j, other := range behaviors {
+			if j != i {
+				distances = append(distances, n.distance(behavior, other))
+			}
+		}
+		for _, other := range n.archive {
+			distances = append(distances, n.distance(behavior, other))
+		}
+		novelty[i] = meanOfSmallest(distances, n.config.K)
+	}
+
+	// Remember the behaviors that should be remembered.
+	for i, behavior := range behaviors {
+		var isArchived bool
+		switch n.config.ArchivePolicy {
+		case NOVELTY_ARCHIVE_THRESHOLD:
+			isArchived = novelty[i] > n.config.ArchiveThreshold
+		case NOVELTY_ARCHIVE_RANDOM:
+			isArchived = rand.Float64() < n.config.ArchiveProbability // Assume the seed has been set.
+		}
+		if isArchived {
+			n.addToArchive(behavior)
+		}
+	}
+
+	return novelty
+}
+
+// addToArchive remembers a behavior, forgetting a random one first if the archive is full.
+func (n *NoveltySearch) addToArchive(behavior []float64) {
+	if n.config.ArchiveMaxSize > 0 && len(n.archive) >= n.config.ArchiveMaxSize {
+		// https://code.google.com/p/go-wiki/wiki/SliceTricks
+		var indexToRemove int = rand.Intn(len(n.archive))
+		n.archive = append(n.archive[:indexToRemove], n.archive[indexToRemove+1:]...)
+	}
+	n.archive = append(n.archive, behavior)
+}
+
+// Apply gives each scored result its novelty, either in the bonus or as an extra outcome. Every result needs a behavior.
+func (n *NoveltySearch) Apply(results []ScoreResult) (novelty []float64) {
+	var behaviors [][]float64
+	for i, result := range results {
+		if result.Behavior == nil {
+			log.Panicf("Novelty search needs a Behavior from the scorer for every neural net, missing at: %d", i)
+		}
+		behaviors = append(behaviors, result.Behavior)
+	}
+
+	novelty = n.Novelty(behaviors)
+	for i := range results {
+		var weighted float64 = novelty[i] * n.config.Weight
+		switch n.config.Target {
+		case NOVELTY_TARGET_BONUS:
+			results[i].Bonus += weighted
+		case NOVELTY_TARGET_OUTCOME:
+			results[i].Outcomes = append(append([]float64{}, results[i].Outcomes...), weighted)
+		}
+	}
+	return novelty
+}
+
+// meanOfSmallest is the mean of the count smallest values, or all of them if there are not that many. 0.0 if there are none.
+func meanOfSmallest(values []float64, count int) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	sort.Float64s(values)
+	if count > len(values) {
+		count = len(values)
+	}
+	var sum float64
+	for _, value := range values[:count] {
+		sum += value
+	}
+	return sum / float64(count)
+}
@@ -96,6 +96,167 @@ func (s *NoveltySearchSuite) Test_NoveltySearchTally(c *C) {
 
 }
 
+func (s *NoveltySearchSuite) Test_BehaviorDistances(c *C) {
+	var a []float64 = []float64{1.0, 2.0}
+	var b []float64 = []float64{4.0, -2.0}
+	c.Check(euclideanDistance(a, b), Equals, 5.0)
+	c.Check(manhattanDistance(a, b), Equals, 7.0)
+	c.Check(chebyshevDistance(a, b), Equals, 4.0)
+	c.Check(func() { euclideanDistance(a, []float64{1.0}) }, Panics, "Behaviors must be the same length to compare: [1 2], [1]")
+
+	// Distances can be added for behaviors that are not points.
+	RegisterBehaviorDistance("test_count_different", func(a []float64, b []float64) float64 {
+		var count float64
+		for i := range a {
+			if a[i] != b[i] {
+				count++
+			}
+		}
+		return count
+	})
+	var noveltySearch *NoveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 1, Distance: "test_count_different", ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, ArchiveThreshold: 100.0, Target: NOVELTY_TARGET_BONUS})
+	c.Check(noveltySearch.Novelty([][]float64{a, b, []float64{1.0, 3.0}}), DeepEquals, []float64{1.0, 2.0, 1.0})
+}
+
+func (s *NoveltySearchSuite) Test_NoveltySearch_Novelty(c *C) {
+	var noveltySearch *NoveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 2, ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, ArchiveThreshold: 2.5, Target: NOVELTY_TARGET_BONUS})
+
+	// Hands that differ a little are a little novel, not completely novel.
+	var novelty []float64 = noveltySearch.Novelty([][]float64{
+		[]float64{0.0},
+		[]float64{1.0},
+		[]float64{3.0},
+		[]float64{10.0},
+	})
+	c.Check(novelty, DeepEquals, []float64{2.0, 1.5, 2.5, 8.0})
+
+	// Only the behavior more novel than the threshold was archived.
+	c.Check(noveltySearch.archive, DeepEquals, [][]float64{[]float64{10.0}})
+
+	// The archive counts as neighbors in later generations.
+	novelty = noveltySearch.Novelty([][]float64{
+		[]float64{9.0},
+		[]float64{12.0},
+	})
+	c.Check(novelty, DeepEquals, []float64{2.0, 2.5})
+	c.Check(noveltySearch.ArchiveSize(), Equals, 1)
+
+	// With fewer neighbors than K, all of them are used. With none, there is no novelty.
+	noveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 5, ArchivePolicy: NOVELTY_ARCHIVE_RANDOM, ArchiveProbability: 0.0, Target: NOVELTY_TARGET_BONUS})
+	c.Check(noveltySearch.Novelty([][]float64{[]float64{0.0}}), DeepEquals, []float64{0.0})
+	c.Check(noveltySearch.Novelty([][]float64{[]float64{0.0}, []float64{2.0}, []float64{4.0}}), DeepEquals, []float64{3.0, 2.0, 3.0})
+	c.Check(noveltySearch.ArchiveSize(), Equals, 0)
+}
+
+func (s *NoveltySearchSuite) Test_NoveltySearch_Archive(c *C) {
+
+	// Everything is archived, but the archive never grows too large.
+	var noveltySearch *NoveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 1, ArchivePolicy: NOVELTY_ARCHIVE_RANDOM, ArchiveProbability: 1.0, ArchiveMaxSize: 3, Target: NOVELTY_TARGET_BONUS})
+	noveltySearch.Novelty([][]float64{[]float64{0.0}, []float64{1.0}})
+	c.Check(noveltySearch.archive, DeepEquals, [][]float64{[]float64{0.0}, []float64{1.0}})
+	noveltySearch.Novelty([][]float64{[]float64{2.0}, []float64{3.0}})
+	c.Check(noveltySearch.ArchiveSize(), Equals, 3)
+	c.Check(noveltySearch.archive[2], DeepEquals, []float64{3.0}) // The newest is always kept.
+}
+
+func (s *NoveltySearchSuite) Test_NoveltySearch_Apply(c *C) {
+	var results []ScoreResult
+
+	// Novelty as a bonus.
+	var noveltySearch *NoveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 1, ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, ArchiveThreshold: 100.0, Target: NOVELTY_TARGET_BONUS, Weight: 0.5})
+	results = []ScoreResult{
+		ScoreResult{Score: 10.0, Bonus: 1.0, Behavior: []float64{0.0, 0.0}},
+		ScoreResult{Score: 20.0, Bonus: 0.0, Behavior: []float64{3.0, 4.0}},
+	}
+	c.Check(noveltySearch.Apply(results), DeepEquals, []float64{5.0, 5.0})
+	c.Check(results, DeepEquals, []ScoreResult{
+		ScoreResult{Score: 10.0, Bonus: 3.5, Behavior: []float64{0.0, 0.0}},
+		ScoreResult{Score: 20.0, Bonus: 2.5, Behavior: []float64{3.0, 4.0}},
+	})
+
+	// Novelty as an outcome.
+	noveltySearch = NewNoveltySearch(ConfigNoveltySearch{K: 1, Distance: BEHAVIOR_DISTANCE_MANHATTAN, ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, ArchiveThreshold: 100.0, Target: NOVELTY_TARGET_OUTCOME})
+	results = []ScoreResult{
+		ScoreResult{Outcomes: []float64{1.0}, Behavior: []float64{0.0, 0.0}},
+		ScoreResult{Outcomes: []float64{2.0}, Behavior: []float64{3.0, 4.0}},
+	}
+	noveltySearch.Apply(results)
+	c.Check(results[0].Outcomes, DeepEquals, []float64{1.0, 7.0})
+	c.Check(results[1].Outcomes, DeepEquals, []float64{2.0, 7.0})
+
+	// Every result needs a behavior.
+	c.Check(func() { noveltySearch.Apply([]ScoreResult{ScoreResult{Score: 1.0}}) }, Panics, "Novelty search needs a Behavior from the scorer for every neural net, missing at: 0")
+}
+
+func (s *NoveltySearchSuite) Test_ConfigNoveltySearch_ValidOrPanic(c *C) {
+	var config ConfigNoveltySearch = ConfigNoveltySearch{K: 1, ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, Target: NOVELTY_TARGET_BONUS}
+	config.validOrPanic() // Valid.
+
+	var invalid ConfigNoveltySearch
+
+	invalid = config
+	invalid.K = 0
+	c.Check(func() { invalid.validOrPanic() }, Panics, "Novelty search K must be one or more: 0")
+
+	invalid = config
+	invalid.Distance = "unknown"
+	c.Check(func() { invalid.validOrPanic() }, Panics, "Unknown behavior distance: 'unknown'")
+
+	invalid = config
+	invalid.ArchivePolicy = ""
+	c.Check(func() { invalid.validOrPanic() }, Panics, "Unknown novelty archive policy: ''")
+
+	invalid = config
+	invalid.ArchivePolicy = NOVELTY_ARCHIVE_RANDOM
+	invalid.ArchiveProbability = 1.5
+	c.Check(func() { invalid.validOrPanic() }, Panics, "ArchiveProbability must be 0.0 to 1.0: 1.500000")
+
+	invalid = config
+	invalid.ArchiveMaxSize = -1
+	c.Check(func() { invalid.validOrPanic() }, Panics, "ArchiveMaxSize cannot be negative: -1")
+
+	invalid = config
+	invalid.Target = "score"
+	c.Check(func() { invalid.validOrPanic() }, Panics, "Unknown novelty target: 'score'")
+}
+
+// behaviorTestScorer reports the first weight of a neural net as its behavior.
+type behaviorTestScorer struct{}
+
+func (s *behaviorTestScorer) Score(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (score float64, bonus float64, outcomes []float64) {
+	return 1.0, 2.0, []float64{3.0}
+}
+func (s *behaviorTestScorer) ScoreResult(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (result ScoreResult) {
+	return ScoreResult{Score: float64(neuralNetIndex), Behavior: []float64{neuralNet.Genome.Genes[0].Weight}}
+}
+func (s *behaviorTestScorer) GenerationStart(generationNum uint64) {}
+func (s *behaviorTestScorer) GenerationDetails() (json []byte)     { return nil }
+
+func (s *NoveltySearchSuite) Test_ScoreNeuralNets(c *C) {
+	var population []NeatNeuralNet = []NeatNeuralNet{
+		NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.5}}}},
+		NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.25}}}},
+	}
+
+	// Scorers that report behaviors are asked for them.
+	c.Check(scoreNeuralNets(&behaviorTestScorer{}, population), DeepEquals, []ScoreResult{
+		ScoreResult{Score: 0.0, Behavior: []float64{0.5}},
+		ScoreResult{Score: 1.0, Behavior: []float64{0.25}},
+	})
+
+	// Other scorers just score.
+	c.Check(scoreNeuralNets(&phenotypeTestScorer{}, population), DeepEquals, []ScoreResult{
+		ScoreResult{Score: 0.5},
+		ScoreResult{Score: 0.25},
+	})
+
+	// Phenotype scorers pass behaviors through.
+	c.Check(scoreNeuralNets(NewPhenotypeScorer(&phenotypeTestDecoder{}, &behaviorTestScorer{}), population), DeepEquals, []ScoreResult{
+		ScoreResult{Score: 0.0, Behavior: []float64{1.0}}, // The test decoder counts decodes into the weight.
+		ScoreResult{Score: 1.0, Behavior: []float64{2.0}},
+	})
+}
+
 // sortedMapKeys gets keys of a map in an order suitable for testing.
 func sortedMapKeys(theMap map[string]int) []string {
 	var keys []string = []string{}
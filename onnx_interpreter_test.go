@@ -0,0 +1,300 @@
+package genetic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// The test interpreter reads the ONNX protobuf without any of the code that wrote it, and runs the graph, so tests check the
+// model itself rather than the writer's idea of it. It only understands the little bit of ONNX that WriteOnnx uses.
+
+// protobufField is a single field read from a protobuf message.
+type protobufField struct {
+	field    int
+	wireType int
+	varint   uint64 // For varint, 64-bit, and 32-bit fields.
+	bytes    []byte // For length-delimited fields.
+}
+
+// parseProtobuf reads all the fields of a protobuf message.
+func parseProtobuf(data []byte) (fields []protobufField, err error) {
+	for len(data) > 0 {
+		var tag uint64
+		var size int
+		if tag, size = binary.Uvarint(data); size <= 0 {
+			return nil, fmt.Errorf("bad tag")
+		}
+		data = data[size:]
+		var field protobufField = protobufField{field: int(tag >> 3), wireType: int(tag & 0x7)}
+		switch field.wireType {
+		case 0:
+			if field.varint, size = binary.Uvarint(data); size <= 0 {
+				return nil, fmt.Errorf("bad varint in field %d", field.field)
+			}
+			data = data[size:]
+		case 1:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("short 64-bit field %d", field.field)
+			}
+			field.varint = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case 2:
+			var length uint64
+			if length, size = binary.Uvarint(data); size <= 0 || uint64(len(data)-size) < length {
+				return nil, fmt.Errorf("bad length in field %d", field.field)
+			}
+			field.bytes = data[size : size+int(length)]
+			data = data[size+int(length):]
+		case 5:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("short 32-bit field %d", field.field)
+			}
+			field.varint = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return nil, fmt.Errorf("unknown wire type %d in field %d", field.wireType, field.field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// mustParseProtobuf reads a protobuf message that is expected to be well-formed.
+func mustParseProtobuf(data []byte) []protobufField {
+	var fields []protobufField
+	var err error
+	if fields, err = parseProtobuf(data); err != nil {
+		panic(err)
+	}
+	return fields
+}
+
+// onnxTestModel is an ONNX model read back for testing.
+type onnxTestModel struct {
+	irVersion    uint64
+	producerName string
+	opsetDomain  string
+	opsetVersion uint64
+	graphName    string
+	nodes        []onnxTestNode
+	initializers map[string]float64
+	inputs       []string
+	outputs      []string
+}
+
+// onnxTestNode is an ONNX operation read back for testing.
+type onnxTestNode struct {
+	name       string
+	opType     string
+	inputs     []string
+	outputs    []string
+	attributes map[string]int64
+}
+
+// readOnnxTestModel reads an ONNX model protobuf.
+func readOnnxTestModel(data []byte) (model onnxTestModel, err error) {
+	var fields []protobufField
+	if fields, err = parseProtobuf(data); err != nil {
+		return onnxTestModel{}, err
+	}
+	model.initializers = map[string]float64{}
+	for _, field := range fields {
+		switch field.field {
+		case 1:
+			model.irVersion = field.varint
+		case 2:
+			model.producerName = string(field.bytes)
+		case 7:
+			if err = model.readGraph(field.bytes); err != nil {
+				return onnxTestModel{}, err
+			}
+		case 8:
+			for _, opset := range mustParseProtobuf(field.bytes) {
+				switch opset.field {
+				case 1:
+					model.opsetDomain = string(opset.bytes)
+				case 2:
+					model.opsetVersion = opset.varint
+				}
+			}
+		}
+	}
+	return model, nil
+}
+
+// readGraph reads an ONNX graph protobuf into the model.
+func (m *onnxTestModel) readGraph(data []byte) (err error) {
+	var fields []protobufField
+	if fields, err = parseProtobuf(data); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		switch field.field {
+		case 1:
+			var node onnxTestNode = onnxTestNode{attributes: map[string]int64{}}
+			for _, nodeField := range mustParseProtobuf(field.bytes) {
+				switch nodeField.field {
+				case 1:
+					node.inputs = append(node.inputs, string(nodeField.bytes))
+				case 2:
+					node.outputs = append(node.outputs, string(nodeField.bytes))
+				case 3:
+					node.name = string(nodeField.bytes)
+				case 4:
+					node.opType = string(nodeField.bytes)
+				case 5:
+					var name string
+					var value int64
+					for _, attributeField := range mustParseProtobuf(nodeField.bytes) {
+						switch attributeField.field {
+						case 1:
+							name = string(attributeField.bytes)
+						case 3:
+							value = int64(attributeField.varint)
+						case 20:
+							if attributeField.varint != _ONNX_ATTRIBUTE_INT {
+								return fmt.Errorf("attribute is not an int: %d", attributeField.varint)
+							}
+						}
+					}
+					node.attributes[name] = value
+				}
+			}
+			m.nodes = append(m.nodes, node)
+		case 2:
+			m.graphName = string(field.bytes)
+		case 5:
+			var name string
+			var values []float64
+			for _, tensorField := range mustParseProtobuf(field.bytes) {
+				switch tensorField.field {
+				case 1:
+					if len(tensorField.bytes) != 1 || tensorField.bytes[0] != 1 {
+						return fmt.Errorf("initializer is not a tensor of one value: %v", tensorField.bytes)
+					}
+				case 2:
+					if tensorField.varint != _ONNX_DATA_TYPE_DOUBLE {
+						return fmt.Errorf("initializer is not a double: %d", tensorField.varint)
+					}
+				case 8:
+					name = string(tensorField.bytes)
+				case 10:
+					for i := 0; i+8 <= len(tensorField.bytes); i += 8 {
+						values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(tensorField.bytes[i:])))
+					}
+				}
+			}
+			if len(values) != 1 {
+				return fmt.Errorf("initializer '%s' has %d values", name, len(values))
+			}
+			m.initializers[name] = values[0]
+		case 11, 12:
+			var name string
+			for _, valueInfoField := range mustParseProtobuf(field.bytes) {
+				if valueInfoField.field == 1 {
+					name = string(valueInfoField.bytes)
+				}
+			}
+			if field.field == 11 {
+				m.inputs = append(m.inputs, name)
+			} else {
+				m.outputs = append(m.outputs, name)
+			}
+		}
+	}
+	return nil
+}
+
+// run computes the graph. Every value is a single double, with booleans as 1.0 and 0.0.
+func (m *onnxTestModel) run(inputs map[string]float64) (outputs map[string]float64, err error) {
+	var values map[string]float64 = map[string]float64{}
+	for name, value := range m.initializers {
+		values[name] = value
+	}
+	for _, name := range m.inputs {
+		var ok bool
+		if values[name], ok = inputs[name]; !ok {
+			return nil, fmt.Errorf("missing input '%s'", name)
+		}
+	}
+
+	for _, node := range m.nodes {
+
+		// Every operation must come after the values it needs.
+		var args []float64
+		for _, input := range node.inputs {
+			var value float64
+			var ok bool
+			if value, ok = values[input]; !ok {
+				return nil, fmt.Errorf("node '%s' uses '%s' before it is computed", node.name, input)
+			}
+			args = append(args, value)
+		}
+
+		var result float64
+		switch node.opType {
+		case "Identity":
+			result = args[0]
+		case "Mul":
+			result = args[0] * args[1]
+		case "Sub":
+			result = args[0] - args[1]
+		case "Div":
+			result = args[0] / args[1]
+		case "Sum":
+			for _, arg := range args {
+				result += arg
+			}
+		case "Max":
+			result = args[0]
+			for _, arg := range args {
+				result = math.Max(result, arg)
+			}
+		case "Neg":
+			result = -args[0]
+		case "Abs":
+			result = math.Abs(args[0])
+		case "Floor":
+			result = math.Floor(args[0])
+		case "Exp":
+			result = math.Exp(args[0])
+		case "Sigmoid":
+			result = 1.0 / (1.0 + math.Exp(-args[0]))
+		case "Tanh":
+			result = math.Tanh(args[0])
+		case "Sin":
+			result = math.Sin(args[0])
+		case "Cos":
+			result = math.Cos(args[0])
+		case "Tan":
+			result = math.Tan(args[0])
+		case "Equal":
+			if args[0] == args[1] {
+				result = 1.0
+			}
+		case "Cast":
+			if node.attributes["to"] != _ONNX_DATA_TYPE_DOUBLE {
+				return nil, fmt.Errorf("node '%s' casts to an unexpected type: %d", node.name, node.attributes["to"])
+			}
+			result = args[0]
+		default:
+			return nil, fmt.Errorf("node '%s' has an unknown op type: '%s'", node.name, node.opType)
+		}
+
+		// Values are only ever computed once.
+		if _, ok := values[node.outputs[0]]; ok {
+			return nil, fmt.Errorf("node '%s' computes '%s' a second time", node.name, node.outputs[0])
+		}
+		values[node.outputs[0]] = result
+	}
+
+	outputs = map[string]float64{}
+	for _, name := range m.outputs {
+		var ok bool
+		if outputs[name], ok = values[name]; !ok {
+			return nil, fmt.Errorf("output '%s' is never computed", name)
+		}
+	}
+	return outputs, nil
+}
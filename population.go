@@ -45,15 +45,24 @@ func (p *generationPopulation) AddSpecimen(specimen Specimen) {
 // FillOut grows the population from the fittest of last generation to a full population by mutation and mating.
 func (p *generationPopulation) FillOut() {
 
+	// How many more specimens do we need?
+	var specimensNeeded int = (p.config.PopulationSize - p.prepareRandomSpecimenIndexes())
+
+	// Add all the new specimens to species, creating any needed to house them.
+	for _, specimen := range p.breed(specimensNeeded) {
+		p.AddSpecimen(specimen)
+	}
+}
+
+// breed creates count new specimens from the specimens of the population by mutation and mating. They are not added to
+// the population.
+func (p *generationPopulation) breed(count int) (newSpecimens []Specimen) {
+
 	// Prepare the species for pulling random specimens.
 	var specimenCount int = p.prepareRandomSpecimenIndexes()
 
 	// Gather all the new specimens.
-	var newSpecimens []Specimen
-
-	// How many more specimens do we need?
-	var specimensNeeded int = (p.config.PopulationSize - specimenCount)
-	for i := 0; i < specimensNeeded; i++ {
+	for i := 0; i < count; i++ {
 
 		// Pick a random specimen.
 		var specimen Specimen
@@ -65,11 +74,7 @@ func (p *generationPopulation) FillOut() {
 		var mutant Specimen = specimen.mateMutate(speciesSpecimens, specimenIndex, p.config.Mutate)
 		newSpecimens = append(newSpecimens, mutant)
 	}
-
-	// Add all the new specimens to species, creating any needed to house them.
-	for _, specimen := range newSpecimens {
-		p.AddSpecimen(specimen)
-	}
+	return newSpecimens
 }
 
 // prepareRandomSpecimenIndexes prepares species with indexes allowing random specimens to be picked.
@@ -130,6 +135,14 @@ func (p *generationPopulation) randomSpecimen(specimenCount int) (specimen Speci
 	return
 }
 
+// allSpecimens are all the specimens of the population, left in place.
+func (p *generationPopulation) allSpecimens() (specimens []Specimen) {
+	for i := range p.species {
+		specimens = append(specimens, p.species[i].Specimens...)
+	}
+	return specimens
+}
+
 // DumpSpecimens removes all the specimens from the population and returns them, fit for selection.
 func (p *generationPopulation) DumpSpecimens() []Specimen {
 	// Empty out the species of their specimens, but keep them there, they need to stick around for
@@ -158,10 +171,11 @@ func (p *generationPopulation) DumpSpecimensAsNeuralNets() []NeatNeuralNet {
 	return neuralNets
 }
 
-// WeightSpecies weights all the specimen scores by the size of their species.
+// WeightSpecies weights all the specimen scores by the size of their species. Each species is numbered from 1 for the
+// generation.
 func (p *generationPopulation) WeightSpecies() {
 	for i := range p.species {
-		p.species[i].WeightSpecies()
+		p.species[i].WeightSpecies(i + 1)
 	}
 }
 
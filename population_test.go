@@ -11,6 +11,22 @@ type PopulationSuite struct{}
 
 var _ = Suite(&PopulationSuite{})
 
+// populationTestInOut is a validated interface of two inputs and an output, for tests that grow neural nets.
+func populationTestInOut() NeuralNetInOut {
+	var inOut NeuralNetInOut = NeuralNetInOut{Inputs: []string{"in1", "in2"}, Outputs: []string{"out"}}
+	inOut.validate()
+	return inOut
+}
+
+// populationTestConfig keeps everyone in a single species, and can mutate neural nets grown from nothing.
+func populationTestConfig(populationSize int) ConfigPopulation {
+	return ConfigPopulation{
+		PopulationSize: populationSize,
+		Speciation:     ConfigSpeciation{Threshold: 1000.0, C1: 1.0, C2: 1.0, C3: 1.0},
+		Mutate:         ConfigMutate{AvailableNodeFunctions: []string{ACTIVATION_SIGMOID}, MaxAddConnectionAttempts: 5},
+	}
+}
+
 // Add the tests.
 
 func (s *PopulationSuite) Test_Population_AddSpecimen_NoSpeciesYet(c *C) {
@@ -164,3 +180,20 @@ func (s *PopulationSuite) Test_Population_AddSpecimen_NoMatchingSpecies(c *C) {
 	c.Assert(population, DeepEquals, expectedPopulation)
 
 }
+
+func (s *PopulationSuite) Test_Population_WeightSpecies(c *C) {
+	var population generationPopulation = generationPopulation{
+		species: []genSpecies{
+			genSpecies{Specimens: []Specimen{Specimen{Score: 1.0}, Specimen{Score: 2.0}}},
+			genSpecies{Specimens: []Specimen{Specimen{Score: 3.0}}},
+		},
+	}
+	population.WeightSpecies()
+
+	// Each specimen knows the size of its species and which one it is.
+	c.Check(population.DumpSpecimens(), DeepEquals, []Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 2, SpeciesId: 1},
+		Specimen{Score: 2.0, SpeciesMemberCount: 2, SpeciesId: 1},
+		Specimen{Score: 3.0, SpeciesMemberCount: 1, SpeciesId: 2},
+	})
+}
@@ -0,0 +1,80 @@
+package genetic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	// The protobuf wire types used.
+	// Reference: https://protobuf.dev/programming-guides/encoding/
+	_PROTOBUF_WIRE_VARINT           = 0
+	_PROTOBUF_WIRE_LENGTH_DELIMITED = 2
+)
+
+// protobufWriter writes a protobuf message by hand, one field at a time. Only the little bit of protobuf needed to write
+// ONNX models is here, so there is no dependency on a protobuf library or generated code.
+type protobufWriter struct {
+	buffer bytes.Buffer
+}
+
+// bytes is the message written so far.
+func (w *protobufWriter) bytes() []byte {
+	return w.buffer.Bytes()
+}
+
+// writeVarint writes a raw base 128 varint.
+func (w *protobufWriter) writeVarint(value uint64) {
+	for value >= 0x80 {
+		w.buffer.WriteByte(byte(value) | 0x80)
+		value >>= 7
+	}
+	w.buffer.WriteByte(byte(value))
+}
+
+// writeTag writes the start of a field.
+func (w *protobufWriter) writeTag(field int, wireType int) {
+	w.writeVarint(uint64(field)<<3 | uint64(wireType))
+}
+
+// writeInt writes an integer field (int32, int64, or enum). Negative values are written as ten byte varints like protobuf does.
+func (w *protobufWriter) writeInt(field int, value int64) {
+	w.writeTag(field, _PROTOBUF_WIRE_VARINT)
+	w.writeVarint(uint64(value))
+}
+
+// writeBytes writes a length-delimited field.
+func (w *protobufWriter) writeBytes(field int, value []byte) {
+	w.writeTag(field, _PROTOBUF_WIRE_LENGTH_DELIMITED)
+	w.writeVarint(uint64(len(value)))
+	w.buffer.Write(value)
+}
+
+// writeString writes a string field.
+func (w *protobufWriter) writeString(field int, value string) {
+	w.writeBytes(field, []byte(value))
+}
+
+// writeMessage writes an embedded message field.
+func (w *protobufWriter) writeMessage(field int, message *protobufWriter) {
+	w.writeBytes(field, message.bytes())
+}
+
+// writePackedDoubles writes a repeated double field in packed form.
+func (w *protobufWriter) writePackedDoubles(field int, values []float64) {
+	var packed []byte = make([]byte, 8*len(values))
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(packed[8*i:], math.Float64bits(value))
+	}
+	w.writeBytes(field, packed)
+}
+
+// writePackedInts writes a repeated integer field in packed form.
+func (w *protobufWriter) writePackedInts(field int, values []int64) {
+	var packed protobufWriter
+	for _, value := range values {
+		packed.writeVarint(uint64(value))
+	}
+	w.writeBytes(field, packed.bytes())
+}
@@ -0,0 +1,45 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type ProtobufSuite struct{}
+
+var _ = Suite(&ProtobufSuite{})
+
+// Add the tests.
+
+func (s *ProtobufSuite) Test_ProtobufWriter(c *C) {
+	var writer protobufWriter
+
+	// Examples from https://protobuf.dev/programming-guides/encoding/
+	writer.writeInt(1, 150)
+	c.Check(writer.bytes(), DeepEquals, []byte{0x08, 0x96, 0x01})
+
+	writer = protobufWriter{}
+	writer.writeString(2, "testing")
+	c.Check(writer.bytes(), DeepEquals, []byte{0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'})
+
+	writer = protobufWriter{}
+	writer.writePackedInts(4, []int64{3, 270, 86942})
+	c.Check(writer.bytes(), DeepEquals, []byte{0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05})
+
+	// Negative integers are ten bytes.
+	writer = protobufWriter{}
+	writer.writeInt(1, -1)
+	c.Check(writer.bytes(), DeepEquals, []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+
+	// Doubles are little endian.
+	writer = protobufWriter{}
+	writer.writePackedDoubles(10, []float64{1.0})
+	c.Check(writer.bytes(), DeepEquals, []byte{0x52, 0x08, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f})
+
+	// Embedded messages.
+	var message protobufWriter
+	message.writeInt(1, 150)
+	writer = protobufWriter{}
+	writer.writeMessage(3, &message)
+	c.Check(writer.bytes(), DeepEquals, []byte{0x1a, 0x03, 0x08, 0x96, 0x01})
+}
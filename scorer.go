@@ -15,3 +15,47 @@ type Scorer interface {
 	GenerationStart(generationNum uint64)
 	GenerationDetails() (json []byte)
 }
+
+// ScoreResult is everything a scorer can report about a single neural net.
+type ScoreResult struct {
+	Score    float64   // The score of the specimen.
+	Bonus    float64   // Added to the score for extra qualities of the neural net (e.g. a novelty search).
+	Outcomes []float64 // Multi-outcomes for sorters that use them (e.g. a hyper-volume indicator). nil if unused.
+	Behavior []float64 // What the neural net did, as numbers (e.g. where a robot ended up) for a novelty search. nil if unused.
+
+	// ConstraintViolation is how badly the neural net broke the scorer's hard constraints, 0.0 if it broke none (it is
+	// feasible). Feasible specimens always sort ahead of infeasible ones, which sort by their violation.
+	ConstraintViolation float64
+
+	// How many trials the scores are from, and how much the score varied between them (see ConfigScoring). Filled in when
+	// scoring, not by the scorer.
+	Trials        int
+	ScoreVariance float64
+}
+
+// ResultScorer is a Scorer that reports a full ScoreResult. If a scorer implements it, ScoreResult is called instead of Score.
+type ResultScorer interface {
+	Scorer
+
+	// ScoreResult scores a particular member of the population, the same as Score.
+	ScoreResult(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (result ScoreResult)
+}
+
+// scoreNeuralNet scores a single neural net with whichever scoring the scorer supports.
+func scoreNeuralNet(scorer Scorer, neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (result ScoreResult) {
+	var resultScorer ResultScorer
+	var ok bool
+	if resultScorer, ok = scorer.(ResultScorer); ok {
+		return resultScorer.ScoreResult(neuralNet, population, neuralNetIndex)
+	}
+	result.Score, result.Bonus, result.Outcomes = scorer.Score(neuralNet, population, neuralNetIndex)
+	return result
+}
+
+// scoreNeuralNets scores every neural net of the population, one at a time.
+func scoreNeuralNets(scorer Scorer, neuralNets []NeatNeuralNet) (results []ScoreResult) {
+	for i, neuralNet := range neuralNets {
+		results = append(results, scoreNeuralNet(scorer, neuralNet, neuralNets, i))
+	}
+	return results
+}
@@ -0,0 +1,237 @@
+package genetic
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// How the trials of a specimen become a single score.
+	SCORING_AGGREGATION_MEAN         = "mean"         // The average of the trials (the default).
+	SCORING_AGGREGATION_MEDIAN       = "median"       // The middle trial, ignoring how extreme the luckiest and unluckiest were.
+	SCORING_AGGREGATION_MIN          = "min"          // The lowest trial, the worst case. Only when the sorter seeks higher values.
+	SCORING_AGGREGATION_TRIMMED_MEAN = "trimmed_mean" // The average of the trials, less the highest and lowest few.
+)
+
+// SeedableScorer is a Scorer whose scores depend on randomness (e.g. shuffled cards). If a scorer implements it, it is
+// seeded before each trial. Every neural net of a trial is scored with the same seed, so they face the same luck.
+type SeedableScorer interface {
+	Scorer
+
+	// Seed seeds the scorer's randomness for the next trial.
+	Seed(seed int64)
+}
+
+// validAggregationOrPanic panics if the lowest trial would be the luckiest rather than the worst, because the sorter seeks
+// lower values (of the score, or of the outcomes it orders by). Lucky specimens would survive, which is what scoring over
+// several trials is meant to stop.
+func validAggregationOrPanic(sorter Sorter, config ConfigScoring) {
+	if config.Aggregation != SCORING_AGGREGATION_MIN {
+		return
+	}
+	var outcomes outcomeSorter
+	var ok bool
+	if outcomes, ok = sorter.(outcomeSorter); ok {
+		if len(outcomes.minimizedOutcomes()) > 0 {
+			log.Panicf("Scoring Aggregation 'min' keeps the luckiest trial of outcomes %v, which sorter %s minimizes", outcomes.minimizedOutcomes(), typeNameOf(sorter))
+		}
+		return
+	}
+	if !sorter.IsMaximize() {
+		log.Panicf("Scoring Aggregation 'min' keeps the luckiest trial, sorter %s minimizes", typeNameOf(sorter))
+	}
+}
+
+// scoreNeuralNetTrials scores every neural net of the population over several trials, each with its own seed, and
+// aggregates the trials into a single result for each.
+func scoreNeuralNetTrials(scorer Scorer, neuralNets []NeatNeuralNet, config ConfigScoring) (results []ScoreResult) {
+	var trialCount int = config.Trials
+	if trialCount < 1 {
+		trialCount = 1
+	}
+
+	// Score every trial.
+	var seedableScorer SeedableScorer
+	var isSeedable bool
+	seedableScorer, isSeedable = scorer.(SeedableScorer)
+	var trials [][]ScoreResult = make([][]ScoreResult, len(neuralNets))
+	for t := 0; t < trialCount; t++ {
+		if isSeedable {
+			seedableScorer.Seed(rand.Int63())
+		}
+		for i, result := range scoreNeuralNets(scorer, neuralNets) {
+			trials[i] = append(trials[i], result)
+		}
+	}
+
+	// Each neural net's trials become a single result.
+	for i := range neuralNets {
+		results = append(results, aggregateTrials(trials[i], config))
+	}
+	return results
+}
+
+// scoreSpecimens scores the specimens in as many trials as configured, and makes a newly scored specimen of each. Each
+// keeps its age and is a scoring older. If configured, survivors average their scores over their lifetime. If novelty is
+// searched for, it is measured across all the specimens.
+func scoreSpecimens(scorer Scorer, specimens []Specimen, config ConfigScoring, noveltySearch *NoveltySearch) (scored []Specimen) {
+	var neuralNets []NeatNeuralNet
+	for _, specimen := range specimens {
+		neuralNets = append(neuralNets, specimen.NeuralNet)
+	}
+
+	// Score each neural net, one at a time, in as many trials as configured.
+	//
+	//  score is the score for the neural net
+	//  bonus is decided by meta-decisions (e.g. novelty search), 0.0 if nothing
+	//  outcomes are for use with multi-outcome selectors (e.g. hyper-volume indicator), null otherwise
+	//  behavior is for novelty searches, null otherwise
+	var results []ScoreResult = scoreNeuralNetTrials(scorer, neuralNets, config)
+
+	// Novelty can only be known once every neural net has behaved.
+	var novelty []float64
+	if noveltySearch != nil {
+		novelty = noveltySearch.Apply(results)
+	}
+
+	// Bundle each neural net with its scores to make a specimen.
+	for i, specimen := range specimens {
+		var result Specimen = newScoredSpecimen(specimen.NeuralNet, results[i])
+		result.Age = specimen.Age
+		result.Evaluations = specimen.Evaluations + 1
+		if config.LifetimeAverage {
+			result = averageLifetime(result, specimen)
+		}
+		if novelty != nil {
+			result.Novelty = novelty[i]
+		}
+		scored = append(scored, result)
+	}
+	return scored
+}
+
+// aggregateTrials makes a single result from the trials of a neural net. The score and outcomes are aggregated as
+// configured, the bonus and behavior are averaged, and the constraint violation is the worst of any trial.
+func aggregateTrials(trials []ScoreResult, config ConfigScoring) (result ScoreResult) {
+	var scores []float64
+	var bonuses []float64
+	for _, trial := range trials {
+		scores = append(scores, trial.Score)
+		bonuses = append(bonuses, trial.Bonus)
+		if trial.ConstraintViolation > result.ConstraintViolation {
+			result.ConstraintViolation = trial.ConstraintViolation
+		}
+	}
+	result.Score = aggregateValues(scores, config)
+	result.Bonus = meanOf(bonuses)
+	result.Outcomes = aggregateVectors(trials, func(trial ScoreResult) []float64 { return trial.Outcomes }, func(values []float64) float64 { return aggregateValues(values, config) })
+	result.Behavior = aggregateVectors(trials, func(trial ScoreResult) []float64 { return trial.Behavior }, meanOf)
+	result.Trials = len(trials)
+	result.ScoreVariance = varianceOf(scores)
+	return result
+}
+
+// aggregateVectors aggregates a vector of the trials (e.g. outcomes) value by value. nil if the trials have none.
+func aggregateVectors(trials []ScoreResult, vectorOf func(ScoreResult) []float64, aggregate func([]float64) float64) (aggregated []float64) {
+	var length int = len(vectorOf(trials[0]))
+	for v := 0; v < length; v++ {
+		var values []float64
+		for _, trial := range trials {
+			values = append(values, vectorOf(trial)[v])
+		}
+		aggregated = append(aggregated, aggregate(values))
+	}
+	return aggregated
+}
+
+// aggregateValues makes a single value from the values of the trials, as configured.
+func aggregateValues(values []float64, config ConfigScoring) float64 {
+	// Sort a copy, leaving the trials in their order.
+	var sorted []float64 = append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	switch config.Aggregation {
+	case "", SCORING_AGGREGATION_MEAN:
+		return meanOf(sorted)
+	case SCORING_AGGREGATION_MEDIAN:
+		var middle int = len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[middle-1] + sorted[middle]) / 2.0
+		}
+		return sorted[middle]
+	case SCORING_AGGREGATION_MIN:
+		return sorted[0]
+	case SCORING_AGGREGATION_TRIMMED_MEAN:
+		var trim int = int(math.Floor(config.TrimFraction * float64(len(sorted))))
+		return meanOf(sorted[trim : len(sorted)-trim])
+	default:
+		log.Panicf("Unknown scoring Aggregation: '%s'", config.Aggregation)
+	}
+	return 0.0
+}
+
+// meanOf is the average of the values.
+func meanOf(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// varianceOf is the (population) variance of the values.
+func varianceOf(values []float64) (variance float64) {
+	var mean float64 = meanOf(values)
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return variance / float64(len(values))
+}
+
+// averageLifetime averages a survivor's new score with the score of its earlier generations, weighted by how many trials
+// each is from. The outcomes are averaged the same way. Everything else is this generation's. A specimen with no earlier
+// trials is unchanged.
+func averageLifetime(specimen Specimen, earlier Specimen) Specimen {
+	if earlier.Trials == 0 || specimen.Trials == 0 {
+		return specimen
+	}
+
+	var earlierWeight float64 = float64(earlier.Trials)
+	var laterWeight float64 = float64(specimen.Trials)
+	var totalWeight float64 = earlierWeight + laterWeight
+	var mean float64 = (earlier.Score*earlierWeight + specimen.Score*laterWeight) / totalWeight
+
+	// The variance of all the trials together, from the variance of each group and how far each group is from the mean.
+	var earlierSpread float64 = earlier.ScoreVariance + (earlier.Score-mean)*(earlier.Score-mean)
+	var laterSpread float64 = specimen.ScoreVariance + (specimen.Score-mean)*(specimen.Score-mean)
+	specimen.ScoreVariance = (earlierSpread*earlierWeight + laterSpread*laterWeight) / totalWeight
+
+	if len(earlier.Outcomes) == len(specimen.Outcomes) {
+		var outcomes []float64
+		for i := range specimen.Outcomes {
+			outcomes = append(outcomes, (earlier.Outcomes[i]*earlierWeight+specimen.Outcomes[i]*laterWeight)/totalWeight)
+		}
+		specimen.Outcomes = outcomes
+	}
+
+	specimen.Score = mean
+	specimen.Trials = earlier.Trials + specimen.Trials
+	return specimen
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigScoring) validOrPanic() {
+	if c.Trials < 0 {
+		log.Panicf("Scoring Trials cannot be negative: %d", c.Trials)
+	}
+	switch c.Aggregation {
+	case "", SCORING_AGGREGATION_MEAN, SCORING_AGGREGATION_MEDIAN, SCORING_AGGREGATION_MIN, SCORING_AGGREGATION_TRIMMED_MEAN:
+	default:
+		log.Panicf("Unknown scoring Aggregation: '%s'", c.Aggregation)
+	}
+	if c.TrimFraction < 0.0 || c.TrimFraction >= 0.5 {
+		log.Panicf("Scoring TrimFraction must be 0.0 up to (not including) 0.5: %f", c.TrimFraction)
+	}
+}
@@ -0,0 +1,170 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type ScoringSuite struct{}
+
+var _ = Suite(&ScoringSuite{})
+
+// trialTestScorer scores each trial from its list, plus the index of the neural net. It remembers its seeds.
+type trialTestScorer struct {
+	trialScores []float64
+	seeds       []int64
+}
+
+func (s *trialTestScorer) Seed(seed int64) { s.seeds = append(s.seeds, seed) }
+func (s *trialTestScorer) Score(neuralNet NeatNeuralNet, population []NeatNeuralNet, neuralNetIndex int) (score float64, bonus float64, outcomes []float64) {
+	var trialScore float64 = s.trialScores[len(s.seeds)-1]
+	return trialScore + float64(neuralNetIndex), 1.0, []float64{trialScore, -trialScore}
+}
+func (s *trialTestScorer) GenerationStart(generationNum uint64) {}
+func (s *trialTestScorer) GenerationDetails() (json []byte)     { return nil }
+
+// Add the tests.
+
+func (s *ScoringSuite) Test_ScoreNeuralNetTrials(c *C) {
+	var neuralNets []NeatNeuralNet = []NeatNeuralNet{NeatNeuralNet{}, NeatNeuralNet{}}
+	var scorer *trialTestScorer = &trialTestScorer{trialScores: []float64{1.0, 2.0, 6.0}}
+
+	// Each trial has its own seed, shared by every neural net.
+	var results []ScoreResult = scoreNeuralNetTrials(scorer, neuralNets, ConfigScoring{Trials: 3})
+	c.Check(len(scorer.seeds), Equals, 3)
+	c.Check(scorer.seeds[0] != scorer.seeds[1], Equals, true)
+	c.Check(results, DeepEquals, []ScoreResult{
+		ScoreResult{Score: 3.0, Bonus: 1.0, Outcomes: []float64{3.0, -3.0}, Trials: 3, ScoreVariance: 14.0 / 3.0},
+		ScoreResult{Score: 4.0, Bonus: 1.0, Outcomes: []float64{3.0, -3.0}, Trials: 3, ScoreVariance: 14.0 / 3.0},
+	})
+
+	// No trials configured is a single trial.
+	scorer = &trialTestScorer{trialScores: []float64{5.0}}
+	results = scoreNeuralNetTrials(scorer, neuralNets, ConfigScoring{})
+	c.Check(results[1], DeepEquals, ScoreResult{Score: 6.0, Bonus: 1.0, Outcomes: []float64{5.0, -5.0}, Trials: 1})
+
+	// Scorers without seeds are scored the same.
+	neuralNets = []NeatNeuralNet{NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.5}}}}}
+	results = scoreNeuralNetTrials(&behaviorTestScorer{}, neuralNets, ConfigScoring{Trials: 2})
+	c.Check(results, DeepEquals, []ScoreResult{ScoreResult{Score: 0.0, Behavior: []float64{0.5}, Trials: 2}})
+}
+
+func (s *ScoringSuite) Test_ScoreSpecimens(c *C) {
+	var specimens []Specimen = []Specimen{
+		Specimen{NeuralNet: NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.5}}}}, Score: 6.0, Age: 2, Evaluations: 3, Trials: 1},
+		Specimen{NeuralNet: NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Weight: 0.9}}}}},
+	}
+
+	// Everyone is scored again, keeping their age and a scoring older. The behavior test scorer scores by index.
+	var scored []Specimen = scoreSpecimens(&behaviorTestScorer{}, specimens, ConfigScoring{}, nil)
+	c.Check(len(scored), Equals, 2)
+	c.Check(scored[0].Score, Equals, 0.0)
+	c.Check(scored[0].Age, Equals, 2)
+	c.Check(scored[0].Evaluations, Equals, 4)
+	c.Check(scored[0].Behavior, DeepEquals, []float64{0.5})
+	c.Check(scored[1].Score, Equals, 1.0)
+	c.Check(scored[1].Evaluations, Equals, 1)
+
+	// Survivors average over their lifetime, if configured.
+	scored = scoreSpecimens(&behaviorTestScorer{}, specimens, ConfigScoring{LifetimeAverage: true}, nil)
+	c.Check(scored[0].Score, Equals, 3.0)
+	c.Check(scored[0].Trials, Equals, 2)
+	c.Check(scored[1].Score, Equals, 1.0)
+
+	// Novelty is measured across everyone.
+	scored = scoreSpecimens(&behaviorTestScorer{}, specimens, ConfigScoring{}, NewNoveltySearch(ConfigNoveltySearch{K: 1, ArchivePolicy: NOVELTY_ARCHIVE_THRESHOLD, ArchiveThreshold: 100.0, Target: NOVELTY_TARGET_BONUS}))
+	c.Check(isNearlyEqual(scored[0].Novelty, 0.4), Equals, true)
+	c.Check(isNearlyEqual(scored[1].Novelty, 0.4), Equals, true)
+}
+
+func (s *ScoringSuite) Test_AggregateTrials(c *C) {
+	var trials []ScoreResult = []ScoreResult{
+		ScoreResult{Score: 4.0, Outcomes: []float64{4.0}, Behavior: []float64{1.0}},
+		ScoreResult{Score: 1.0, Outcomes: []float64{1.0}, Behavior: []float64{2.0}, ConstraintViolation: 2.0},
+		ScoreResult{Score: 9.0, Outcomes: []float64{9.0}, Behavior: []float64{6.0}, ConstraintViolation: 1.0},
+	}
+
+	// Outcomes are aggregated like the score, behaviors averaged, and the violation is the worst.
+	var result ScoreResult = aggregateTrials(trials, ConfigScoring{Aggregation: SCORING_AGGREGATION_MEDIAN})
+	c.Check(isNearlyEqual(result.ScoreVariance, 98.0/9.0), Equals, true)
+	result.ScoreVariance = 0.0
+	c.Check(result, DeepEquals, ScoreResult{
+		Score:               4.0,
+		Outcomes:            []float64{4.0},
+		Behavior:            []float64{3.0},
+		ConstraintViolation: 2.0,
+		Trials:              3,
+	})
+}
+
+func (s *ScoringSuite) Test_AggregateValues(c *C) {
+	var values []float64 = []float64{3.0, 100.0, 1.0, 2.0, -50.0, 4.0}
+
+	c.Check(aggregateValues(values, ConfigScoring{}), Equals, 10.0)
+	c.Check(aggregateValues(values, ConfigScoring{Aggregation: SCORING_AGGREGATION_MEAN}), Equals, 10.0)
+	c.Check(aggregateValues(values, ConfigScoring{Aggregation: SCORING_AGGREGATION_MEDIAN}), Equals, 2.5)
+	c.Check(aggregateValues(values[:5], ConfigScoring{Aggregation: SCORING_AGGREGATION_MEDIAN}), Equals, 2.0)
+	c.Check(aggregateValues(values, ConfigScoring{Aggregation: SCORING_AGGREGATION_MIN}), Equals, -50.0)
+	c.Check(aggregateValues(values, ConfigScoring{Aggregation: SCORING_AGGREGATION_TRIMMED_MEAN, TrimFraction: 0.2}), Equals, 2.5)
+	c.Check(aggregateValues(values, ConfigScoring{Aggregation: SCORING_AGGREGATION_TRIMMED_MEAN, TrimFraction: 0.1}), Equals, 10.0) // Too few to trim.
+
+	// The values are left in their order.
+	c.Check(values, DeepEquals, []float64{3.0, 100.0, 1.0, 2.0, -50.0, 4.0})
+
+	c.Check(func() { aggregateValues(values, ConfigScoring{Aggregation: "max"}) }, Panics, "Unknown scoring Aggregation: 'max'")
+}
+
+func (s *ScoringSuite) Test_ValidAggregationOrPanic(c *C) {
+	var worst ConfigScoring = ConfigScoring{Trials: 3, Aggregation: SCORING_AGGREGATION_MIN}
+
+	// The lowest trial is the worst when seeking higher values.
+	validAggregationOrPanic(NewSorterSimpleMaximize(), worst)
+	validAggregationOrPanic(&SorterNSGA2{Maximize: []bool{true, true}}, worst)
+	validAggregationOrPanic(NewSorterSimpleMinimize(), ConfigScoring{Trials: 3, Aggregation: SCORING_AGGREGATION_MEDIAN})
+
+	// Seeking lower values, it is the luckiest.
+	c.Check(func() { validAggregationOrPanic(NewSorterSimpleMinimize(), worst) }, Panics, "Scoring Aggregation 'min' keeps the luckiest trial, sorter genetic.sorterSimple minimizes")
+	c.Check(func() {
+		validAggregationOrPanic(&SorterWeightedSum{Weights: []float64{1.0, 1.0}, Maximize: []bool{true, false}}, worst)
+	}, Panics, "Scoring Aggregation 'min' keeps the luckiest trial of outcomes [1], which sorter genetic.SorterWeightedSum minimizes")
+
+	// Only the outcomes an outcome sorter orders by count, not which way its score runs.
+	validAggregationOrPanic(&SorterLexicographic{Objectives: []LexicographicObjective{LexicographicObjective{Outcome: 0, Maximize: true}}}, worst)
+	c.Check(func() {
+		validAggregationOrPanic(&SorterLexicographic{Objectives: []LexicographicObjective{LexicographicObjective{Outcome: 2, Maximize: false}}}, worst)
+	}, Panics, "Scoring Aggregation 'min' keeps the luckiest trial of outcomes [2], which sorter genetic.SorterLexicographic minimizes")
+}
+
+func (s *ScoringSuite) Test_AverageLifetime(c *C) {
+	var earlier Specimen = Specimen{Score: 2.0, Outcomes: []float64{2.0}, Trials: 2, ScoreVariance: 1.0}
+	var specimen Specimen = Specimen{Score: 5.0, Outcomes: []float64{8.0}, Bonus: 3.0, Trials: 1}
+
+	// Weighted by trials. The variance is of all three trials (1.0, 3.0, 5.0).
+	var averaged Specimen = averageLifetime(specimen, earlier)
+	c.Check(averaged.Score, Equals, 3.0)
+	c.Check(averaged.Outcomes, DeepEquals, []float64{4.0})
+	c.Check(averaged.Bonus, Equals, 3.0)
+	c.Check(averaged.Trials, Equals, 3)
+	c.Check(isNearlyEqual(averaged.ScoreVariance, 8.0/3.0), Equals, true)
+
+	// New specimens have nothing to average with.
+	c.Check(averageLifetime(specimen, Specimen{}), DeepEquals, specimen)
+}
+
+func (s *ScoringSuite) Test_ConfigScoring_ValidOrPanic(c *C) {
+	var config ConfigScoring
+
+	config = ConfigScoring{}
+	config.validOrPanic() // Valid.
+	config = ConfigScoring{Trials: 5, Aggregation: SCORING_AGGREGATION_TRIMMED_MEAN, TrimFraction: 0.2, LifetimeAverage: true}
+	config.validOrPanic() // Valid.
+
+	config = ConfigScoring{Trials: -1}
+	c.Check(func() { config.validOrPanic() }, Panics, "Scoring Trials cannot be negative: -1")
+
+	config = ConfigScoring{Aggregation: "max"}
+	c.Check(func() { config.validOrPanic() }, Panics, "Unknown scoring Aggregation: 'max'")
+
+	config = ConfigScoring{TrimFraction: 0.5}
+	c.Check(func() { config.validOrPanic() }, Panics, "Scoring TrimFraction must be 0.0 up to (not including) 0.5: 0.500000")
+}
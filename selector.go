@@ -1,8 +1,53 @@
 package genetic
 
+import (
+	"log"
+)
+
 // Selector selects which members of a population to keep for the next generation.
 type Selector interface {
 
 	// Pick the population members to continue on to the next generation.
 	Select(specimens []Specimen) (fittest []Specimen)
 }
+
+// minimizingSelector is a Selector told which way its selection scores run, rather than just using the sorted order
+// (e.g. roulette). It must agree with the sorter.
+type minimizingSelector interface {
+	Selector
+
+	// isMinimize is true if the selector treats lower selection scores as fitter.
+	isMinimize() bool
+}
+
+// validSelectionOrPanic panics if a selector (or any stage of a chain) treats selection scores as running the other way
+// from how the sorter gives them, which would silently keep the least fit.
+func validSelectionOrPanic(sorter Sorter, selector Selector) {
+	var isLowerFitter bool = isLowerSelectionFitter(sorter)
+
+	// A chain's stages are each their own selector.
+	var selectors []Selector = []Selector{selector}
+	var chain *SelectorChain
+	var ok bool
+	if chain, ok = selector.(*SelectorChain); ok {
+		selectors = nil
+		for _, stage := range chain.Stages {
+			selectors = append(selectors, stage.Selector)
+		}
+	}
+
+	for _, selector := range selectors {
+		var minimizing minimizingSelector
+		if minimizing, ok = selector.(minimizingSelector); ok && minimizing.isMinimize() != isLowerFitter {
+			log.Panicf("Selector %s has Minimize %t but sorter %s gives fitter specimens %s selection scores", typeNameOf(selector), minimizing.isMinimize(), typeNameOf(sorter), selectionDirectionOf(isLowerFitter))
+		}
+	}
+}
+
+// selectionDirectionOf describes which way fitter selection scores run.
+func selectionDirectionOf(isLowerFitter bool) string {
+	if isLowerFitter {
+		return "lower"
+	}
+	return "higher"
+}
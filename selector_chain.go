@@ -0,0 +1,251 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+)
+
+const (
+	// The selectors a chain can name for its stages.
+	SELECTOR_ELITISM              = "elitism"
+	SELECTOR_TOURNAMENT           = "tournament"
+	SELECTOR_ROULETTE             = "roulette"
+	SELECTOR_RANK                 = "rank"
+	SELECTOR_STOCHASTIC_UNIVERSAL = "stochastic_universal"
+	SELECTOR_SPECIES              = "species"
+	SELECTOR_RANDOM               = "random"
+)
+
+// SelectorFactory creates a selector from its json configuration, as a stage of a chain.
+type SelectorFactory func(config json.RawMessage) (Selector, error)
+
+// selectorFactories are the selectors a chain can use, by name.
+var selectorFactories map[string]SelectorFactory = map[string]SelectorFactory{
+	SELECTOR_ELITISM: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorElitism
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		return &selector, error(nil)
+	},
+	SELECTOR_TOURNAMENT: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorTournament
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+	SELECTOR_ROULETTE: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorRoulette
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+	SELECTOR_RANK: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorRank
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+	SELECTOR_STOCHASTIC_UNIVERSAL: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorStochasticUniversal
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+	SELECTOR_SPECIES: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorSpecies
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+	SELECTOR_RANDOM: func(config json.RawMessage) (Selector, error) {
+		var err error
+		var selector SelectorRandom
+		if err = json.Unmarshal(config, &selector); err != nil {
+			return nil, err
+		}
+		selector.validOrPanic()
+		return &selector, error(nil)
+	},
+}
+
+// RegisterSelectorFactory makes a selector available to chains by name, for selectors outside this package.
+func RegisterSelectorFactory(name string, factory SelectorFactory) {
+	if name == "" || factory == nil {
+		log.Panicf("Selector factory needs a name and a function: '%s'", name)
+	}
+	selectorFactories[name] = factory
+}
+
+// SelectorChain runs selectors one after another, each on the specimens the stages before it didn't keep. For example,
+// keep the top 5 unconditionally, then tournament select 45 more from the rest, then 5 random survivors for diversity:
+//
+//	{"Stages": [
+//	  {"Type": "elitism", "Quota": 5, "Config": {"KeepCount": 5}},
+//	  {"Type": "tournament", "Quota": 45, "Config": {"KeepCount": 45, "Contenders": 3}},
+//	  {"Type": "random", "Quota": 5, "Config": {"KeepCount": 5}}
+//	]}
+//
+// A stage's selector may pick the same specimen more than once (e.g. with replacement), or pick specimens with the same
+// genome as one already kept. Those are duplicates and are dropped, so a stage can keep fewer than its quota.
+type SelectorChain struct {
+	Stages []SelectorStage // The selectors, in the order they run.
+}
+
+// SelectorStage is a single selector of a chain.
+type SelectorStage struct {
+	Type     string          // The name of the selector (e.g. "tournament").
+	Quota    int             // The most specimens this stage keeps. Its selector should keep at least this many. If 0, no limit.
+	Config   json.RawMessage // The json configuration of the selector, the same as its own config file.
+	Selector Selector        `json:"-"` // The selector itself, created from the type and config when loaded (or set directly).
+}
+
+// Select runs each stage on what is left of the population.
+func (s *SelectorChain) Select(specimens []Specimen) (fittest []Specimen) {
+
+	// Work out each genome's key once, they are looked up by every stage.
+	var keys genomeKeyCache = genomeKeyCache{}
+	var remainingKeys []string
+	for _, specimen := range specimens {
+		remainingKeys = append(remainingKeys, keys.keyOf(specimen))
+	}
+
+	var remaining []Specimen = specimens
+	var isKept map[string]bool = map[string]bool{}
+	for _, stage := range s.Stages {
+		if len(remaining) == 0 {
+			break
+		}
+
+		// Keep this stage's picks, up to the quota, skipping duplicates.
+		var stageCount int
+		for _, specimen := range stage.Selector.Select(remaining) {
+			if stage.Quota > 0 && stageCount >= stage.Quota {
+				break
+			}
+			var key string = keys.keyOf(specimen)
+			if !isKept[key] {
+				isKept[key] = true
+				fittest = append(fittest, specimen)
+				stageCount++
+			}
+		}
+
+		// The next stage gets who is left, still in sorted order.
+		var stillRemaining []Specimen
+		var stillRemainingKeys []string
+		for i, specimen := range remaining {
+			if !isKept[remainingKeys[i]] {
+				stillRemaining = append(stillRemaining, specimen)
+				stillRemainingKeys = append(stillRemainingKeys, remainingKeys[i])
+			}
+		}
+		remaining = stillRemaining
+		remainingKeys = stillRemainingKeys
+	}
+	return fittest
+}
+
+// LoadSelectorChainConfig loads the json filename as a new configuration, creating the selector of each stage.
+func LoadSelectorChainConfig(filename string) (SelectorChain, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorChain
+
+	log.Printf("Loading chain selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorChain{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorChain{}, err
+	}
+	if err = selector.createSelectors(); err != nil {
+		return SelectorChain{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// createSelectors creates the selector of each stage from its type and config.
+func (s *SelectorChain) createSelectors() (err error) {
+	for i := range s.Stages {
+		var factory SelectorFactory
+		var ok bool
+		if factory, ok = selectorFactories[s.Stages[i].Type]; !ok {
+			log.Panicf("Unknown selector Type: '%s'", s.Stages[i].Type)
+		}
+
+		// No config at all is an empty config.
+		var config json.RawMessage = s.Stages[i].Config
+		if len(config) == 0 {
+			config = json.RawMessage("{}")
+		}
+		if s.Stages[i].Selector, err = factory(config); err != nil {
+			return err
+		}
+	}
+	return error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorChain) validOrPanic() {
+	if len(s.Stages) == 0 {
+		log.Panic("Stages must have values")
+	}
+	for _, stage := range s.Stages {
+		if stage.Selector == nil {
+			log.Panicf("Stage '%s' has no Selector", stage.Type)
+		}
+		if stage.Quota < 0 {
+			log.Panicf("Stage '%s' Quota cannot be negative: %d", stage.Type, stage.Quota)
+		}
+	}
+}
+
+// genomeKeyOf identifies a specimen by its genome, so the same specimen picked twice (or a copy of it) can be spotted.
+func genomeKeyOf(specimen Specimen) string {
+	var err error
+	var bytes []byte
+	if bytes, err = json.Marshal(specimen.NeuralNet.Genome); err != nil {
+		log.Panic(err)
+	}
+	return md5Of(string(bytes))
+}
+
+// genomeKeyCache remembers the genome keys of specimens, so each is only worked out once. Specimens are handed around as
+// copies, so a specimen is known by the genes its copies share.
+type genomeKeyCache map[*neatGene]string
+
+// keyOf is the genome key of the specimen, worked out the first time its genes are seen.
+func (c genomeKeyCache) keyOf(specimen Specimen) string {
+	if len(specimen.NeuralNet.Genome.Genes) == 0 {
+		return genomeKeyOf(specimen)
+	}
+	var genes *neatGene = &specimen.NeuralNet.Genome.Genes[0]
+	var key string
+	var ok bool
+	if key, ok = c[genes]; !ok {
+		key = genomeKeyOf(specimen)
+		c[genes] = key
+	}
+	return key
+}
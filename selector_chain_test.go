@@ -0,0 +1,112 @@
+package genetic
+
+import (
+	"encoding/json"
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+)
+
+// Create a suite.
+type SelectorChainSuite struct{}
+
+var _ = Suite(&SelectorChainSuite{})
+
+// selectorChainTestSpecimens are sorted specimens, each with its own genome.
+func selectorChainTestSpecimens() []Specimen {
+	var specimens []Specimen
+	for i := 0; i < 10; i++ {
+		specimens = append(specimens, Specimen{
+			NeuralNet:      NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: uint64(i + 1)}}}},
+			Score:          float64(i),
+			SelectionScore: float64(10 - i),
+		})
+	}
+	return specimens
+}
+
+// Add the tests.
+
+func (s *SelectorChainSuite) Test_Select(c *C) {
+	rand.Seed(1)
+
+	// The top three, then two random from the rest.
+	var selector SelectorChain = SelectorChain{Stages: []SelectorStage{
+		SelectorStage{Type: SELECTOR_ELITISM, Quota: 3, Selector: &SelectorElitism{KeepCount: 3}},
+		SelectorStage{Type: SELECTOR_RANDOM, Quota: 2, Selector: &SelectorRandom{KeepCount: 2}},
+	}}
+	for i := 0; i < 10; i++ {
+		var fittest []Specimen = selector.Select(selectorChainTestSpecimens())
+		c.Assert(len(fittest), Equals, 5)
+		c.Check(selectedScores(fittest[:3]), DeepEquals, []float64{0.0, 1.0, 2.0})
+		c.Check(fittest[3].Score >= 3.0, Equals, true)
+		c.Check(fittest[4].Score > fittest[3].Score, Equals, true)
+	}
+
+	// A stage that picks the same specimen again and again only keeps it once. The quota limits a stage.
+	selector = SelectorChain{Stages: []SelectorStage{
+		SelectorStage{Type: SELECTOR_ELITISM, Quota: 1, Selector: &SelectorElitism{KeepCount: 5}},
+		SelectorStage{Type: SELECTOR_RANK, Selector: &SelectorRank{KeepCount: 5, Ranking: RANK_EXPONENTIAL, Base: 0.000001, WithReplacement: true}},
+	}}
+	c.Check(selectedScores(selector.Select(selectorChainTestSpecimens())), DeepEquals, []float64{0.0, 1.0})
+
+	// Once everyone is kept, the later stages have nothing to do.
+	selector = SelectorChain{Stages: []SelectorStage{
+		SelectorStage{Type: SELECTOR_ELITISM, Selector: &SelectorElitism{KeepCount: 20}},
+		SelectorStage{Type: SELECTOR_RANDOM, Selector: &SelectorRandom{KeepCount: 2}},
+	}}
+	c.Check(selector.Select(selectorChainTestSpecimens()), DeepEquals, selectorChainTestSpecimens())
+}
+
+func (s *SelectorChainSuite) Test_LoadSelectorChainConfig(c *C) {
+	var filename string = filepath.Join(c.MkDir(), "chain.json")
+	c.Assert(ioutil.WriteFile(filename, []byte(`{"Stages": [
+		{"Type": "elitism", "Quota": 5, "Config": {"KeepCount": 5}},
+		{"Type": "tournament", "Quota": 45, "Config": {"KeepCount": 45, "Contenders": 3}},
+		{"Type": "random", "Quota": 5, "Config": {"KeepCount": 5}}
+	]}`), 0644), IsNil)
+
+	var selector SelectorChain
+	var err error
+	selector, err = LoadSelectorChainConfig(filename)
+	c.Assert(err, IsNil)
+	c.Assert(len(selector.Stages), Equals, 3)
+	c.Check(selector.Stages[0].Selector, DeepEquals, &SelectorElitism{KeepCount: 5})
+	c.Check(selector.Stages[1].Selector, DeepEquals, &SelectorTournament{KeepCount: 45, Contenders: 3})
+	c.Check(selector.Stages[2].Selector, DeepEquals, &SelectorRandom{KeepCount: 5})
+
+	// Bad json in a stage's config is an error.
+	c.Assert(ioutil.WriteFile(filename, []byte(`{"Stages": [{"Type": "elitism", "Config": {"KeepCount": "five"}}]}`), 0644), IsNil)
+	_, err = LoadSelectorChainConfig(filename)
+	c.Check(err, NotNil)
+}
+
+func (s *SelectorChainSuite) Test_CreateSelectors(c *C) {
+	// No config is an empty config, which the selector may not accept.
+	var selector SelectorChain = SelectorChain{Stages: []SelectorStage{SelectorStage{Type: SELECTOR_RANDOM}}}
+	c.Check(func() { selector.createSelectors() }, Panics, "KeepCount must be one or more: 0")
+
+	selector = SelectorChain{Stages: []SelectorStage{SelectorStage{Type: "lottery"}}}
+	c.Check(func() { selector.createSelectors() }, Panics, "Unknown selector Type: 'lottery'")
+
+	// Selectors from elsewhere can be registered.
+	RegisterSelectorFactory("test_everyone", func(config json.RawMessage) (Selector, error) { return &SelectorElitism{KeepCount: 1000}, nil })
+	selector = SelectorChain{Stages: []SelectorStage{SelectorStage{Type: "test_everyone"}}}
+	c.Assert(selector.createSelectors(), IsNil)
+	c.Check(selector.Stages[0].Selector, DeepEquals, &SelectorElitism{KeepCount: 1000})
+}
+
+func (s *SelectorChainSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorChain = SelectorChain{Stages: []SelectorStage{SelectorStage{Type: SELECTOR_RANDOM, Selector: &SelectorRandom{KeepCount: 1}}}}
+	selector.validOrPanic() // Valid.
+
+	selector.Stages[0].Quota = -1
+	c.Check(func() { selector.validOrPanic() }, Panics, "Stage 'random' Quota cannot be negative: -1")
+
+	selector.Stages[0].Selector = nil
+	c.Check(func() { selector.validOrPanic() }, Panics, "Stage 'random' has no Selector")
+
+	selector.Stages = nil
+	c.Check(func() { selector.validOrPanic() }, Panics, "Stages must have values")
+}
@@ -14,6 +14,11 @@ type SelectorElitism struct {
 
 // Select the N fittest members of a population.
 func (s *SelectorElitism) Select(specimens []Specimen) (fittest []Specimen) {
+	// If we are keeping the entire population, just pass it through.
+	if s.KeepCount >= len(specimens) {
+		return specimens
+	}
+
 	// The specimens are sorted from fittest to least fit.
 	return specimens[:s.KeepCount]
 }
@@ -0,0 +1,56 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+// SelectorRandom keeps specimens at random, no matter how fit they are. On its own it is no selection at all, but as a
+// stage of a SelectorChain it keeps a few unlikely survivors around for diversity.
+type SelectorRandom struct {
+	KeepCount int // How many specimens should be kept in each generation?
+}
+
+// Select random members of the population, keeping their sorted order.
+func (s *SelectorRandom) Select(specimens []Specimen) (fittest []Specimen) {
+	// If we are keeping the entire population, just pass it through.
+	if s.KeepCount >= len(specimens) {
+		return specimens
+	}
+
+	var picks []int = rand.Perm(len(specimens))[:s.KeepCount]
+	sort.Ints(picks)
+	for _, pick := range picks {
+		fittest = append(fittest, specimens[pick])
+	}
+	return fittest
+}
+
+// LoadSelectorRandomConfig loads the json filename as a new configuration.
+func LoadSelectorRandomConfig(filename string) (SelectorRandom, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorRandom
+
+	log.Printf("Loading random selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorRandom{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorRandom{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorRandom) validOrPanic() {
+	if s.KeepCount < 1 {
+		log.Panicf("KeepCount must be one or more: %d", s.KeepCount)
+	}
+}
@@ -0,0 +1,41 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type SelectorRandomSuite struct{}
+
+var _ = Suite(&SelectorRandomSuite{})
+
+// Add the tests.
+
+func (s *SelectorRandomSuite) Test_Select(c *C) {
+	rand.Seed(1)
+
+	// Anyone can be kept, in their sorted order.
+	var selector SelectorRandom = SelectorRandom{KeepCount: 2}
+	var kept map[float64]bool = map[float64]bool{}
+	for i := 0; i < 50; i++ {
+		var fittest []Specimen = selector.Select(selectorTestSpecimens())
+		c.Assert(len(fittest), Equals, 2)
+		c.Check(fittest[0].Score < fittest[1].Score, Equals, true)
+		kept[fittest[0].Score] = true
+		kept[fittest[1].Score] = true
+	}
+	c.Check(len(kept), Equals, 4)
+
+	// Keeping everyone is just everyone.
+	selector.KeepCount = 10
+	c.Check(selector.Select(selectorTestSpecimens()), DeepEquals, selectorTestSpecimens())
+}
+
+func (s *SelectorRandomSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorRandom = SelectorRandom{KeepCount: 1}
+	selector.validOrPanic() // Valid.
+
+	selector.KeepCount = 0
+	c.Check(func() { selector.validOrPanic() }, Panics, "KeepCount must be one or more: 0")
+}
@@ -0,0 +1,129 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+)
+
+const (
+	// How the rank of a specimen becomes its slice of the wheel.
+	RANK_LINEAR      = "linear"      // The slice grows evenly from the worst rank to the best.
+	RANK_EXPONENTIAL = "exponential" // Each rank's slice is a fraction (the Base) of the rank above it.
+)
+
+// SelectorRank is rank-based selection. Like the roulette selector, each specimen is a slice of a wheel that is spun
+// once for each specimen to keep, but the width of the slice comes from the specimen's rank in the population (by
+// selection score) rather than the selection score itself. A specimen far ahead of everyone else can't take over the
+// wheel, and selection pressure doesn't fade when everyone's selection scores are close together.
+//
+// Linear ranking gives the best specimen Pressure times the average slice and the worst 2 - Pressure times it, so Pressure
+// from 1.0 (everyone equal) to 2.0 (the worst has no slice at all). Exponential ranking gives the best specimen a slice
+// of 1.0, the next Base, the next Base squared, and so on, so a smaller Base is more pressure.
+type SelectorRank struct {
+	KeepCount       int     // How many specimens should be kept in each generation?
+	Minimize        bool    // True if lower selection scores are fitter (only the simple sorter minimizing). Must match the sorter.
+	WithReplacement bool    // True if a specimen can be kept more than once (its copies mutate independently).
+	Ranking         string  // How ranks become slices of the wheel: "linear" or "exponential". If blank, "linear".
+	Pressure        float64 // For "linear", from 1.0 to 2.0, how much more likely the best specimen is kept than average.
+	Base            float64 // For "exponential", between 0.0 and 1.0, each rank's slice as a fraction of the rank above.
+}
+
+// Select spins the ranked wheel to pick from the population.
+func (s *SelectorRank) Select(specimens []Specimen) (fittest []Specimen) {
+	return sampleByWeight(specimens, s.rankWeights(specimens), s.KeepCount, s.WithReplacement, false)
+}
+
+// LoadSelectorRankConfig loads the json filename as a new configuration.
+func LoadSelectorRankConfig(filename string) (SelectorRank, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorRank
+
+	log.Printf("Loading rank selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorRank{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorRank{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorRank) validOrPanic() {
+	if s.KeepCount < 1 {
+		log.Panicf("KeepCount must be one or more: %d", s.KeepCount)
+	}
+	switch s.Ranking {
+	case "", RANK_LINEAR:
+		if s.Pressure < 1.0 || s.Pressure > 2.0 {
+			log.Panicf("Pressure must be from 1.0 to 2.0: %f", s.Pressure)
+		}
+	case RANK_EXPONENTIAL:
+		if s.Base <= 0.0 || s.Base >= 1.0 {
+			log.Panicf("Base must be between 0.0 and 1.0: %f", s.Base)
+		}
+	default:
+		log.Panicf("Unknown Ranking: '%s'", s.Ranking)
+	}
+}
+
+// isMinimize is true if lower selection scores are fitter. It must match the sorter.
+func (s *SelectorRank) isMinimize() bool { return s.Minimize }
+
+// rankWeights are the width of each specimen's slice of the wheel, from its rank. Specimens with the same selection score
+// share a rank (the best of their ranks).
+func (s *SelectorRank) rankWeights(specimens []Specimen) (weights []float64) {
+
+	// Order the specimens, fittest first. The order is indexes into the specimens.
+	var order []int
+	for i := range specimens {
+		order = append(order, i)
+	}
+	sort.Stable(bySelectionScore{specimens: specimens, indexes: order, minimize: s.Minimize})
+
+	weights = make([]float64, len(specimens))
+	var count float64 = float64(len(specimens))
+	var rank int
+	for position, i := range order {
+		// Ties share the rank.
+		if position > 0 && specimens[i].SelectionScore != specimens[order[position-1]].SelectionScore {
+			rank = position
+		}
+
+		if s.Ranking == RANK_EXPONENTIAL {
+			weights[i] = math.Pow(s.Base, float64(rank))
+		} else {
+			// Evenly from Pressure for the best down to 2 - Pressure for the worst.
+			var fraction float64 = 0.0 // How far from the best, 0.0 to 1.0.
+			if count > 1 {
+				fraction = float64(rank) / (count - 1.0)
+			}
+			weights[i] = s.Pressure - fraction*(2.0*s.Pressure-2.0)
+		}
+	}
+	return weights
+}
+
+// bySelectionScore implements sort.Interface to sort indexes of specimens by selection score, fittest first.
+// Example: sort.Stable(bySelectionScore{specimens: specimens, indexes: order, minimize: false})
+type bySelectionScore struct {
+	specimens []Specimen
+	indexes   []int
+	minimize  bool // True if a lower selection score is fitter.
+}
+
+func (a bySelectionScore) Len() int      { return len(a.indexes) }
+func (a bySelectionScore) Swap(i, j int) { a.indexes[i], a.indexes[j] = a.indexes[j], a.indexes[i] }
+func (a bySelectionScore) Less(i, j int) bool {
+	if a.minimize {
+		return a.specimens[a.indexes[i]].SelectionScore < a.specimens[a.indexes[j]].SelectionScore
+	}
+	return a.specimens[a.indexes[i]].SelectionScore > a.specimens[a.indexes[j]].SelectionScore
+}
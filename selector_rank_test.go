@@ -0,0 +1,74 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type SelectorRankSuite struct{}
+
+var _ = Suite(&SelectorRankSuite{})
+
+// Add the tests.
+
+func (s *SelectorRankSuite) Test_RankWeights(c *C) {
+	// Ties share a rank.
+	var selector SelectorRank = SelectorRank{KeepCount: 2, Pressure: 2.0}
+	var weights []float64 = selector.rankWeights(selectorTestSpecimens())
+	c.Check(weights[0], Equals, 2.0)
+	c.Check(weights[1], Equals, 0.0)
+	c.Check(isNearlyEqual(weights[2], 4.0/3.0), Equals, true)
+	c.Check(weights[3], Equals, weights[2])
+
+	// No pressure is everyone equal.
+	selector.Pressure = 1.0
+	c.Check(selector.rankWeights(selectorTestSpecimens()), DeepEquals, []float64{1.0, 1.0, 1.0, 1.0})
+
+	// Minimizing, the lowest selection score is the best rank.
+	selector = SelectorRank{KeepCount: 2, Ranking: RANK_EXPONENTIAL, Base: 0.5, Minimize: true}
+	c.Check(selector.rankWeights(selectorTestSpecimens()), DeepEquals, []float64{0.125, 1.0, 0.5, 0.5})
+
+	// A lone specimen.
+	selector = SelectorRank{KeepCount: 1, Pressure: 1.5}
+	c.Check(selector.rankWeights([]Specimen{Specimen{}}), DeepEquals, []float64{1.5})
+}
+
+func (s *SelectorRankSuite) Test_Select(c *C) {
+	rand.Seed(1)
+
+	// With full pressure, the worst rank has no slice of the wheel.
+	var selector SelectorRank = SelectorRank{KeepCount: 3, Pressure: 2.0}
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{0.0, 2.0, 3.0})
+	}
+
+	// With replacement, the best rank is kept the most.
+	selector = SelectorRank{KeepCount: 1000, Ranking: RANK_EXPONENTIAL, Base: 0.1, WithReplacement: true}
+	var counts map[float64]int = map[float64]int{}
+	for _, specimen := range selector.Select(selectorTestSpecimens()) {
+		counts[specimen.Score]++
+	}
+	c.Check(counts[0.0] > counts[2.0]+counts[3.0], Equals, true)
+	c.Check(counts[2.0]+counts[3.0] > counts[1.0], Equals, true)
+}
+
+func (s *SelectorRankSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorRank = SelectorRank{KeepCount: 1, Pressure: 1.5}
+	selector.validOrPanic() // Valid.
+
+	selector.Pressure = 2.5
+	c.Check(func() { selector.validOrPanic() }, Panics, "Pressure must be from 1.0 to 2.0: 2.500000")
+
+	selector = SelectorRank{KeepCount: 1, Ranking: RANK_EXPONENTIAL, Base: 0.5}
+	selector.validOrPanic() // Valid.
+
+	selector.Base = 1.0
+	c.Check(func() { selector.validOrPanic() }, Panics, "Base must be between 0.0 and 1.0: 1.000000")
+
+	selector.Ranking = "sigmoid"
+	c.Check(func() { selector.validOrPanic() }, Panics, "Unknown Ranking: 'sigmoid'")
+
+	selector.KeepCount = 0
+	c.Check(func() { selector.validOrPanic() }, Panics, "KeepCount must be one or more: 0")
+}
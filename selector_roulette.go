@@ -0,0 +1,206 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+)
+
+// SelectorRoulette is fitness-proportionate selection. Each specimen is a slice of a roulette wheel as wide as its
+// selection score, and the wheel is spun once for each specimen to keep. Fitter specimens are more likely to be kept, but
+// anyone might be.
+//
+// Selection scores can be negative, so the wheel is measured from the worst selection score in the population. The worst
+// specimen has no slice at all (unless everyone is equal, then everyone has the same slice).
+type SelectorRoulette struct {
+	KeepCount       int  // How many specimens should be kept in each generation?
+	Minimize        bool // True if lower selection scores are fitter (only the simple sorter minimizing). Must match the sorter.
+	WithReplacement bool // True if a specimen can be kept more than once (its copies mutate independently).
+}
+
+// Select spins the roulette wheel to pick from the population.
+func (s *SelectorRoulette) Select(specimens []Specimen) (fittest []Specimen) {
+	return sampleByWeight(specimens, proportionateWeights(specimens, s.Minimize), s.KeepCount, s.WithReplacement, false)
+}
+
+// LoadSelectorRouletteConfig loads the json filename as a new configuration.
+func LoadSelectorRouletteConfig(filename string) (SelectorRoulette, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorRoulette
+
+	log.Printf("Loading roulette selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorRoulette{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorRoulette{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorRoulette) validOrPanic() {
+	if s.KeepCount < 1 {
+		log.Panicf("KeepCount must be one or more: %d", s.KeepCount)
+	}
+}
+
+// isMinimize is true if lower selection scores are fitter. It must match the sorter.
+func (s *SelectorRoulette) isMinimize() bool { return s.Minimize }
+
+// proportionateWeights are the width of each specimen's slice of the wheel, how far its selection score is from the worst.
+func proportionateWeights(specimens []Specimen, minimize bool) (weights []float64) {
+	if len(specimens) == 0 {
+		return nil
+	}
+
+	// Find the worst selection score.
+	var worst float64 = specimens[0].SelectionScore
+	for _, specimen := range specimens {
+		if (!minimize && specimen.SelectionScore < worst) || (minimize && specimen.SelectionScore > worst) {
+			worst = specimen.SelectionScore
+		}
+	}
+
+	for _, specimen := range specimens {
+		if minimize {
+			weights = append(weights, worst-specimen.SelectionScore)
+		} else {
+			weights = append(weights, specimen.SelectionScore-worst)
+		}
+	}
+	return weights
+}
+
+// sampleByWeight picks keepCount specimens, each with a chance in proportion to its weight. Without replacement, a picked
+// specimen is taken off the wheel, and if everyone is to be kept the specimens are just passed through.
+//
+// A roulette wheel is spun once for each pick. A universal wheel (stochastic universal sampling) is spun once for all the
+// picks, with the picks evenly spaced around it, so the picks can't all bunch up on a few lucky specimens.
+func sampleByWeight(specimens []Specimen, weights []float64, keepCount int, withReplacement bool, isUniversal bool) (fittest []Specimen) {
+	if len(specimens) == 0 {
+		return nil
+	}
+
+	// If we are keeping the entire population, just pass it through.
+	if !withReplacement && keepCount >= len(specimens) {
+		return specimens
+	}
+
+	// With replacement, the wheel never changes.
+	if withReplacement {
+		var picks []int
+		if isUniversal {
+			picks = universalPicks(weights, keepCount)
+		} else {
+			for i := 0; i < keepCount; i++ {
+				picks = append(picks, roulettePick(weights))
+			}
+		}
+		for _, pick := range picks {
+			fittest = append(fittest, specimens[pick])
+		}
+		return fittest
+	}
+
+	// Without replacement, keep spinning the wheel of who is left. Who is left is indexes into the specimens.
+	var remaining []int
+	for i := range specimens {
+		remaining = append(remaining, i)
+	}
+	for len(fittest) < keepCount {
+		var remainingWeights []float64
+		for _, i := range remaining {
+			remainingWeights = append(remainingWeights, weights[i])
+		}
+
+		// The picks are positions in the remaining specimens.
+		var picks []int
+		if isUniversal {
+			picks = universalPicks(remainingWeights, keepCount-len(fittest))
+		} else {
+			picks = []int{roulettePick(remainingWeights)}
+		}
+
+		// Keep each pick once, and take it off the wheel. A universal wheel can pick the same specimen more than once, the
+		// next spin makes up the difference.
+		var isPicked map[int]bool = map[int]bool{}
+		for _, pick := range picks {
+			if !isPicked[pick] {
+				isPicked[pick] = true
+				fittest = append(fittest, specimens[remaining[pick]])
+			}
+		}
+		var stillRemaining []int
+		for position, i := range remaining {
+			if !isPicked[position] {
+				stillRemaining = append(stillRemaining, i)
+			}
+		}
+		remaining = stillRemaining
+	}
+	return fittest
+}
+
+// roulettePick spins a wheel once and returns the index of the weight it lands on. If no weight is above zero, every index
+// is equally likely.
+func roulettePick(weights []float64) int {
+	var total float64 = wheelTotal(weights)
+	if total <= 0.0 {
+		return rand.Intn(len(weights))
+	}
+	return wheelLanding(weights, rand.Float64()*total)
+}
+
+// universalPicks spins a wheel once with count evenly spaced pointers and returns the indexes of the weights they land on,
+// in wheel order. If no weight is above zero, every index is equally likely.
+func universalPicks(weights []float64, count int) (picks []int) {
+	var total float64 = wheelTotal(weights)
+	if total <= 0.0 {
+		weights = make([]float64, len(weights))
+		for i := range weights {
+			weights[i] = 1.0
+		}
+		total = float64(len(weights))
+	}
+
+	var spacing float64 = total / float64(count)
+	var start float64 = rand.Float64() * spacing
+	for i := 0; i < count; i++ {
+		picks = append(picks, wheelLanding(weights, start+float64(i)*spacing))
+	}
+	return picks
+}
+
+// wheelTotal is the size of the wheel, the total of all the (positive) weights.
+func wheelTotal(weights []float64) (total float64) {
+	for _, weight := range weights {
+		if weight > 0.0 {
+			total += weight
+		}
+	}
+	return total
+}
+
+// wheelLanding is the index of the weight a pointer at position on the wheel lands on.
+func wheelLanding(weights []float64, position float64) int {
+	var last int
+	var cumulative float64
+	for i, weight := range weights {
+		if weight <= 0.0 {
+			continue
+		}
+		cumulative += weight
+		if position < cumulative {
+			return i
+		}
+		last = i
+	}
+	// Rounding can leave a pointer at the very end of the wheel.
+	return last
+}
@@ -0,0 +1,96 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+	"sort"
+)
+
+// Create a suite.
+type SelectorRouletteSuite struct{}
+
+var _ = Suite(&SelectorRouletteSuite{})
+
+// selectorTestSpecimens have a negative selection score and a tie.
+func selectorTestSpecimens() []Specimen {
+	return []Specimen{
+		Specimen{Score: 0.0, SelectionScore: 3.0},
+		Specimen{Score: 1.0, SelectionScore: -1.0},
+		Specimen{Score: 2.0, SelectionScore: 1.0},
+		Specimen{Score: 3.0, SelectionScore: 1.0},
+	}
+}
+
+// selectedScores are the sorted scores of the selected specimens, to identify them.
+func selectedScores(specimens []Specimen) (scores []float64) {
+	for _, specimen := range specimens {
+		scores = append(scores, specimen.Score)
+	}
+	sort.Float64s(scores)
+	return scores
+}
+
+// Add the tests.
+
+func (s *SelectorRouletteSuite) Test_ProportionateWeights(c *C) {
+	c.Check(proportionateWeights(nil, false), IsNil)
+	c.Check(proportionateWeights(selectorTestSpecimens(), false), DeepEquals, []float64{4.0, 0.0, 2.0, 2.0})
+	c.Check(proportionateWeights(selectorTestSpecimens(), true), DeepEquals, []float64{0.0, 4.0, 2.0, 2.0})
+}
+
+func (s *SelectorRouletteSuite) Test_Wheel(c *C) {
+	var weights []float64 = []float64{4.0, 0.0, 2.0}
+	c.Check(wheelTotal(weights), Equals, 6.0)
+	c.Check(wheelLanding(weights, 0.0), Equals, 0)
+	c.Check(wheelLanding(weights, 3.9), Equals, 0)
+	c.Check(wheelLanding(weights, 4.0), Equals, 2) // Nothing lands on no weight.
+	c.Check(wheelLanding(weights, 6.0), Equals, 2) // The very end of the wheel.
+
+	// Evenly spaced pointers land in proportion.
+	rand.Seed(1)
+	c.Check(universalPicks([]float64{1.0, 1.0, 2.0}, 4), DeepEquals, []int{0, 1, 2, 2})
+
+	// With no weights at all, every index is possible.
+	var picked map[int]bool = map[int]bool{}
+	for i := 0; i < 100; i++ {
+		picked[roulettePick([]float64{0.0, 0.0, 0.0})] = true
+	}
+	c.Check(len(picked), Equals, 3)
+	c.Check(universalPicks([]float64{0.0, 0.0, 0.0}, 3), DeepEquals, []int{0, 1, 2})
+}
+
+func (s *SelectorRouletteSuite) Test_Select(c *C) {
+	rand.Seed(1)
+
+	// The worst specimen has no slice of the wheel.
+	var selector SelectorRoulette = SelectorRoulette{KeepCount: 3}
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{0.0, 2.0, 3.0})
+	}
+
+	// Minimizing, the best specimen has no slice.
+	selector.Minimize = true
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{1.0, 2.0, 3.0})
+	}
+
+	// With replacement, specimens can be kept more than once, but never the one with no slice.
+	selector = SelectorRoulette{KeepCount: 20, WithReplacement: true}
+	var fittest []Specimen = selector.Select(selectorTestSpecimens())
+	c.Check(len(fittest), Equals, 20)
+	for _, specimen := range fittest {
+		c.Check(specimen.Score, Not(Equals), 1.0)
+	}
+
+	// Keeping everyone is just everyone.
+	selector = SelectorRoulette{KeepCount: 4}
+	c.Check(selector.Select(selectorTestSpecimens()), DeepEquals, selectorTestSpecimens())
+}
+
+func (s *SelectorRouletteSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorRoulette = SelectorRoulette{KeepCount: 1}
+	selector.validOrPanic() // Valid.
+
+	selector.KeepCount = 0
+	c.Check(func() { selector.validOrPanic() }, Panics, "KeepCount must be one or more: 0")
+}
@@ -0,0 +1,94 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+)
+
+// SelectorSpecies keeps the fittest fraction of every species, the NEAT survival threshold. A selector that only sees the
+// whole population (like SelectorElitism) can keep nothing but the dominant species, throwing away new species before
+// they've had time to improve. Here every species keeps at least its champion (its fittest specimen).
+//
+// The specimens are expected sorted fittest first, and grouped by their SpeciesId. The total kept is capped by MaxKeep,
+// filled fittest first across the whole population, but the cap never drops a champion.
+type SelectorSpecies struct {
+	SurvivalThreshold float64 // The fraction of each species that is kept (rounded up), more than 0.0 and up to 1.0.
+	MaxKeep           int     // The most specimens kept in each generation, but every champion is kept. If 0, no cap.
+}
+
+// Select the fittest of each species.
+func (s *SelectorSpecies) Select(specimens []Specimen) (fittest []Specimen) {
+
+	// How many are in each species?
+	var speciesCounts map[int]int = map[int]int{}
+	for _, specimen := range specimens {
+		speciesCounts[specimen.SpeciesId]++
+	}
+
+	// The champions are kept no matter what. They are the first of each species in the sorted specimens.
+	var isKept []bool = make([]bool, len(specimens))
+	var speciesKept map[int]int = map[int]int{}
+	var keptCount int
+	for i, specimen := range specimens {
+		if speciesKept[specimen.SpeciesId] == 0 {
+			isKept[i] = true
+			speciesKept[specimen.SpeciesId] = 1
+			keptCount++
+		}
+	}
+
+	// The rest of each species' survivors, fittest first, until the cap.
+	for i, specimen := range specimens {
+		if s.MaxKeep > 0 && keptCount >= s.MaxKeep {
+			break
+		}
+		if isKept[i] {
+			continue
+		}
+		var survivors int = int(math.Ceil(s.SurvivalThreshold * float64(speciesCounts[specimen.SpeciesId])))
+		if speciesKept[specimen.SpeciesId] < survivors {
+			isKept[i] = true
+			speciesKept[specimen.SpeciesId]++
+			keptCount++
+		}
+	}
+
+	// Keep them in their sorted order.
+	for i, specimen := range specimens {
+		if isKept[i] {
+			fittest = append(fittest, specimen)
+		}
+	}
+	return fittest
+}
+
+// LoadSelectorSpeciesConfig loads the json filename as a new configuration.
+func LoadSelectorSpeciesConfig(filename string) (SelectorSpecies, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorSpecies
+
+	log.Printf("Loading species selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorSpecies{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorSpecies{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorSpecies) validOrPanic() {
+	if s.SurvivalThreshold <= 0.0 || s.SurvivalThreshold > 1.0 {
+		log.Panicf("SurvivalThreshold must be more than 0.0 and up to 1.0: %f", s.SurvivalThreshold)
+	}
+	if s.MaxKeep < 0 {
+		log.Panicf("MaxKeep cannot be negative: %d", s.MaxKeep)
+	}
+}
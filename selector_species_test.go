@@ -0,0 +1,49 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type SelectorSpeciesSuite struct{}
+
+var _ = Suite(&SelectorSpeciesSuite{})
+
+// selectorSpeciesTestSpecimens are sorted specimens of three species, four, three, and one in size.
+func selectorSpeciesTestSpecimens() []Specimen {
+	var specimens []Specimen
+	for i, speciesId := range []int{1, 1, 2, 1, 1, 3, 2, 2} {
+		specimens = append(specimens, Specimen{Score: float64(i + 1), SpeciesId: speciesId})
+	}
+	return specimens
+}
+
+// Add the tests.
+
+func (s *SelectorSpeciesSuite) Test_Select(c *C) {
+	var selector SelectorSpecies = SelectorSpecies{SurvivalThreshold: 0.5}
+	c.Check(selectedScores(selector.Select(selectorSpeciesTestSpecimens())), DeepEquals, []float64{1.0, 2.0, 3.0, 6.0, 7.0})
+
+	// Capped, fittest first.
+	selector.MaxKeep = 4
+	c.Check(selectedScores(selector.Select(selectorSpeciesTestSpecimens())), DeepEquals, []float64{1.0, 2.0, 3.0, 6.0})
+
+	// The cap never drops a champion.
+	selector.MaxKeep = 2
+	c.Check(selectedScores(selector.Select(selectorSpeciesTestSpecimens())), DeepEquals, []float64{1.0, 3.0, 6.0})
+
+	// Everyone.
+	selector = SelectorSpecies{SurvivalThreshold: 1.0}
+	c.Check(selector.Select(selectorSpeciesTestSpecimens()), DeepEquals, selectorSpeciesTestSpecimens())
+}
+
+func (s *SelectorSpeciesSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorSpecies = SelectorSpecies{SurvivalThreshold: 0.2}
+	selector.validOrPanic() // Valid.
+
+	selector.MaxKeep = -1
+	c.Check(func() { selector.validOrPanic() }, Panics, "MaxKeep cannot be negative: -1")
+
+	selector.SurvivalThreshold = 0.0
+	c.Check(func() { selector.validOrPanic() }, Panics, "SurvivalThreshold must be more than 0.0 and up to 1.0: 0.000000")
+}
@@ -0,0 +1,56 @@
+package genetic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+)
+
+// SelectorStochasticUniversal is stochastic universal sampling, a fitness-proportionate selection with less luck than the
+// roulette selector. The wheel (with the same slices as the roulette selector) is spun once, with a pointer for each
+// specimen to keep evenly spaced around it. A specimen with twice the average slice is kept about twice, never ten times
+// by a run of lucky spins.
+//
+// Without replacement, a specimen more than one pointer lands on is only kept once, and the wheel of who is left is spun
+// again for the rest.
+//
+// Reference: Baker, J. E. (1987) Reducing Bias and Inefficiency in the Selection Algorithm.
+type SelectorStochasticUniversal struct {
+	KeepCount       int  // How many specimens should be kept in each generation?
+	Minimize        bool // True if lower selection scores are fitter (only the simple sorter minimizing). Must match the sorter.
+	WithReplacement bool // True if a specimen can be kept more than once (its copies mutate independently).
+}
+
+// Select spins the wheel once to pick from the population.
+func (s *SelectorStochasticUniversal) Select(specimens []Specimen) (fittest []Specimen) {
+	return sampleByWeight(specimens, proportionateWeights(specimens, s.Minimize), s.KeepCount, s.WithReplacement, true)
+}
+
+// LoadSelectorStochasticUniversalConfig loads the json filename as a new configuration.
+func LoadSelectorStochasticUniversalConfig(filename string) (SelectorStochasticUniversal, error) {
+	var err error
+	var bytes []byte
+	var selector SelectorStochasticUniversal
+
+	log.Printf("Loading stochastic universal selector Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SelectorStochasticUniversal{}, err
+	}
+	if err = json.Unmarshal(bytes, &selector); err != nil {
+		return SelectorStochasticUniversal{}, err
+	}
+	selector.validOrPanic()
+	return selector, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SelectorStochasticUniversal) validOrPanic() {
+	if s.KeepCount < 1 {
+		log.Panicf("KeepCount must be one or more: %d", s.KeepCount)
+	}
+}
+
+// isMinimize is true if lower selection scores are fitter. It must match the sorter.
+func (s *SelectorStochasticUniversal) isMinimize() bool { return s.Minimize }
@@ -0,0 +1,44 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type SelectorStochasticUniversalSuite struct{}
+
+var _ = Suite(&SelectorStochasticUniversalSuite{})
+
+// Add the tests.
+
+func (s *SelectorStochasticUniversalSuite) Test_Select(c *C) {
+	rand.Seed(1)
+
+	// Without replacement, a specimen two pointers land on is kept once and the rest of the wheel is spun again.
+	var selector SelectorStochasticUniversal = SelectorStochasticUniversal{KeepCount: 3}
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{0.0, 2.0, 3.0})
+	}
+
+	// With replacement, each specimen is kept in proportion to its slice, give or take one.
+	// The slices are 4, 0, 2, and 2 of 8.
+	selector = SelectorStochasticUniversal{KeepCount: 8, WithReplacement: true}
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{0.0, 0.0, 0.0, 0.0, 2.0, 2.0, 3.0, 3.0})
+	}
+
+	// Minimizing.
+	selector = SelectorStochasticUniversal{KeepCount: 4, Minimize: true, WithReplacement: true}
+	for i := 0; i < 10; i++ {
+		c.Check(selectedScores(selector.Select(selectorTestSpecimens())), DeepEquals, []float64{1.0, 1.0, 2.0, 3.0})
+	}
+}
+
+func (s *SelectorStochasticUniversalSuite) Test_ValidOrPanic(c *C) {
+	var selector SelectorStochasticUniversal = SelectorStochasticUniversal{KeepCount: 1}
+	selector.validOrPanic() // Valid.
+
+	selector.KeepCount = 0
+	c.Check(func() { selector.validOrPanic() }, Panics, "KeepCount must be one or more: 0")
+}
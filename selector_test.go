@@ -0,0 +1,36 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type SelectorSuite struct{}
+
+var _ = Suite(&SelectorSuite{})
+
+// Add the tests.
+
+func (s *SelectorSuite) Test_ValidSelectionOrPanic(c *C) {
+	var maximizing Sorter = NewSorterSimpleMaximize()
+	var minimizing Sorter = NewSorterSimpleMinimize()
+	var lexicographic Sorter = &SorterLexicographic{Objectives: []LexicographicObjective{LexicographicObjective{Outcome: 0, Maximize: false}}}
+
+	// Matching directions.
+	validSelectionOrPanic(maximizing, &SelectorRoulette{KeepCount: 1})
+	validSelectionOrPanic(minimizing, &SelectorRank{KeepCount: 1, Minimize: true})
+	validSelectionOrPanic(minimizing, &SelectorElitism{KeepCount: 1}) // Only uses the sorted order.
+
+	// Only the simple sorter minimizing gives lower selection scores to the fitter, whatever the others are seeking.
+	c.Check(func() { validSelectionOrPanic(minimizing, &SelectorRoulette{KeepCount: 1}) }, Panics, "Selector genetic.SelectorRoulette has Minimize false but sorter genetic.sorterSimple gives fitter specimens lower selection scores")
+	c.Check(func() {
+		validSelectionOrPanic(lexicographic, &SelectorStochasticUniversal{KeepCount: 1, Minimize: true})
+	}, Panics, "Selector genetic.SelectorStochasticUniversal has Minimize true but sorter genetic.SorterLexicographic gives fitter specimens higher selection scores")
+
+	// Every stage of a chain is checked.
+	var chain *SelectorChain = &SelectorChain{Stages: []SelectorStage{
+		SelectorStage{Type: SELECTOR_ELITISM, Selector: &SelectorElitism{KeepCount: 1}},
+		SelectorStage{Type: SELECTOR_RANK, Selector: &SelectorRank{KeepCount: 1, Minimize: true}},
+	}}
+	c.Check(func() { validSelectionOrPanic(maximizing, chain) }, Panics, "Selector genetic.SelectorRank has Minimize true but sorter genetic.sorterSimple gives fitter specimens higher selection scores")
+}
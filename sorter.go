@@ -4,9 +4,46 @@ package genetic
 // to pick the fittest members of a population.
 type Sorter interface {
 
-	// Order the specimens and report how well the population did.
+	// Order the specimens and report how well the population did. If no specimen is feasible, there is no best score
+	// (see noFeasibleBestScore).
 	Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen)
 
 	// IsMaximize returns true if this experiment is seeking higher values, false if seeking lower values.
 	IsMaximize() bool
 }
+
+// SorterDetails is a Sorter that has details worth recording with each generation (e.g. a reference point that changes).
+type SorterDetails interface {
+	Sorter
+
+	// The details of the last sort of the whole population, as json.
+	GenerationDetails() (json []byte)
+}
+
+// lowerSelectionSorter is a Sorter that may give fitter specimens lower selection scores (e.g. the simple sorter
+// minimizing). Sorters that aren't one always give fitter specimens higher selection scores, whatever IsMaximize says.
+type lowerSelectionSorter interface {
+	Sorter
+
+	// isLowerSelectionFitter is true if fitter specimens have lower selection scores.
+	isLowerSelectionFitter() bool
+}
+
+// outcomeSorter is a Sorter that orders specimens by their outcomes rather than their score, where some outcomes may be
+// fitter when lower.
+type outcomeSorter interface {
+	Sorter
+
+	// minimizedOutcomes are the outcomes (indexes into the outcomes) that are fitter when lower.
+	minimizedOutcomes() []int
+}
+
+// isLowerSelectionFitter is true if the sorter gives fitter specimens lower selection scores.
+func isLowerSelectionFitter(sorter Sorter) bool {
+	var lowerSorter lowerSelectionSorter
+	var ok bool
+	if lowerSorter, ok = sorter.(lowerSelectionSorter); ok {
+		return lowerSorter.isLowerSelectionFitter()
+	}
+	return false
+}
@@ -9,6 +9,16 @@ import (
 	"sort"
 )
 
+const (
+	// How hypervolume indicators are calculated.
+	HYPERVOLUME_ALGORITHM_KD_TREE = "kd_tree" // Quick, but only exact in two dimensions (the default).
+	HYPERVOLUME_ALGORITHM_WFG     = "wfg"     // Exact in any number of dimensions.
+
+	// Where the hypervolume reference point comes from.
+	HYPERVOLUME_REFERENCE_STATIC = "static" // The configured ReferencePoint (the default).
+	HYPERVOLUME_REFERENCE_NADIR  = "nadir"  // Each generation, the worst outcomes of the population, pushed out by ReferenceOffset.
+)
+
 // SorterHypervolumeIndicator sorts multi-outcome specimens how good their outcomes are as well as how much their outcome is
 // unique in the population. Hypervolume indicators require essentially comparing each member
 // of the population against all other members of the population (although there is some small optimication).
@@ -17,11 +27,22 @@ import (
 // with the indicator being given as a bonus on top of its normal volume.
 //
 // The hypervolume indicator sort ignores the score and bonus and only operatons on the multi-outcomes from the scorer.
+//
+// A static reference point that a specimen doesn't pass in some outcome gives that specimen no volume at all. Early in an
+// experiment that can be most of the population. The "nadir" reference mode instead puts the reference point just past the
+// worst outcomes of each generation's population, so every specimen has some volume. The reference point used for the last
+// sort is reported in the generation details.
 type SorterHypervolumeIndicator struct {
-	ReferencePoint []float64 // For each outcome, what is the base value the outcome is compared to.
-	Maximize       []bool    // For each outcome, true means a higher value is more fit, false means a lower value is more fit.
-	Weights        []float64 // For each outcome, what relative value does it have? A higher value means it will have more influence on the sort.
-	IndicatorPower float64   // The power scaling for the indicator, assuming that an indicator may only be in half the dimensions 2.0 would scale to be relative to the hypercube's volume
+	ReferencePoint  []float64 // For each outcome, what is the base value the outcome is compared to. Unused in "nadir" mode.
+	Maximize        []bool    // For each outcome, true means a higher value is more fit, false means a lower value is more fit.
+	Weights         []float64 // For each outcome, what relative value does it have? A higher value means it will have more influence on the sort.
+	IndicatorPower  float64   // The power scaling for the indicator, assuming that an indicator may only be in half the dimensions 2.0 would scale to be relative to the hypercube's volume
+	Algorithm       string    // How the indicators are calculated: "kd_tree" or "wfg" (exact, for three or more outcomes). If blank, "kd_tree".
+	ReferenceMode   string    // Where the reference point comes from: "static" or "nadir". If blank, "static".
+	ReferenceOffset []float64 // For "nadir", how far past the worst outcome the reference point is for each outcome (more than 0.0, or the worst have no volume).
+	Normalize       bool      // True scales each outcome so the best in the population is 1.0 from the reference point (before weights).
+
+	referencePoint []float64 // The reference point used in the last sort.
 }
 
 // LoadSorterHypervolumeIndicatorConfig loads the json filename as a new configuration.
@@ -45,34 +66,69 @@ func LoadSorterHypervolumeIndicatorConfig(filename string) (SorterHypervolumeInd
 
 // validOrPanic panics if we're not ready for use.
 func (s *SorterHypervolumeIndicator) validOrPanic() {
-	if len(s.ReferencePoint) == 0 {
-		log.Panic("ReferencePoint must have values")
-	}
-	if len(s.ReferencePoint) != len(s.Maximize) || len(s.Maximize) != len(s.Weights) {
-		log.Panicf("ReferencePoint (%v), Maximize (%v), and Weights (%v) must all have the same number of values", s.ReferencePoint, s.Maximize, s.Weights)
+	switch s.ReferenceMode {
+	case "", HYPERVOLUME_REFERENCE_STATIC:
+		if len(s.ReferencePoint) == 0 {
+			log.Panic("ReferencePoint must have values")
+		}
+		if len(s.ReferencePoint) != len(s.Maximize) || len(s.Maximize) != len(s.Weights) {
+			log.Panicf("ReferencePoint (%v), Maximize (%v), and Weights (%v) must all have the same number of values", s.ReferencePoint, s.Maximize, s.Weights)
+		}
+	case HYPERVOLUME_REFERENCE_NADIR:
+		if len(s.Maximize) == 0 {
+			log.Panic("Maximize must have values")
+		}
+		if len(s.Maximize) != len(s.Weights) || len(s.ReferenceOffset) != len(s.Maximize) {
+			log.Panicf("Maximize (%v), Weights (%v), and ReferenceOffset (%v) must all have the same number of values", s.Maximize, s.Weights, s.ReferenceOffset)
+		}
+		for _, offset := range s.ReferenceOffset {
+			if offset <= 0.0 {
+				log.Panicf("ReferenceOffset must be more than 0.0, the worst specimens would have no volume: %v", s.ReferenceOffset)
+			}
+		}
+	default:
+		log.Panicf("Unknown hypervolume ReferenceMode: '%s'", s.ReferenceMode)
 	}
 	for _, weight := range s.Weights {
 		if weight == 0.0 {
 			log.Panicf("Weights with a zero value are not allowed: %v", s.Weights)
 		}
 	}
+	if s.Algorithm != "" && s.Algorithm != HYPERVOLUME_ALGORITHM_KD_TREE && s.Algorithm != HYPERVOLUME_ALGORITHM_WFG {
+		log.Panicf("Unknown hypervolume Algorithm: '%s'", s.Algorithm)
+	}
 }
 
-// Sort the specimens descending by how good their multi-outcome are with a bonus for having multi-outcomes unique to the population.
+// Sort the specimens descending by how good their multi-outcome are with a bonus for having multi-outcomes unique to the population,
+// feasible specimens first. Infeasible specimens take no part in the hypervolumes of the feasible ones.
 func (s *SorterHypervolumeIndicator) Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	return sortConstrained(specimens, true, s.sortFeasible)
+}
+
+// sortFeasible sorts the specimens by hypervolume, ignoring constraints.
+func (s *SorterHypervolumeIndicator) sortFeasible(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+
+	// Where are the hypercubes measured from, and how are they scaled?
+	var referencePoint []float64 = s.generationReferencePoint(specimens)
+	var weights []float64 = s.generationWeights(specimens, referencePoint)
+	s.referencePoint = referencePoint
 
 	// Create hypercubes of the specimens.
 	var hypercubes []*specimenHypercube
 	for _, specimen := range specimens {
-		var hypercube specimenHypercube = newSpecimenHypercube(specimen, s.ReferencePoint, s.Maximize, s.Weights)
+		var hypercube specimenHypercube = newSpecimenHypercube(specimen, referencePoint, s.Maximize, weights)
 		hypercubes = append(hypercubes, &hypercube)
 	}
 
 	// Calculate the hypervolume indicators.
-	calculateHypervolumeIndicators(hypercubes)
+	if s.Algorithm == HYPERVOLUME_ALGORITHM_WFG {
+		calculateExactHypervolumeIndicators(hypercubes)
+	} else {
+		calculateHypervolumeIndicators(hypercubes)
+	}
 
 	// How many dimensions are in the hypercubes.
-	var dimensions float64 = float64(len(s.ReferencePoint))
+	var dimensions float64 = float64(len(s.Maximize))
 
 	// Give each specimen a selection score.
 	var bestSpecimen *specimenHypercube
@@ -128,9 +184,81 @@ func (s *SorterHypervolumeIndicator) Sort(specimens []Specimen) (bestScore float
 	return bestScore, best, sorted
 }
 
+// generationReferencePoint is the reference point for sorting these specimens. In "nadir" mode it is the worst value of
+// each outcome in the population, pushed further out by the offset.
+func (s *SorterHypervolumeIndicator) generationReferencePoint(specimens []Specimen) (referencePoint []float64) {
+	if s.ReferenceMode != HYPERVOLUME_REFERENCE_NADIR || len(specimens) == 0 {
+		return s.ReferencePoint
+	}
+
+	for i, maximize := range s.Maximize {
+		var offset float64 = s.ReferenceOffset[i]
+		var nadir float64 = specimens[0].Outcomes[i]
+		for _, specimen := range specimens {
+			if maximize {
+				nadir = math.Min(nadir, specimen.Outcomes[i])
+			} else {
+				nadir = math.Max(nadir, specimen.Outcomes[i])
+			}
+		}
+		if maximize {
+			referencePoint = append(referencePoint, nadir-offset)
+		} else {
+			referencePoint = append(referencePoint, nadir+offset)
+		}
+	}
+	return referencePoint
+}
+
+// generationWeights are the weights for sorting these specimens. When normalizing, each weight is divided by the distance
+// from the reference point to the best value of that outcome in the population, so the best is 1.0 (times the weight).
+func (s *SorterHypervolumeIndicator) generationWeights(specimens []Specimen, referencePoint []float64) (weights []float64) {
+	if !s.Normalize || len(specimens) == 0 {
+		return s.Weights
+	}
+
+	for i, maximize := range s.Maximize {
+		var ideal float64 = specimens[0].Outcomes[i]
+		for _, specimen := range specimens {
+			if maximize {
+				ideal = math.Max(ideal, specimen.Outcomes[i])
+			} else {
+				ideal = math.Min(ideal, specimen.Outcomes[i])
+			}
+		}
+
+		// If nothing passes the reference point, there is nothing to scale by.
+		var length float64 = math.Abs(ideal - referencePoint[i])
+		if (maximize && ideal <= referencePoint[i]) || (!maximize && ideal >= referencePoint[i]) {
+			length = 1.0
+		}
+		weights = append(weights, s.Weights[i]/length)
+	}
+	return weights
+}
+
+// GenerationDetails reports the reference point used in the last sort.
+func (s *SorterHypervolumeIndicator) GenerationDetails() (details []byte) {
+	var err error
+	if details, err = json.Marshal(map[string][]float64{"ReferencePoint": s.referencePoint}); err != nil {
+		log.Panic(err)
+	}
+	return details
+}
+
 // IsMaximize returns true. Hypervolume indicator sort makes normalized hypercubes that increase in volume when fitter.
 func (s *SorterHypervolumeIndicator) IsMaximize() bool { return true }
 
+// minimizedOutcomes are the outcomes that are fitter when lower.
+func (s *SorterHypervolumeIndicator) minimizedOutcomes() (outcomes []int) {
+	for i, maximize := range s.Maximize {
+		if !maximize {
+			outcomes = append(outcomes, i)
+		}
+	}
+	return outcomes
+}
+
 // byHypervolumeIndicatorDescending implements sort.Interface to sort descending by selection score, then volume, then indicator.
 // Example: sort.Sort(byHypervolumeIndicatorDescending(hypercubes))
 type byHypervolumeIndicatorDescending []*specimenHypercube
@@ -94,3 +94,97 @@ func (s *SorterHypervolumeIndicatorSuite) Test_SorterHypervolumeIndicator(c *C)
 	c.Check(bestScore, Equals, 2.25)                                                                           // Volume is the ultimate best score.
 	c.Check(best, Equals, "indicator: 0.250000, volume: 2.250000, speciesmembercount: 1, outcomes: [1.5 1.5]") // Volume is the ultimate best score.
 }
+
+func (s *SorterHypervolumeIndicatorSuite) Test_ValidOrPanic_Nadir(c *C) {
+	var goodSorter SorterHypervolumeIndicator = SorterHypervolumeIndicator{
+		Maximize:        []bool{true, false},
+		Weights:         []float64{1.0, 1.0},
+		ReferenceMode:   HYPERVOLUME_REFERENCE_NADIR,
+		ReferenceOffset: []float64{0.5, 0.5},
+	}
+	var sorter SorterHypervolumeIndicator
+
+	// No reference point is needed.
+	goodSorter.validOrPanic()
+
+	sorter = goodSorter
+	sorter.Maximize = nil
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Maximize must have values")
+
+	sorter = goodSorter
+	sorter.ReferenceOffset = []float64{0.5}
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Maximize ([true false]), Weights ([1 1]), and ReferenceOffset ([0.5]) must all have the same number of values")
+
+	sorter = goodSorter
+	sorter.ReferenceOffset = nil
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Maximize ([true false]), Weights ([1 1]), and ReferenceOffset ([]) must all have the same number of values")
+
+	sorter = goodSorter
+	sorter.ReferenceOffset = []float64{0.5, 0.0}
+	c.Check(func() { sorter.validOrPanic() }, Panics, "ReferenceOffset must be more than 0.0, the worst specimens would have no volume: [0.5 0]")
+
+	sorter = goodSorter
+	sorter.ReferenceOffset = []float64{0.5, -0.5}
+	c.Check(func() { sorter.validOrPanic() }, Panics, "ReferenceOffset must be more than 0.0, the worst specimens would have no volume: [0.5 -0.5]")
+
+	sorter = goodSorter
+	sorter.ReferenceMode = "ideal"
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Unknown hypervolume ReferenceMode: 'ideal'")
+}
+
+func (s *SorterHypervolumeIndicatorSuite) Test_SorterHypervolumeIndicator_Nadir(c *C) {
+	var sorter SorterHypervolumeIndicator = SorterHypervolumeIndicator{
+		Maximize:        []bool{true, false},
+		Weights:         []float64{1.0, 1.0},
+		IndicatorPower:  1.0,
+		ReferenceMode:   HYPERVOLUME_REFERENCE_NADIR,
+		ReferenceOffset: []float64{1.0, 0.5},
+	}
+
+	// The reference point is just past the worst of each outcome, so every specimen has volume.
+	var specimens []Specimen = []Specimen{
+		Specimen{Outcomes: []float64{1.0, 3.0}, SpeciesMemberCount: 1}, // volume: 1.0 x 0.5
+		Specimen{Outcomes: []float64{3.0, 1.0}, SpeciesMemberCount: 1}, // volume: 3.0 x 2.5
+		Specimen{Outcomes: []float64{2.0, 2.0}, SpeciesMemberCount: 1}, // volume: 2.0 x 1.5
+	}
+	var bestScore float64
+	bestScore, _, _ = sorter.Sort(specimens)
+	c.Check(bestScore, Equals, 7.5)
+	c.Check(string(sorter.GenerationDetails()), Equals, `{"ReferencePoint":[0,3.5]}`)
+
+	// Even the specimen worst in every outcome has some volume.
+	var worst specimenHypercube = newSpecimenHypercube(specimens[0], sorter.referencePoint, sorter.Maximize, sorter.Weights)
+	c.Check(worst.volume > 0.0, Equals, true)
+
+	// A static reference point is reported too.
+	sorter = SorterHypervolumeIndicator{
+		ReferencePoint: []float64{0.0, 4.0},
+		Maximize:       []bool{true, false},
+		Weights:        []float64{1.0, 1.0},
+	}
+	sorter.Sort(specimens)
+	c.Check(string(sorter.GenerationDetails()), Equals, `{"ReferencePoint":[0,4]}`)
+}
+
+func (s *SorterHypervolumeIndicatorSuite) Test_SorterHypervolumeIndicator_Normalize(c *C) {
+	var sorter SorterHypervolumeIndicator = SorterHypervolumeIndicator{
+		ReferencePoint: []float64{0.0, 10.0},
+		Maximize:       []bool{true, false},
+		Weights:        []float64{1.0, 2.0},
+		IndicatorPower: 1.0,
+		Normalize:      true,
+	}
+
+	// The best of each outcome is 1.0 from the reference point, before weights.
+	var specimens []Specimen = []Specimen{
+		Specimen{Outcomes: []float64{4.0, 8.0}, SpeciesMemberCount: 1}, // volume: 1.0 x 1.0
+		Specimen{Outcomes: []float64{2.0, 6.0}, SpeciesMemberCount: 1}, // volume: 0.5 x 2.0
+	}
+	c.Check(sorter.generationWeights(specimens, sorter.ReferencePoint), DeepEquals, []float64{0.25, 0.5})
+	var bestScore float64
+	bestScore, _, _ = sorter.Sort(specimens)
+	c.Check(bestScore, Equals, 1.0)
+
+	// Outcomes where nothing passes the reference point are left alone.
+	c.Check(sorter.generationWeights([]Specimen{Specimen{Outcomes: []float64{-1.0, 12.0}}}, sorter.ReferencePoint), DeepEquals, []float64{1.0, 2.0})
+}
@@ -0,0 +1,190 @@
+package genetic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+)
+
+// SorterLexicographic sorts multi-outcome specimens by a priority-ordered list of objectives. The first objective decides
+// the order, and only specimens tied on it are ordered by the second objective, and so on.
+//
+// Exact ties are rare with real valued outcomes, so each objective can have a tolerance. Specimens are put into bands of
+// the tolerance's width counting back from the best value in the population, and specimens in the same band are tied for
+// that objective. With a tolerance of 0.5 and a best of 10.0, the outcomes 10.0 and 9.7 are tied, and 9.4 is a band worse.
+// Banding from the best (rather than comparing specimens pairwise) keeps the order consistent: if a ties with b, and b ties
+// with c, then a ties with c.
+//
+// Like the simple sorter, each outcome is weighted by the size of the specimen's species before it is compared, so a
+// single species doesn't take over the population. A maximized outcome is divided by the number of members in the species,
+// and a minimized outcome is multiplied by it.
+//
+// The selection score is the (negative) place of the specimen in the sort, with tied specimens sharing a place, so a
+// better place is always a higher selection score.
+type SorterLexicographic struct {
+	Objectives []LexicographicObjective // The objectives in priority order, most important first.
+}
+
+// LexicographicObjective is one outcome of the lexicographic sort.
+type LexicographicObjective struct {
+	Outcome   int     // Which outcome of the specimen this objective is (the index into the outcomes).
+	Maximize  bool    // True means a higher value is more fit, false means a lower value is more fit.
+	Tolerance float64 // The width of the bands of tied values (after species weighting). If 0.0, only equal values are tied.
+}
+
+// LoadSorterLexicographicConfig loads the json filename as a new configuration.
+func LoadSorterLexicographicConfig(filename string) (SorterLexicographic, error) {
+	var err error
+	var bytes []byte
+	var sorter SorterLexicographic
+
+	log.Printf("Loading lexicographic Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SorterLexicographic{}, err
+	}
+	if err = json.Unmarshal(bytes, &sorter); err != nil {
+		return SorterLexicographic{}, err
+	}
+	sorter.validOrPanic()
+	return sorter, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SorterLexicographic) validOrPanic() {
+	if len(s.Objectives) == 0 {
+		log.Panic("Objectives must have values")
+	}
+	for _, objective := range s.Objectives {
+		if objective.Outcome < 0 {
+			log.Panicf("Objective Outcome cannot be negative: %d", objective.Outcome)
+		}
+		if objective.Tolerance < 0.0 {
+			log.Panicf("Objective Tolerance cannot be negative: %f", objective.Tolerance)
+		}
+	}
+}
+
+// Sort the specimens by each objective in turn, feasible specimens first. The best score is the best first objective's
+// outcome in the population (before species weighting).
+func (s *SorterLexicographic) Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	return sortConstrained(specimens, true, s.sortFeasible)
+}
+
+// sortFeasible sorts the specimens by each objective in turn, ignoring constraints.
+func (s *SorterLexicographic) sortFeasible(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	for _, specimen := range specimens {
+		for _, objective := range s.Objectives {
+			if objective.Outcome >= len(specimen.Outcomes) {
+				log.Panicf("Specimen outcomes (%v) must have a value for outcome %d", specimen.Outcomes, objective.Outcome)
+			}
+		}
+	}
+	if len(specimens) == 0 {
+		return bestScore, best, specimens
+	}
+
+	// Weight by species, and turn every objective into higher is better.
+	var values [][]float64 = make([][]float64, len(specimens))
+	for i, specimen := range specimens {
+		for _, objective := range s.Objectives {
+			var value float64 = specimen.Outcomes[objective.Outcome]
+			if objective.Maximize {
+				value = value / float64(specimen.SpeciesMemberCount)
+			} else {
+				value = -value * float64(specimen.SpeciesMemberCount)
+			}
+			values[i] = append(values[i], value)
+		}
+	}
+
+	// Band each objective back from the best value in the population.
+	var bands [][]float64 = make([][]float64, len(specimens))
+	for o, objective := range s.Objectives {
+		var bestValue float64 = values[0][o]
+		for i := range specimens {
+			bestValue = math.Max(bestValue, values[i][o])
+		}
+		for i := range specimens {
+			var band float64 = values[i][o]
+			if objective.Tolerance > 0.0 {
+				band = -math.Floor((bestValue - values[i][o]) / objective.Tolerance)
+			}
+			bands[i] = append(bands[i], band)
+		}
+	}
+
+	// Sort by the bands, then the values themselves for anything still tied.
+	var bySort byLexicographic = byLexicographic{specimens: specimens, bands: bands, values: values}
+	sort.Stable(bySort)
+
+	// Tied specimens share a place.
+	var place int
+	for i := range specimens {
+		if i > 0 && bySort.Less(i-1, i) {
+			place++
+		}
+		specimens[i].setSelectionScore(-float64(place))
+	}
+
+	// The best first objective, whatever the size of its species.
+	var first LexicographicObjective = s.Objectives[0]
+	var bestSpecimen Specimen = specimens[0]
+	for _, specimen := range specimens {
+		if (first.Maximize && specimen.Outcomes[first.Outcome] > bestSpecimen.Outcomes[first.Outcome]) ||
+			(!first.Maximize && specimen.Outcomes[first.Outcome] < bestSpecimen.Outcomes[first.Outcome]) {
+			bestSpecimen = specimen
+		}
+	}
+	bestScore = bestSpecimen.Outcomes[first.Outcome]
+	best = fmt.Sprintf("outcomes: %v, speciesmembercount: %d", bestSpecimen.Outcomes, bestSpecimen.SpeciesMemberCount)
+
+	// The best information of the population (may not be the specimen at the head of the list).
+	return bestScore, best, specimens
+}
+
+// IsMaximize returns true if a higher value of the first objective is fitter.
+func (s *SorterLexicographic) IsMaximize() bool { return s.Objectives[0].Maximize }
+
+// minimizedOutcomes are the outcomes of the objectives that are fitter when lower.
+func (s *SorterLexicographic) minimizedOutcomes() (outcomes []int) {
+	for _, objective := range s.Objectives {
+		if !objective.Maximize {
+			outcomes = append(outcomes, objective.Outcome)
+		}
+	}
+	return outcomes
+}
+
+// byLexicographic implements sort.Interface to sort specimens descending by their objective bands, then their objective
+// values, each in priority order. The bands and values are kept beside the specimens and higher is always better.
+// Example: sort.Stable(byLexicographic{specimens: specimens, bands: bands, values: values})
+type byLexicographic struct {
+	specimens []Specimen
+	bands     [][]float64
+	values    [][]float64
+}
+
+func (a byLexicographic) Len() int { return len(a.specimens) }
+func (a byLexicographic) Swap(i, j int) {
+	a.specimens[i], a.specimens[j] = a.specimens[j], a.specimens[i]
+	a.bands[i], a.bands[j] = a.bands[j], a.bands[i]
+	a.values[i], a.values[j] = a.values[j], a.values[i]
+}
+func (a byLexicographic) Less(i, j int) bool {
+	for o := range a.bands[i] {
+		if a.bands[i][o] != a.bands[j][o] {
+			return a.bands[i][o] > a.bands[j][o] // First by the bands.
+		}
+	}
+	for o := range a.values[i] {
+		if a.values[i][o] != a.values[j][o] {
+			return a.values[i][o] > a.values[j][o] // Second by the values.
+		}
+	}
+	return false
+}
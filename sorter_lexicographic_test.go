@@ -0,0 +1,71 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type SorterLexicographicSuite struct{}
+
+var _ = Suite(&SorterLexicographicSuite{})
+
+// Add the tests.
+
+func (s *SorterLexicographicSuite) Test_ValidOrPanic(c *C) {
+	var sorter SorterLexicographic = SorterLexicographic{Objectives: []LexicographicObjective{LexicographicObjective{Outcome: 1, Tolerance: 0.5}}}
+	sorter.validOrPanic() // Valid.
+
+	sorter.Objectives[0].Tolerance = -0.5
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Objective Tolerance cannot be negative: -0.500000")
+
+	sorter.Objectives[0].Outcome = -1
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Objective Outcome cannot be negative: -1")
+
+	sorter.Objectives = nil
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Objectives must have values")
+}
+
+func (s *SorterLexicographicSuite) Test_Sort(c *C) {
+	var sorter SorterLexicographic = SorterLexicographic{Objectives: []LexicographicObjective{
+		LexicographicObjective{Outcome: 1, Maximize: true, Tolerance: 1.0},
+		LexicographicObjective{Outcome: 0, Maximize: false},
+	}}
+	c.Check(sorter.IsMaximize(), Equals, true)
+
+	var specimens []Specimen = []Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 1, Outcomes: []float64{3.0, 10.0}},
+		Specimen{Score: 2.0, SpeciesMemberCount: 1, Outcomes: []float64{1.0, 9.5}},  // Tied with 1.0 on the first objective, better on the second.
+		Specimen{Score: 3.0, SpeciesMemberCount: 1, Outcomes: []float64{0.0, 8.9}},  // A band worse on the first objective.
+		Specimen{Score: 4.0, SpeciesMemberCount: 1, Outcomes: []float64{5.0, 10.0}}, // Tied on the first objective, worst on the second.
+		Specimen{Score: 5.0, SpeciesMemberCount: 2, Outcomes: []float64{0.0, 18.0}}, // Weighted by species to 9.0, tied with 3.0 until the values.
+		Specimen{Score: 6.0, SpeciesMemberCount: 1, Outcomes: []float64{1.0, 9.5}},  // Tied with 2.0 on everything.
+	}
+
+	var bestScore float64
+	var best string
+	var sorted []Specimen
+	bestScore, best, sorted = sorter.Sort(specimens)
+	c.Check(bestScore, Equals, 18.0) // The best first objective, not the head of the sort.
+	c.Check(best, Equals, "outcomes: [0 18], speciesmembercount: 2")
+
+	var scores []float64
+	var selectionScores []float64
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+		selectionScores = append(selectionScores, specimen.SelectionScore)
+	}
+	c.Check(scores, DeepEquals, []float64{2.0, 6.0, 1.0, 4.0, 5.0, 3.0})
+	c.Check(selectionScores, DeepEquals, []float64{0.0, 0.0, -1.0, -2.0, -3.0, -4.0})
+
+	// Without a tolerance, the first objective decides almost everything.
+	sorter.Objectives[0].Tolerance = 0.0
+	_, _, sorted = sorter.Sort(specimens)
+	scores = nil
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+	}
+	c.Check(scores, DeepEquals, []float64{1.0, 4.0, 2.0, 6.0, 5.0, 3.0})
+
+	// Every objective needs an outcome.
+	c.Check(func() { sorter.Sort([]Specimen{Specimen{Outcomes: []float64{1.0}}}) }, Panics, "Specimen outcomes ([1]) must have a value for outcome 1")
+}
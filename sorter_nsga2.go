@@ -0,0 +1,224 @@
+package genetic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+)
+
+// SorterNSGA2 sorts multi-outcome specimens by Pareto front, then by how crowded their part of the front is. The first
+// front is the specimens no other specimen dominates (is at least as good in every outcome and better in one), the second
+// front is the specimens only the first front dominates, and so on. Within a front, specimens far from their neighbors
+// (a large crowding distance) come first, keeping the front spread out.
+//
+// Each specimen's FrontRank and CrowdingDistance are set for selectors and recorders. The selection score is
+// -FrontRank plus up to 0.5 for crowding distance, so a better front is always a higher selection score.
+//
+// The NSGA-II sort ignores the score, bonus, and species size and only operates on the multi-outcomes from the scorer.
+//
+// Constraints are part of domination: a feasible specimen dominates every infeasible one, and an infeasible specimen
+// dominates another only by having a smaller violation. So every infeasible specimen is in a later front than the
+// feasible ones.
+//
+// Reference: Deb, K., Pratap, A., Agarwal, S. and Meyarivan, T. (2002) A Fast and Elitist Multiobjective Genetic
+// Algorithm: NSGA-II.
+type SorterNSGA2 struct {
+	Maximize []bool // For each outcome, true means a higher value is more fit, false means a lower value is more fit.
+}
+
+// LoadSorterNSGA2Config loads the json filename as a new configuration.
+func LoadSorterNSGA2Config(filename string) (SorterNSGA2, error) {
+	var err error
+	var bytes []byte
+	var sorter SorterNSGA2
+
+	log.Printf("Loading NSGA-II Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SorterNSGA2{}, err
+	}
+	if err = json.Unmarshal(bytes, &sorter); err != nil {
+		return SorterNSGA2{}, err
+	}
+	sorter.validOrPanic()
+	return sorter, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SorterNSGA2) validOrPanic() {
+	if len(s.Maximize) == 0 {
+		log.Panic("Maximize must have values")
+	}
+}
+
+// Sort the specimens by front, then crowding distance. The best score is how many specimens are in the first front.
+func (s *SorterNSGA2) Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	for _, specimen := range specimens {
+		if len(specimen.Outcomes) != len(s.Maximize) {
+			log.Panicf("Specimen outcomes (%v) must have %d values", specimen.Outcomes, len(s.Maximize))
+		}
+	}
+
+	// Rank the fronts, then spread out each one.
+	var fronts [][]int = s.nondominatedFronts(specimens)
+	for rank, front := range fronts {
+		s.setCrowdingDistances(specimens, front)
+		for _, i := range front {
+			specimens[i].FrontRank = rank + 1
+			var crowding float64 = specimens[i].CrowdingDistance
+			specimens[i].setSelectionScore(-float64(rank+1) + 0.5*crowding/(1.0+crowding))
+		}
+	}
+
+	// Sort by front, then crowding distance.
+	sort.Stable(byNSGA2Rank(specimens))
+
+	// The best is the size of the first front, with an extreme of it (the biggest crowding distance) as an example.
+	// Infeasible specimens are only in the first front if no one is feasible, and then there is no best.
+	if len(specimens) > 0 && !specimens[0].isFeasible() {
+		bestScore, best = noFeasibleBestScore(), _NO_FEASIBLE_BEST
+	} else if len(specimens) > 0 {
+		bestScore = float64(len(fronts[0]))
+		best = fmt.Sprintf("fronts: %d, first front: %d, outcomes: %v", len(fronts), len(fronts[0]), specimens[0].Outcomes)
+	}
+
+	return bestScore, best, specimens
+}
+
+// IsMaximize returns true. A bigger first front is a better population.
+func (s *SorterNSGA2) IsMaximize() bool { return true }
+
+// minimizedOutcomes are the outcomes that are fitter when lower.
+func (s *SorterNSGA2) minimizedOutcomes() (outcomes []int) {
+	for i, maximize := range s.Maximize {
+		if !maximize {
+			outcomes = append(outcomes, i)
+		}
+	}
+	return outcomes
+}
+
+// dominates is true if specimen a is at least as good as specimen b in every outcome, and better in at least one. If
+// either broke a constraint, only the constraints decide.
+func (s *SorterNSGA2) dominates(a Specimen, b Specimen) bool {
+	if !a.isFeasible() || !b.isFeasible() {
+		return compareFeasibility(a, b) < 0
+	}
+
+	var isBetter bool
+	for i, maximize := range s.Maximize {
+		var difference float64 = a.Outcomes[i] - b.Outcomes[i]
+		if !maximize {
+			difference = -difference
+		}
+		if difference < 0.0 {
+			return false
+		}
+		if difference > 0.0 {
+			isBetter = true
+		}
+	}
+	return isBetter
+}
+
+// nondominatedFronts sorts the specimens into fronts, as indexes into specimens. This is the fast non-dominated sort:
+// each specimen is compared with every other once, and then fronts are peeled off one at a time.
+func (s *SorterNSGA2) nondominatedFronts(specimens []Specimen) (fronts [][]int) {
+	var dominatedSpecimens [][]int = make([][]int, len(specimens)) // Who each specimen dominates.
+	var dominatedByCount []int = make([]int, len(specimens))       // How many specimens dominate each specimen.
+
+	var front []int
+	for i := range specimens {
+		for j := range specimens {
+			if s.dominates(specimens[i], specimens[j]) {
+				dominatedSpecimens[i] = append(dominatedSpecimens[i], j)
+			} else if s.dominates(specimens[j], specimens[i]) {
+				dominatedByCount[i]++
+			}
+		}
+		if dominatedByCount[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	// Each front is the specimens only dominated by earlier fronts.
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var nextFront []int
+		for _, i := range front {
+			for _, j := range dominatedSpecimens[i] {
+				dominatedByCount[j]--
+				if dominatedByCount[j] == 0 {
+					nextFront = append(nextFront, j)
+				}
+			}
+		}
+		front = nextFront
+	}
+	return fronts
+}
+
+// setCrowdingDistances sets how far each specimen of a front is from its neighbors. For each outcome, the front is ordered
+// and each specimen gets the (normalized) distance between the specimens on either side of it. The specimens at the ends
+// of the front are as far as can be, math.MaxFloat64 (infinity would not survive being recorded as json).
+func (s *SorterNSGA2) setCrowdingDistances(specimens []Specimen, front []int) {
+	for _, i := range front {
+		specimens[i].CrowdingDistance = 0.0
+	}
+
+	var ordered []int = append([]int{}, front...)
+	for outcome := range s.Maximize {
+		sort.Stable(byNSGA2Outcome{specimens: specimens, indexes: ordered, outcome: outcome})
+
+		var first *Specimen = &specimens[ordered[0]]
+		var last *Specimen = &specimens[ordered[len(ordered)-1]]
+		first.CrowdingDistance = math.MaxFloat64
+		last.CrowdingDistance = math.MaxFloat64
+
+		// All the same? Nothing to spread out.
+		var outcomeRange float64 = last.Outcomes[outcome] - first.Outcomes[outcome]
+		if outcomeRange == 0.0 {
+			continue
+		}
+
+		for k := 1; k < len(ordered)-1; k++ {
+			var specimen *Specimen = &specimens[ordered[k]]
+			if specimen.CrowdingDistance == math.MaxFloat64 {
+				continue
+			}
+			var distance float64 = (specimens[ordered[k+1]].Outcomes[outcome] - specimens[ordered[k-1]].Outcomes[outcome]) / outcomeRange
+			specimen.CrowdingDistance = math.Min(specimen.CrowdingDistance+distance, math.MaxFloat64)
+		}
+	}
+}
+
+// byNSGA2Rank implements sort.Interface to sort by front rank ascending, then crowding distance descending.
+// Example: sort.Stable(byNSGA2Rank(specimens))
+type byNSGA2Rank []Specimen
+
+func (a byNSGA2Rank) Len() int      { return len(a) }
+func (a byNSGA2Rank) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byNSGA2Rank) Less(i, j int) bool {
+	if a[i].FrontRank == a[j].FrontRank {
+		return a[i].CrowdingDistance > a[j].CrowdingDistance // Second by crowding distance.
+	}
+	return a[i].FrontRank < a[j].FrontRank // First by front.
+}
+
+// byNSGA2Outcome implements sort.Interface to sort indexes of specimens ascending by one outcome.
+// Example: sort.Stable(byNSGA2Outcome{specimens: specimens, indexes: front, outcome: 0})
+type byNSGA2Outcome struct {
+	specimens []Specimen
+	indexes   []int
+	outcome   int
+}
+
+func (a byNSGA2Outcome) Len() int      { return len(a.indexes) }
+func (a byNSGA2Outcome) Swap(i, j int) { a.indexes[i], a.indexes[j] = a.indexes[j], a.indexes[i] }
+func (a byNSGA2Outcome) Less(i, j int) bool {
+	return a.specimens[a.indexes[i]].Outcomes[a.outcome] < a.specimens[a.indexes[j]].Outcomes[a.outcome]
+}
@@ -0,0 +1,101 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math"
+)
+
+// Create a suite.
+type SorterNSGA2Suite struct{}
+
+var _ = Suite(&SorterNSGA2Suite{})
+
+// Add the tests.
+
+func (s *SorterNSGA2Suite) Test_ValidOrPanic(c *C) {
+	var sorter SorterNSGA2 = SorterNSGA2{Maximize: []bool{true}}
+	sorter.validOrPanic() // Valid.
+
+	sorter.Maximize = nil
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Maximize must have values")
+}
+
+func (s *SorterNSGA2Suite) Test_Dominates(c *C) {
+	var sorter SorterNSGA2 = SorterNSGA2{Maximize: []bool{true, false}}
+	c.Check(sorter.dominates(Specimen{Outcomes: []float64{2.0, 1.0}}, Specimen{Outcomes: []float64{1.0, 2.0}}), Equals, true)
+	c.Check(sorter.dominates(Specimen{Outcomes: []float64{2.0, 2.0}}, Specimen{Outcomes: []float64{1.0, 2.0}}), Equals, true)
+	c.Check(sorter.dominates(Specimen{Outcomes: []float64{1.0, 2.0}}, Specimen{Outcomes: []float64{1.0, 2.0}}), Equals, false) // Equal is not better.
+	c.Check(sorter.dominates(Specimen{Outcomes: []float64{2.0, 3.0}}, Specimen{Outcomes: []float64{1.0, 2.0}}), Equals, false) // A trade off.
+}
+
+func (s *SorterNSGA2Suite) Test_Sort(c *C) {
+	var sorter SorterNSGA2 = SorterNSGA2{Maximize: []bool{true, true}}
+
+	// Four on the first front, then one each on two more fronts.
+	var specimens []Specimen = []Specimen{
+		Specimen{Score: 1.0, Outcomes: []float64{1.0, 5.0}},
+		Specimen{Score: 2.0, Outcomes: []float64{2.0, 4.0}},
+		Specimen{Score: 3.0, Outcomes: []float64{3.0, 3.0}},
+		Specimen{Score: 4.0, Outcomes: []float64{4.0, 1.0}},
+		Specimen{Score: 5.0, Outcomes: []float64{1.0, 1.0}},
+		Specimen{Score: 6.0, Outcomes: []float64{2.0, 2.0}},
+	}
+
+	var bestScore float64
+	var best string
+	var sorted []Specimen
+	bestScore, best, sorted = sorter.Sort(specimens)
+	c.Check(bestScore, Equals, 4.0)
+	c.Check(best, Equals, "fronts: 3, first front: 4, outcomes: [1 5]")
+
+	// The ends of the first front, then the least crowded, then the next fronts.
+	var scores []float64
+	var frontRanks []int
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+		frontRanks = append(frontRanks, specimen.FrontRank)
+	}
+	c.Check(scores, DeepEquals, []float64{1.0, 4.0, 3.0, 2.0, 6.0, 5.0})
+	c.Check(frontRanks, DeepEquals, []int{1, 1, 1, 1, 2, 3})
+
+	// Crowding distances are normalized by each outcome's range.
+	c.Check(sorted[0].CrowdingDistance, Equals, math.MaxFloat64)
+	c.Check(isNearlyEqual(sorted[2].CrowdingDistance, 2.0/3.0+3.0/4.0), Equals, true)
+	c.Check(isNearlyEqual(sorted[3].CrowdingDistance, 2.0/3.0+2.0/4.0), Equals, true)
+	c.Check(sorted[4].CrowdingDistance, Equals, math.MaxFloat64) // Alone on its front.
+
+	// Selection scores keep fronts apart.
+	c.Check(isNearlyEqual(sorted[0].SelectionScore, -0.5), Equals, true)
+	c.Check(isNearlyEqual(sorted[2].SelectionScore, -1.0+0.5*(17.0/12.0)/(29.0/12.0)), Equals, true)
+	c.Check(isNearlyEqual(sorted[4].SelectionScore, -1.5), Equals, true)
+	for i := 1; i < len(sorted); i++ {
+		c.Check(sorted[i-1].SelectionScore >= sorted[i].SelectionScore, Equals, true)
+	}
+
+	// Minimizing an outcome flips who dominates.
+	sorter = SorterNSGA2{Maximize: []bool{false, false}}
+	bestScore, _, sorted = sorter.Sort(sorted)
+	c.Check(bestScore, Equals, 1.0)
+	c.Check(sorted[0].Outcomes, DeepEquals, []float64{1.0, 1.0})
+
+	// Outcomes must match.
+	c.Check(func() { sorter.Sort([]Specimen{Specimen{Outcomes: []float64{1.0}}}) }, Panics, "Specimen outcomes ([1]) must have 2 values")
+
+	// Nothing to sort.
+	bestScore, best, sorted = sorter.Sort(nil)
+	c.Check(bestScore, Equals, 0.0)
+	c.Check(best, Equals, "")
+	c.Check(len(sorted), Equals, 0)
+}
+
+func (s *SorterNSGA2Suite) Test_Dominates_Constraints(c *C) {
+	var sorter SorterNSGA2 = SorterNSGA2{Maximize: []bool{true}}
+	var feasible Specimen = Specimen{Outcomes: []float64{1.0}}
+	var slightly Specimen = Specimen{Outcomes: []float64{5.0}, ConstraintViolation: 0.5}
+	var badly Specimen = Specimen{Outcomes: []float64{9.0}, ConstraintViolation: 4.0}
+	c.Check(sorter.dominates(feasible, slightly), Equals, true) // Feasible beats any outcome.
+	c.Check(sorter.dominates(slightly, feasible), Equals, false)
+	c.Check(sorter.dominates(slightly, badly), Equals, true) // Then the smaller violation.
+	c.Check(sorter.dominates(badly, slightly), Equals, false)
+	c.Check(sorter.dominates(badly, badly), Equals, false)
+}
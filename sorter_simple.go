@@ -28,8 +28,13 @@ func NewSorterSimpleMinimize() Sorter {
 	}
 }
 
-// Sort the specimens either ascending or descending.
+// Sort the specimens either ascending or descending, feasible specimens first.
 func (s *sorterSimple) Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	return sortConstrained(specimens, s.Maximize, s.sortFeasible)
+}
+
+// sortFeasible sorts the specimens either ascending or descending, ignoring constraints.
+func (s *sorterSimple) sortFeasible(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
 
 	// Give each specimen a selection score.
 	var bestSpecimen *Specimen
@@ -82,6 +87,9 @@ func (s *sorterSimple) Sort(specimens []Specimen) (bestScore float64, best strin
 // IsMaximize returns true if we higher scores are fitter and false if lower scores are fitter.
 func (s *sorterSimple) IsMaximize() bool { return s.Maximize }
 
+// isLowerSelectionFitter is true when minimizing, the selection scores run the same way as the scores.
+func (s *sorterSimple) isLowerSelectionFitter() bool { return !s.Maximize }
+
 // bySimpleSortAscending implements sort.Interface to sort ascending by (score + bonus) x (# members in species).
 // Example: sort.Sort(bySimpleSortAscending(specimens))
 type bySimpleSortAscending []Specimen
@@ -0,0 +1,176 @@
+package genetic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+)
+
+// SorterWeightedSum sorts multi-outcome specimens by a weighted sum of their outcomes. Minimized outcomes count against
+// the sum, so a higher sum is always fitter.
+//
+// Outcomes on very different scales (a distance in the thousands and an error under one) swamp each other in a sum. With
+// Normalize, each outcome is first scaled across the population so the worst value is 0.0 and the best is 1.0, and the
+// weights alone decide how much each outcome matters. Normalizing only decides the order. The best score is still the raw
+// weighted sum, so it can be compared from one generation to the next.
+//
+// Like the simple sorter, the sum is weighted by the size of the specimen's species, so a single species doesn't take over
+// the population. The sum is divided by the number of members in the species (or multiplied, if the sum is negative, so a
+// larger species is always worse).
+type SorterWeightedSum struct {
+	Weights   []float64 // For each outcome, what relative value does it have? A higher value means it will have more influence on the sort.
+	Maximize  []bool    // For each outcome, true means a higher value is more fit, false means a lower value is more fit.
+	Normalize bool      // True scales each outcome to between 0.0 (the worst in the population) and 1.0 (the best) before weighting.
+}
+
+// LoadSorterWeightedSumConfig loads the json filename as a new configuration.
+func LoadSorterWeightedSumConfig(filename string) (SorterWeightedSum, error) {
+	var err error
+	var bytes []byte
+	var sorter SorterWeightedSum
+
+	log.Printf("Loading weighted sum Config: '%s'\n", filename)
+
+	// Load and parse from json.
+	if bytes, err = ioutil.ReadFile(filename); err != nil {
+		return SorterWeightedSum{}, err
+	}
+	if err = json.Unmarshal(bytes, &sorter); err != nil {
+		return SorterWeightedSum{}, err
+	}
+	sorter.validOrPanic()
+	return sorter, error(nil)
+}
+
+// validOrPanic panics if we're not ready for use.
+func (s *SorterWeightedSum) validOrPanic() {
+	if len(s.Weights) == 0 {
+		log.Panic("Weights must have values")
+	}
+	if len(s.Weights) != len(s.Maximize) {
+		log.Panicf("Weights (%v) and Maximize (%v) must have the same number of values", s.Weights, s.Maximize)
+	}
+	for _, weight := range s.Weights {
+		if weight < 0.0 {
+			log.Panicf("Weights cannot be negative: %v", s.Weights)
+		}
+	}
+}
+
+// Sort the specimens descending by their weighted sum, feasible specimens first. The best score is the highest raw weighted
+// sum (before normalizing or species weighting). Infeasible specimens take no part in the normalization of the feasible ones.
+func (s *SorterWeightedSum) Sort(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	return sortConstrained(specimens, true, s.sortFeasible)
+}
+
+// sortFeasible sorts the specimens descending by their weighted sum, ignoring constraints.
+func (s *SorterWeightedSum) sortFeasible(specimens []Specimen) (bestScore float64, best string, sorted []Specimen) {
+	for _, specimen := range specimens {
+		if len(specimen.Outcomes) != len(s.Weights) {
+			log.Panicf("Specimen outcomes (%v) must have %d values", specimen.Outcomes, len(s.Weights))
+		}
+	}
+	if len(specimens) == 0 {
+		return bestScore, best, specimens
+	}
+
+	// The range of each outcome for normalizing.
+	var lows []float64 = append([]float64{}, specimens[0].Outcomes...)
+	var highs []float64 = append([]float64{}, specimens[0].Outcomes...)
+	for _, specimen := range specimens {
+		for i, outcome := range specimen.Outcomes {
+			lows[i] = math.Min(lows[i], outcome)
+			highs[i] = math.Max(highs[i], outcome)
+		}
+	}
+
+	// Give each specimen a selection score.
+	var sums []float64 = make([]float64, len(specimens))
+	var rawSums []float64 = make([]float64, len(specimens))
+	var bestIndex int
+	for i := range specimens {
+		sums[i] = s.weightedSum(specimens[i].Outcomes, lows, highs, s.Normalize)
+		rawSums[i] = s.weightedSum(specimens[i].Outcomes, lows, highs, false)
+
+		// Value gets worse if specimen is in a larger species.
+		var selectionScore float64 = sums[i]
+		if selectionScore >= 0.0 {
+			selectionScore = selectionScore / float64(specimens[i].SpeciesMemberCount)
+		} else {
+			selectionScore = selectionScore * float64(specimens[i].SpeciesMemberCount)
+		}
+		specimens[i].setSelectionScore(selectionScore)
+
+		// Is this the best specimen we've found?
+		if rawSums[i] > rawSums[bestIndex] {
+			bestIndex = i
+		}
+	}
+
+	// What is the text summary of the best specimen.
+	bestScore = rawSums[bestIndex]
+	best = fmt.Sprintf("weighted sum: %f, speciesmembercount: %d, outcomes: %v", bestScore, specimens[bestIndex].SpeciesMemberCount, specimens[bestIndex].Outcomes)
+
+	// Sort descending by selection score, then weighted sum.
+	sort.Stable(byWeightedSumDescending{specimens: specimens, sums: sums})
+
+	// The best information of the population (may not be the specimen at the head of the list).
+	return bestScore, best, specimens
+}
+
+// IsMaximize returns true. A higher weighted sum is fitter.
+func (s *SorterWeightedSum) IsMaximize() bool { return true }
+
+// minimizedOutcomes are the outcomes that are fitter when lower.
+func (s *SorterWeightedSum) minimizedOutcomes() (outcomes []int) {
+	for i, maximize := range s.Maximize {
+		if !maximize {
+			outcomes = append(outcomes, i)
+		}
+	}
+	return outcomes
+}
+
+// weightedSum is the sum of the weighted outcomes, normalized to the lows and highs of the population if asked.
+func (s *SorterWeightedSum) weightedSum(outcomes []float64, lows []float64, highs []float64, isNormalize bool) (sum float64) {
+	for i, outcome := range outcomes {
+		var value float64 = outcome
+		if isNormalize {
+			// An outcome that is the same for everyone says nothing about who is better.
+			value = 0.0
+			if highs[i] > lows[i] {
+				value = (outcome - lows[i]) / (highs[i] - lows[i])
+				if !s.Maximize[i] {
+					value = 1.0 - value
+				}
+			}
+		} else if !s.Maximize[i] {
+			value = -value
+		}
+		sum += s.Weights[i] * value
+	}
+	return sum
+}
+
+// byWeightedSumDescending implements sort.Interface to sort specimens descending by selection score, then weighted sum.
+// The weighted sums are kept beside the specimens.
+// Example: sort.Stable(byWeightedSumDescending{specimens: specimens, sums: sums})
+type byWeightedSumDescending struct {
+	specimens []Specimen
+	sums      []float64
+}
+
+func (a byWeightedSumDescending) Len() int { return len(a.specimens) }
+func (a byWeightedSumDescending) Swap(i, j int) {
+	a.specimens[i], a.specimens[j] = a.specimens[j], a.specimens[i]
+	a.sums[i], a.sums[j] = a.sums[j], a.sums[i]
+}
+func (a byWeightedSumDescending) Less(i, j int) bool {
+	if a.specimens[i].SelectionScore == a.specimens[j].SelectionScore {
+		return a.sums[i] > a.sums[j] // Second by weighted sum.
+	}
+	return a.specimens[i].SelectionScore > a.specimens[j].SelectionScore // First by selection score.
+}
@@ -0,0 +1,95 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+)
+
+// Create a suite.
+type SorterWeightedSumSuite struct{}
+
+var _ = Suite(&SorterWeightedSumSuite{})
+
+// sorterWeightedSumTestSpecimens are specimens with two outcomes, the first maximized and the second minimized.
+func sorterWeightedSumTestSpecimens() []Specimen {
+	return []Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 1, Outcomes: []float64{3.0, 1.0}},
+		Specimen{Score: 2.0, SpeciesMemberCount: 1, Outcomes: []float64{5.0, 1.0}},
+		Specimen{Score: 3.0, SpeciesMemberCount: 1, Outcomes: []float64{1.0, 2.0}},
+		Specimen{Score: 4.0, SpeciesMemberCount: 2, Outcomes: []float64{5.0, 1.0}},
+		Specimen{Score: 5.0, SpeciesMemberCount: 2, Outcomes: []float64{1.0, 2.0}},
+	}
+}
+
+// Add the tests.
+
+func (s *SorterWeightedSumSuite) Test_ValidOrPanic(c *C) {
+	var sorter SorterWeightedSum = SorterWeightedSum{Weights: []float64{1.0, 2.0}, Maximize: []bool{true, false}}
+	sorter.validOrPanic() // Valid.
+
+	sorter.Weights = []float64{1.0, -2.0}
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Weights cannot be negative: [1 -2]")
+
+	sorter.Weights = []float64{1.0}
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Weights ([1]) and Maximize ([true false]) must have the same number of values")
+
+	sorter.Weights = nil
+	c.Check(func() { sorter.validOrPanic() }, Panics, "Weights must have values")
+}
+
+func (s *SorterWeightedSumSuite) Test_Sort(c *C) {
+	var sorter SorterWeightedSum = SorterWeightedSum{Weights: []float64{1.0, 2.0}, Maximize: []bool{true, false}}
+	c.Check(sorter.IsMaximize(), Equals, true)
+
+	var bestScore float64
+	var best string
+	var sorted []Specimen
+	bestScore, best, sorted = sorter.Sort(sorterWeightedSumTestSpecimens())
+	c.Check(bestScore, Equals, 3.0)
+	c.Check(best, Equals, "weighted sum: 3.000000, speciesmembercount: 1, outcomes: [5 1]")
+
+	// Larger species are worse whether the sum is positive or negative.
+	var scores []float64
+	var selectionScores []float64
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+		selectionScores = append(selectionScores, specimen.SelectionScore)
+	}
+	c.Check(scores, DeepEquals, []float64{2.0, 4.0, 1.0, 3.0, 5.0})
+	c.Check(selectionScores, DeepEquals, []float64{3.0, 1.5, 1.0, -3.0, -6.0})
+
+	// Every outcome needs a weight.
+	c.Check(func() { sorter.Sort([]Specimen{Specimen{Outcomes: []float64{1.0}}}) }, Panics, "Specimen outcomes ([1]) must have 2 values")
+}
+
+func (s *SorterWeightedSumSuite) Test_Sort_Normalize(c *C) {
+	var sorter SorterWeightedSum = SorterWeightedSum{Weights: []float64{1.0, 2.0}, Maximize: []bool{true, false}, Normalize: true}
+
+	var bestScore float64
+	var sorted []Specimen
+	bestScore, _, sorted = sorter.Sort(sorterWeightedSumTestSpecimens())
+	c.Check(bestScore, Equals, 3.0)
+
+	var scores []float64
+	var selectionScores []float64
+	for _, specimen := range sorted {
+		scores = append(scores, specimen.Score)
+		selectionScores = append(selectionScores, specimen.SelectionScore)
+	}
+	c.Check(scores, DeepEquals, []float64{2.0, 1.0, 4.0, 3.0, 5.0})
+	c.Check(selectionScores, DeepEquals, []float64{3.0, 2.5, 1.5, 0.0, 0.0})
+
+	// An outcome the same for everyone adds nothing.
+	_, _, sorted = sorter.Sort([]Specimen{
+		Specimen{Score: 1.0, SpeciesMemberCount: 1, Outcomes: []float64{1.0, 7.0}},
+		Specimen{Score: 2.0, SpeciesMemberCount: 1, Outcomes: []float64{2.0, 7.0}},
+	})
+	c.Check(sorted[0].SelectionScore, Equals, 1.0)
+	c.Check(sorted[1].SelectionScore, Equals, 0.0)
+
+	// The best score is the raw weighted sum, not the normalized one, so generations can be compared.
+	bestScore, _, _ = sorter.Sort([]Specimen{
+		Specimen{SpeciesMemberCount: 1, Outcomes: []float64{10.0, 1.0}},
+		Specimen{SpeciesMemberCount: 1, Outcomes: []float64{0.0, 1.0}},
+	})
+	c.Check(bestScore, Equals, 8.0)
+}
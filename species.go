@@ -59,8 +59,9 @@ func (s *genSpecies) pickSpecimen(populationSpecimenIndex int) (specimen Specime
 	return s.Specimens[localIndex], s.Specimens, localIndex, true
 }
 
-// WeightSpecies adds to the specimens enough information to weigh by species in scoring.
-func (s *genSpecies) WeightSpecies() {
+// WeightSpecies adds to the specimens enough information to weigh by species in scoring, and which species they are in
+// for species-aware selectors.
+func (s *genSpecies) WeightSpecies(speciesId int) {
 
 	// How many specimens are there?
 	var specimenCount int = len(s.Specimens)
@@ -68,5 +69,6 @@ func (s *genSpecies) WeightSpecies() {
 		// The more specimens in the species, the worse the score. Keeps one species from
 		// taking over the whole population.
 		s.Specimens[i].SpeciesMemberCount = specimenCount
+		s.Specimens[i].SpeciesId = speciesId
 	}
 }
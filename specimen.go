@@ -15,13 +15,23 @@ const (
 
 // Specimen is a single member of a population, scored.
 type Specimen struct {
-	NeuralNet          NeatNeuralNet // The neural net that defines this specimen.
-	Score              float64       // The score of the neural net for selectors that use it. 0.0 if unused.
-	Bonus              float64       // The bonus for meta-qualiteis (e.g. novelty searches). 0.0 if unused.
-	Outcomes           []float64     // Multi-outcomes for selectors that use it (e.g. hypervolume indicator). null if unused.
-	SelectionScore     float64       // This is the score the specimen will ultimately be sorted on before being passed to the Selector.
-	SpeciationDistance float64       // How different this specimen's genome is from the species identity genome.
-	SpeciesMemberCount int           // How many specimens are in this specimen's species (including itself).
+	NeuralNet           NeatNeuralNet // The neural net that defines this specimen.
+	Score               float64       // The score of the neural net for selectors that use it. 0.0 if unused.
+	Bonus               float64       // The bonus for meta-qualiteis (e.g. novelty searches). 0.0 if unused.
+	Outcomes            []float64     // Multi-outcomes for selectors that use it (e.g. hypervolume indicator). null if unused.
+	Behavior            []float64     // The behavior characterization for novelty searches. null if unused.
+	Novelty             float64       // How different the behavior was from its nearest neighbors (already in the bonus or outcomes). 0.0 if unused.
+	SelectionScore      float64       // This is the score the specimen will ultimately be sorted on before being passed to the Selector.
+	SpeciationDistance  float64       // How different this specimen's genome is from the species identity genome.
+	SpeciesMemberCount  int           // How many specimens are in this specimen's species (including itself).
+	SpeciesId           int           // Which species the specimen is in this generation, numbered from 1. 0 if not yet weighted by species.
+	FrontRank           int           // For Pareto sorters (e.g. NSGA-II), which front the specimen is in, 1 being the best. 0 if unused.
+	CrowdingDistance    float64       // For Pareto sorters, how far the specimen is from its neighbors in its front. 0.0 if unused.
+	ConstraintViolation float64       // How badly the specimen broke the scorer's hard constraints. 0.0 if feasible (or unused).
+	Age                 int           // The genetic age, how many generations its oldest genetic material has been evolving. 0 if new.
+	Evaluations         int           // For steady-state experiments, how many times the specimen has been scored. 0 if new.
+	Trials              int           // How many trials the score is from, if scored in trials (see ConfigScoring). 0 otherwise.
+	ScoreVariance       float64       // How much the score varied between its trials. 0.0 if scored in a single trial.
 }
 
 // newSpecimen creates a well-formed member of the population.
@@ -37,6 +47,16 @@ func newSpecimen(neuralNet NeatNeuralNet, score float64, bonus float64, outcomes
 	}
 }
 
+// newScoredSpecimen creates a well-formed member of the population from everything the scorer reported.
+func newScoredSpecimen(neuralNet NeatNeuralNet, result ScoreResult) Specimen {
+	var specimen Specimen = newSpecimen(neuralNet, result.Score, result.Bonus, result.Outcomes)
+	specimen.Behavior = result.Behavior
+	specimen.ConstraintViolation = result.ConstraintViolation
+	specimen.Trials = result.Trials
+	specimen.ScoreVariance = result.ScoreVariance
+	return specimen
+}
+
 // setSelectionScore updates the selection score for the specimen.
 func (s *Specimen) setSelectionScore(selectionScore float64) { s.SelectionScore = selectionScore }
 
@@ -57,7 +77,9 @@ func (s *Specimen) mateMutate(speciesSpecimens []Specimen, specimenIndex int, co
 	}
 
 	// Pick the type of change we're going to make. Then make it.
+	// The new specimen is as old as its oldest parent.
 	var newNeuralNet NeatNeuralNet
+	var age int = s.Age
 	var changeType int = randomMateMutatePick(mateWeight, addNodeWeight, addConnectionWeight, alterConnectionWeight)
 	switch changeType {
 
@@ -66,6 +88,9 @@ func (s *Specimen) mateMutate(speciesSpecimens []Specimen, specimenIndex int, co
 		var fitterParent Specimen = *s // Assume this is the fitter parent.
 		var otherParent Specimen = randomSpecimenWithSkip(speciesSpecimens, specimenIndex)
 		newNeuralNet = mate(fitterParent.NeuralNet, otherParent.NeuralNet)
+		if otherParent.Age > age {
+			age = otherParent.Age
+		}
 
 	case _CHANGE_MUTATE_ADD_NODE:
 		newNeuralNet = s.NeuralNet.makeClone()
@@ -88,7 +113,9 @@ func (s *Specimen) mateMutate(speciesSpecimens []Specimen, specimenIndex int, co
 	}
 
 	// The new member of the population.
-	return newSpecimen(newNeuralNet, 0.0, 0.0, nil) // No scores
+	var child Specimen = newSpecimen(newNeuralNet, 0.0, 0.0, nil) // No scores
+	child.Age = age
+	return child
 }
 
 // randomMateMutatePick randomly selects the kind of change we want to make to create a new member of the population.
@@ -64,13 +64,18 @@ DROP TABLE IF EXISTS `experiment_generation`;
 
 CREATE TABLE `experiment_generation` (
   `experimentid` int(11) unsigned NOT NULL,
+  `island` int(11) unsigned NOT NULL DEFAULT '0',
   `generation_num` bigint(11) unsigned NOT NULL,
   `datetime` datetime NOT NULL,
   `best_experiment_score` double NOT NULL,
   `stagnant_generations` int(11) NOT NULL,
   `best` varchar(512) NOT NULL DEFAULT '',
   `details` blob NOT NULL,
-  PRIMARY KEY (`experimentid`,`generation_num`)
+  `sorter_details` blob,
+  `feasible_specimens` int(11) NOT NULL DEFAULT '0',
+  `violation_min` double NOT NULL DEFAULT '0',
+  `violation_total` double NOT NULL DEFAULT '0',
+  PRIMARY KEY (`experimentid`,`island`,`generation_num`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 
@@ -82,12 +87,31 @@ DROP TABLE IF EXISTS `experiment_generation_species`;
 
 CREATE TABLE `experiment_generation_species` (
   `experimentid` int(11) unsigned NOT NULL,
+  `island` int(11) unsigned NOT NULL DEFAULT '0',
   `generation_num` bigint(11) unsigned NOT NULL,
   `species_fingerprint` char(32) NOT NULL DEFAULT '',
   `specimens` bigint(20) unsigned NOT NULL,
-  `best_score` double NOT NULL,
+  `best_score` double DEFAULT NULL,
   `best` varchar(512) NOT NULL DEFAULT '',
-  PRIMARY KEY (`experimentid`,`generation_num`,`species_fingerprint`)
+  PRIMARY KEY (`experimentid`,`island`,`generation_num`,`species_fingerprint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
+
+# Dump of table experiment_hall_of_fame
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `experiment_hall_of_fame`;
+
+CREATE TABLE `experiment_hall_of_fame` (
+  `experimentid` int(11) unsigned NOT NULL,
+  `island` int(11) unsigned NOT NULL DEFAULT '0',
+  `place` int(11) unsigned NOT NULL,
+  `genome_fingerprint` char(32) NOT NULL DEFAULT '',
+  `generation_num` bigint(20) unsigned NOT NULL,
+  `score` double NOT NULL,
+  `specimen` longblob NOT NULL,
+  PRIMARY KEY (`experimentid`,`island`,`place`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 
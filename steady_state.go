@@ -0,0 +1,134 @@
+package genetic
+
+import (
+	"log"
+	"math/rand"
+)
+
+// replaceWorst restocks the population with the sorted specimens, less the worst one that has been scored at least
+// minimumEvaluations times, and adds a single offspring in its place. The offspring's species is picked by the average
+// fitness of the species, so fitter species grow. If no specimen has been scored enough, nothing is replaced.
+func replaceWorst(population *generationPopulation, sorted []Specimen, minimumEvaluations int) (wasReplaced bool) {
+
+	// Who is the worst of the specimens old enough to be judged fairly?
+	var worstIndex int = -1
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].Evaluations >= minimumEvaluations {
+			worstIndex = i
+			break
+		}
+	}
+	if worstIndex < 0 || len(sorted) < 2 {
+		population.AddAllSpecimens(sorted)
+		return false
+	}
+
+	// Everyone else survives.
+	var survivors []Specimen
+	survivors = append(survivors, sorted[:worstIndex]...)
+	survivors = append(survivors, sorted[worstIndex+1:]...)
+
+	// Fitness is by place in the sort, so any sorter will do. The fittest is worth the most. Average it over each species
+	// (as numbered when weighted by species).
+	var speciesIds []int
+	var speciesFitness map[int]float64 = map[int]float64{}
+	var speciesSpecimens map[int][]Specimen = map[int][]Specimen{}
+	for i, specimen := range survivors {
+		if len(speciesSpecimens[specimen.SpeciesId]) == 0 {
+			speciesIds = append(speciesIds, specimen.SpeciesId)
+		}
+		speciesFitness[specimen.SpeciesId] += float64(len(survivors) - i)
+		speciesSpecimens[specimen.SpeciesId] = append(speciesSpecimens[specimen.SpeciesId], specimen)
+	}
+	var weights []float64
+	for _, speciesId := range speciesIds {
+		weights = append(weights, speciesFitness[speciesId]/float64(len(speciesSpecimens[speciesId])))
+	}
+
+	// Breed a single offspring from a random member of the picked species.
+	var parents []Specimen = speciesSpecimens[speciesIds[roulettePick(weights)]]
+	var parentIndex int = rand.Intn(len(parents))
+	var child Specimen = parents[parentIndex].mateMutate(parents, parentIndex, population.config.Mutate)
+
+	// Speciate incrementally, the survivors back into their species and the offspring into whichever it fits.
+	population.AddAllSpecimens(survivors)
+	population.AddSpecimen(child)
+	return true
+}
+
+// validOrPanic panics if we're not ready for use.
+func (c *ConfigSteadyState) validOrPanic() {
+	if c.ReplaceInterval < 1 {
+		log.Panicf("SteadyState ReplaceInterval must be one or more: %d", c.ReplaceInterval)
+	}
+	if c.MinimumEvaluations < 0 {
+		log.Panicf("SteadyState MinimumEvaluations cannot be negative: %d", c.MinimumEvaluations)
+	}
+}
+
+// RunSteadyState runs a steady-state (rtNEAT-style) experiment until stopped manually or an end condition is met. There
+// are no generation boundaries. The whole population is scored every evaluation (e.g. a tick of a real-time simulation),
+// and every so often the worst specimen that has been scored enough times is replaced by a single offspring (see
+// ConfigSteadyState). Everyone else carries on untouched.
+//
+// The experiment counts and records evaluations where others count generations, including for the end condition. There
+// is no selector, the replacement does the selecting.
+//
+// Reference: Stanley, K. O., Bryant, B. D., Miikkulainen, R. (2005) Real-Time Neuroevolution in the NERO Video Game.
+func RunSteadyState(experimentName string, config Config, sorter Sorter, scorer Scorer) {
+
+	// Create the experiment. There is no selector.
+	var experiment geneticExperiment = newGeneticExperiment(experimentName, config, sorter, nil, scorer)
+
+	// Ensure the interface defined in the config is valid.
+	experiment.config.NeuralNetInOut.validate()
+	experiment.config.SteadyState.validOrPanic()
+
+	// Is each specimen scored in several trials?
+	experiment.config.Scoring.validOrPanic()
+	validAggregationOrPanic(experiment.sorter, experiment.config.Scoring)
+
+	// Is novelty rewarded?
+	if experiment.config.NoveltySearch.K > 0 {
+		experiment.noveltySearch = NewNoveltySearch(experiment.config.NoveltySearch)
+	}
+
+	// Create the whole population up front, mutated from a single neural net. From here on it only changes a specimen
+	// at a time.
+	var population generationPopulation = newPopulation(experiment.config.Population)
+	population.AddNeuralNet(newNeatNeuralNet(experiment.config.NeuralNetInOut), 0.0, 0.0, nil) // The specimen has no scores.
+	population.FillOut()
+
+	// Run an evaluation of the experiment.
+	experiment.run(func(evaluationNum uint64) (result generationResult) {
+
+		// Give the scorer the champions so far, if it wants them.
+		experiment.shareHallOfFame(experiment.scorer)
+
+		// Tell the scorer that a new evaluation has started.
+		experiment.scorer.GenerationStart(evaluationNum)
+
+		// Score everyone again, then modify the scores of the specimens by the size of their species.
+		for _, specimen := range scoreSpecimens(experiment.scorer, population.DumpSpecimens(), experiment.config.Scoring, experiment.noveltySearch) {
+			population.AddSpecimen(specimen)
+		}
+		population.WeightSpecies()
+
+		// Sort the specimens to find the best, and the worst.
+		var specimens []Specimen = population.DumpSpecimens()
+		result.constraints = summarizeConstraints(specimens)
+		result.bestScore, result.best, result.sorted = experiment.sorter.Sort(specimens)
+		result.sorterBytes = sorterGenerationDetails(experiment.sorter)
+
+		// Replace the worst every so often, otherwise everyone carries on.
+		if evaluationNum%uint64(experiment.config.SteadyState.ReplaceInterval) == 0 {
+			replaceWorst(&population, result.sorted, experiment.config.SteadyState.MinimumEvaluations)
+		} else {
+			population.AddAllSpecimens(result.sorted)
+		}
+
+		result.population = population
+		result.reinjectInto = []*generationPopulation{&population}
+		return result
+	}, experiment.recordPopulationGeneration)
+}
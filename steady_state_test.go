@@ -0,0 +1,99 @@
+package genetic
+
+import (
+	. "gopkg.in/check.v1" // https://labix.org/gocheck
+	"math/rand"
+)
+
+// Create a suite.
+type SteadyStateSuite struct{}
+
+var _ = Suite(&SteadyStateSuite{})
+
+// TearDownTest puts the gene ids back, the neural nets created here use up gene ids.
+func (s *SteadyStateSuite) TearDownTest(c *C) { setMaxGeneId(0) }
+
+// steadyStateTestPopulation is a population that keeps everyone in a single species, unless told otherwise.
+func steadyStateTestPopulation(threshold float64) generationPopulation {
+	var config ConfigPopulation = populationTestConfig(4)
+	config.Speciation.Threshold = threshold
+	config.Mutate.MateWeight = 1 // Children keep the genes of their species.
+	return newPopulation(config)
+}
+
+// steadyStateScores are the scores of the population's specimens, in order.
+func steadyStateScores(population generationPopulation) (scores []float64) {
+	scores = []float64{}
+	for _, specimen := range population.allSpecimens() {
+		scores = append(scores, specimen.Score)
+	}
+	return scores
+}
+
+// Add the tests.
+
+func (s *SteadyStateSuite) Test_ReplaceWorst(c *C) {
+	rand.Seed(1)
+
+	var specimen = func(weight float64, score float64, evaluations int) Specimen {
+		var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Type: _GENE_TYPE_CONNECTION, IsEnabled: true, Weight: weight}}}}
+		return Specimen{NeuralNet: neuralNet, Score: score, Evaluations: evaluations, SpeciesId: 1}
+	}
+	var sorted []Specimen = []Specimen{specimen(0.1, 4.0, 5), specimen(0.2, 3.0, 5), specimen(0.3, 2.0, 5), specimen(0.4, 1.0, 1)}
+
+	// The worst specimen scored enough times is replaced by a new, unscored, specimen.
+	var population generationPopulation = steadyStateTestPopulation(1000.0)
+	c.Check(replaceWorst(&population, sorted, 3), Equals, true)
+	c.Check(steadyStateScores(population), DeepEquals, []float64{4.0, 3.0, 1.0, 0.0})
+	c.Check(population.allSpecimens()[3].Evaluations, Equals, 0)
+
+	// No one scored enough, no one is replaced.
+	population = steadyStateTestPopulation(1000.0)
+	c.Check(replaceWorst(&population, sorted, 10), Equals, false)
+	c.Check(steadyStateScores(population), DeepEquals, []float64{4.0, 3.0, 2.0, 1.0})
+}
+
+func (s *SteadyStateSuite) Test_ReplaceWorst_SpeciesByFitness(c *C) {
+	rand.Seed(1)
+
+	// Two species, the first much fitter than the second.
+	var specimen = func(weight float64, score float64, speciesId int) Specimen {
+		var neuralNet NeatNeuralNet = NeatNeuralNet{Genome: neatGenome{Genes: []neatGene{neatGene{GeneId: 1, Type: _GENE_TYPE_CONNECTION, IsEnabled: true, Weight: weight}}}}
+		return Specimen{NeuralNet: neuralNet, Score: score, SpeciesId: speciesId}
+	}
+	var sorted []Specimen
+	for i := 0; i < 10; i++ {
+		sorted = append(sorted, specimen(0.0, float64(100-i), 1))
+	}
+	for i := 0; i < 10; i++ {
+		sorted = append(sorted, specimen(50.0, float64(10-i), 2))
+	}
+
+	// The offspring is more often from the fitter species.
+	var fitterCount int
+	var otherCount int
+	for i := 0; i < 200; i++ {
+		var population generationPopulation = steadyStateTestPopulation(10.0)
+		replaceWorst(&population, sorted, 0)
+		c.Check(len(population.species), Equals, 2)
+		if len(population.species[0].Specimens) == 11 {
+			fitterCount++
+		} else {
+			otherCount++
+		}
+	}
+	c.Check(fitterCount > otherCount, Equals, true)
+}
+
+func (s *SteadyStateSuite) Test_ConfigSteadyState_ValidOrPanic(c *C) {
+	var config ConfigSteadyState
+
+	config = ConfigSteadyState{ReplaceInterval: 5, MinimumEvaluations: 10}
+	config.validOrPanic() // Valid.
+
+	config = ConfigSteadyState{ReplaceInterval: 0}
+	c.Check(func() { config.validOrPanic() }, Panics, "SteadyState ReplaceInterval must be one or more: 0")
+
+	config = ConfigSteadyState{ReplaceInterval: 1, MinimumEvaluations: -1}
+	c.Check(func() { config.validOrPanic() }, Panics, "SteadyState MinimumEvaluations cannot be negative: -1")
+}